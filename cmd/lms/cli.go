@@ -0,0 +1,1380 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	httpapi "github.com/mibienpanjoe/LMS-bit/internal/api/http"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/config"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/document"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/dublincore"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/label"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/marc"
+	timeutil "github.com/mibienpanjoe/LMS-bit/internal/infra/time"
+	"github.com/mibienpanjoe/LMS-bit/internal/ui/opac"
+	"github.com/mibienpanjoe/LMS-bit/internal/ui/tui"
+)
+
+const usage = `Usage: lms [command] [flags]
+
+Without a command the interactive TUI is started.
+
+Commands:
+  search [-limit N] <query>     search the catalog by title, author, ISBN, category
+  import-marc [flags] <file>    import MARC21 or MARCXML records into the catalog
+  export [flags]                export the catalog as MARC21, MARCXML or Dublin Core
+  labels [flags] [copy...]      print barcode or spine labels as a PDF or SVG sheet
+  checkout [flags] <member> <copy...>
+                                issue copies to a member and print a checkout receipt
+  eligibility <member> <copy>   explain whether a member may borrow a copy
+  renew [-override reason] <loan>
+                                renew a loan; supervisors may override the limits
+  receipt [flags] <loan...>     print a checkout or return receipt for loans
+  notices [flags] [member...]   print overdue letters, one per member
+  serve [-addr host:port] [-opac host:port]
+                                serve the JSON API under /api/v1, and the opac if asked
+  opac [-addr host:port]        serve the patron catalog and account pages
+  set-pin <member-id>           set a member's OPAC PIN (read from stdin)
+  events [-after N] [-limit N]  list recorded domain events from the outbox
+  overdue [flags]               export overdue loans grouped by member
+  report [flags] <name>         run a circulation report as a table or CSV
+  webhooks <action> [flags]     manage webhook endpoints: add, list, remove, log, deliver, retry
+  stocktake <action> [flags]    shelf check: start, list, scan, report, mark-lost, close
+  members <action> [flags]      memberships, duplicates and blocks: renew, expire, expiring,
+                                duplicates, merge, block, unblock, blocked, review-blocks
+  branches <action>             manage branches: add, list
+  transfers <action> [flags]    move copies between branches: request, ship, receive, cancel, list
+  help                          show this message
+`
+
+func runCommand(ctx context.Context, cfg config.Config, logger *slog.Logger, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	switch args[0] {
+	case "serve":
+		return runServe(ctx, cfg, logger, services, args[1:], out)
+	case "opac":
+		return runOPAC(ctx, cfg, logger, services, args[1:], out)
+	case "set-pin":
+		return runSetPIN(ctx, services, args[1:], in, out)
+	case "events":
+		return runEvents(ctx, services, args[1:], out)
+	case "overdue":
+		return runOverdue(ctx, services, args[1:], out)
+	case "report":
+		return runReport(ctx, services, args[1:], out)
+	case "webhooks":
+		return runWebhooks(ctx, services, args[1:], in, out)
+	case "stocktake":
+		return runStocktake(ctx, services, args[1:], in, out)
+	case "members":
+		return runMembers(ctx, cfg, services, args[1:], out)
+	case "branches":
+		return runBranches(ctx, services, args[1:], out)
+	case "transfers":
+		return runTransfers(ctx, services, args[1:], out)
+	case "search":
+		return runSearch(ctx, services, args[1:], out)
+	case "import-marc":
+		return runImportMARC(ctx, services, args[1:], in, out)
+	case "export":
+		return runExport(ctx, services, args[1:], out)
+	case "labels":
+		return runLabels(ctx, cfg, services, args[1:], out)
+	case "checkout":
+		return runCheckout(ctx, cfg, services, args[1:], out)
+	case "eligibility":
+		return runEligibility(ctx, services, args[1:], out)
+	case "renew":
+		return runRenew(ctx, cfg, services, args[1:], out)
+	case "receipt":
+		return runReceipt(ctx, cfg, services, args[1:], out)
+	case "notices":
+		return runNotices(ctx, cfg, services, args[1:], out)
+	case "help", "-h", "--help":
+		_, err := fmt.Fprint(out, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+}
+
+func runSearch(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	fs.SetOutput(out)
+	limit := fs.Int("limit", 20, "maximum number of results")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(q) == "" {
+		return fmt.Errorf("query is required")
+	}
+
+	books, err := services.Books.Search(ctx, q, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tAUTHORS\tISBN\tYEAR\tSTATUS")
+	for _, b := range books {
+		year := ""
+		if b.Year > 0 {
+			year = fmt.Sprintf("%d", b.Year)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", b.ID, b.Title, strings.Join(b.Authors, ", "), b.ISBN, year, b.Status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(books) == 0 {
+		fmt.Fprintln(out, "no matches")
+	}
+	return nil
+}
+
+func runImportMARC(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("import-marc", flag.ContinueOnError)
+	fs.SetOutput(out)
+	format := fs.String("format", "auto", "input format: auto, marc21 or marcxml")
+	copies := fs.Int("copies", 0, "copies to create per imported record")
+	attach := fs.Bool("attach-duplicates", false, "add copies to existing titles matched by isbn")
+	yes := fs.Bool("yes", false, "import without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one input file is required")
+	}
+	if *copies < 0 {
+		return fmt.Errorf("copies cannot be negative")
+	}
+
+	f, err := marc.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, err := marc.Read(file, f)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]dto.CreateBookInput, 0, len(records))
+	for _, rec := range records {
+		inputs = append(inputs, marc.BookInput(rec))
+	}
+
+	importer := usecase.NewImportService(services.Books, services.Copies)
+	items, err := importer.Preview(ctx, inputs)
+	if err != nil {
+		return err
+	}
+
+	counts := map[dto.ImportStatus]int{}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tSTATUS\tISBN\tTITLE\tNOTE")
+	for _, it := range items {
+		counts[it.Status]++
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", it.Index, it.Status, it.Input.ISBN, it.Input.Title, it.Problem)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%d records: %d new, %d duplicate, %d invalid\n",
+		len(items), counts[dto.ImportNew], counts[dto.ImportDuplicate], counts[dto.ImportInvalid])
+
+	if counts[dto.ImportNew] == 0 && !(*attach && counts[dto.ImportDuplicate] > 0 && *copies > 0) {
+		fmt.Fprintln(out, "nothing to import")
+		return nil
+	}
+
+	if !*yes && !confirm(in, out, "Import these records?") {
+		fmt.Fprintln(out, "import cancelled")
+		return nil
+	}
+
+	result, err := importer.Import(ctx, items, dto.ImportOptions{CopiesPerRecord: *copies, AttachDuplicates: *attach})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "imported %d books, %d copies, skipped %d\n", result.BooksCreated, result.CopiesCreated, result.Skipped)
+	for _, it := range result.Failed {
+		fmt.Fprintf(out, "record %d failed: %s\n", it.Index, it.Problem)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, services tui.Services, args []string, out io.Writer) (err error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(out)
+	format := fs.String("format", "marcxml", "output format: marc21, marcxml or dc")
+	sinceFlag := fs.String("since", "", "only titles changed after this date (YYYY-MM-DD or RFC 3339)")
+	outPath := fs.String("out", "", "write to this file instead of stdout")
+	institution := fs.String("institution", "", "holding institution code for 852 $a")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	since, err := parseSince(*sinceFlag)
+	if err != nil {
+		return err
+	}
+
+	write, err := exportWriter(*format, *institution)
+	if err != nil {
+		return err
+	}
+
+	holdings, err := usecase.NewExportService(services.Books, services.Copies).Holdings(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		return write(out, holdings)
+	}
+
+	file, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if err := write(file, holdings); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "exported %d records to %s\n", len(holdings), *outPath)
+	return nil
+}
+
+func runLabels(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) (err error) {
+	fs := flag.NewFlagSet("labels", flag.ContinueOnError)
+	fs.SetOutput(out)
+	bookID := fs.String("book", "", "every copy of this book")
+	branch := fs.String("branch", "", "every copy owned by this branch")
+	kind := fs.String("kind", string(label.KindBarcode), "label kind: barcode or spine")
+	layoutName := fs.String("layout", cfg.LabelLayout, "label stock layout")
+	format := fs.String("format", "", "output format: pdf or svg (default from -out, else pdf)")
+	outPath := fs.String("out", "", "write to this file instead of stdout")
+	listLayouts := fs.Bool("layouts", false, "list the label layouts and exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *listLayouts {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LAYOUT\tPER PAGE\tDESCRIPTION")
+		for _, l := range label.Layouts() {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", l.Name, l.PerPage(), l.Description)
+		}
+		return w.Flush()
+	}
+
+	layout, ok := label.LayoutByName(*layoutName)
+	if !ok {
+		return fmt.Errorf("unknown label layout %q (see lms labels -layouts)", *layoutName)
+	}
+	if *format == "" {
+		*format = "pdf"
+		if strings.EqualFold(filepath.Ext(*outPath), ".svg") {
+			*format = "svg"
+		}
+	}
+
+	labels, err := usecase.NewLabelService(services.Books, services.Copies).Labels(ctx, dto.LabelSelection{Copies: fs.Args(), BookID: *bookID, Branch: *branch})
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return fmt.Errorf("no copies selected")
+	}
+
+	if *outPath == "" {
+		return label.Write(out, *format, layout, label.Kind(*kind), labels)
+	}
+
+	file, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if err := label.Write(file, *format, layout, label.Kind(*kind), labels); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "wrote %d labels to %s\n", len(labels), *outPath)
+	return nil
+}
+
+// runEligibility lists every rule that would stop the member borrowing the
+// copy, the same checks checkout makes.
+func runEligibility(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: lms eligibility <member-id|card> <copy-id|barcode>")
+	}
+
+	c, err := services.Copies.GetByID(ctx, args[1])
+	if err != nil {
+		c, err = services.Copies.GetByBarcode(ctx, args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("copy %s: %w", args[1], err)
+	}
+
+	e, err := services.Loans.CheckEligibility(ctx, c.ID, args[0])
+	if err != nil {
+		return err
+	}
+
+	who := fmt.Sprintf("%s (%s)", e.Member.Name, e.Member.ID)
+	if e.Eligible() {
+		fmt.Fprintf(out, "%s may borrow %s: %d of %d loans in use\n", who, args[1], e.ActiveLoans, e.MaxLoans)
+		fmt.Fprintf(out, "(%s)\n", dto.UncheckedNote)
+		return nil
+	}
+
+	fmt.Fprintf(out, "%s may not borrow %s:\n", who, args[1])
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, v := range e.Violations {
+		fmt.Fprintf(w, "  %s\t%s\n", v.Rule, v.Detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "(%s)\n", dto.UncheckedNote)
+	return nil
+}
+
+func runCheckout(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("checkout", flag.ContinueOnError)
+	fs.SetOutput(out)
+	format := fs.String("format", "", "receipt format: text or pdf (default from -out, else text)")
+	outPath := fs.String("out", "", "write the receipt to this file instead of stdout")
+	reason := fs.String("override", "", "supervisor override of the loan limit, with its reason")
+	period := fs.String("period", "", "named loan period, such as reserve or semester")
+	dueFlag := fs.String("due", "", "due date (YYYY-MM-DD) or date and time (\"YYYY-MM-DD HH:MM\")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("usage: lms checkout [flags] <member-id> <copy-id|barcode...>")
+	}
+
+	var due time.Time
+	if *dueFlag != "" {
+		terms := loan.ParseTerms(*dueFlag, time.Local)
+		if terms.DueAt.IsZero() {
+			return fmt.Errorf("-due %q must be a date or a date and time", *dueFlag)
+		}
+		due = terms.DueAt
+	}
+
+	memberID := fs.Arg(0)
+	var loanIDs []string
+	for _, ref := range fs.Args()[1:] {
+		c, err := services.Copies.GetByID(ctx, ref)
+		if err != nil {
+			c, err = services.Copies.GetByBarcode(ctx, ref)
+		}
+		if err != nil {
+			return fmt.Errorf("copy %s: %w", ref, err)
+		}
+
+		l, err := services.Loans.Issue(ctx, dto.IssueLoanInput{CopyID: c.ID, MemberID: memberID, Period: *period, DueAt: due, Override: loanOverride(cfg, *reason)})
+		if err != nil {
+			// Loans issued so far stand; the receipt covers them.
+			if len(loanIDs) > 0 {
+				fmt.Fprintf(out, "copy %s: %v\n", ref, err)
+				break
+			}
+			return fmt.Errorf("copy %s: %w", ref, err)
+		}
+		loanIDs = append(loanIDs, l.ID)
+	}
+
+	r, err := services.Documents.Receipt(ctx, dto.ReceiptCheckout, loanIDs)
+	if err != nil {
+		return err
+	}
+	return writeDocument(out, *outPath, *format, func(w io.Writer, format string) error {
+		return document.Receipt(w, format, cfg.AppName, r)
+	})
+}
+
+// runRenew renews one loan. With -override a supervisor renews it despite
+// the renewal limit or it being overdue.
+func runRenew(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("renew", flag.ContinueOnError)
+	fs.SetOutput(out)
+	reason := fs.String("override", "", "supervisor override of the renewal rules, with its reason")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: lms renew [-override reason] <loan-id>")
+	}
+
+	l, err := services.Loans.Renew(ctx, dto.RenewLoanInput{LoanID: fs.Arg(0), Override: loanOverride(cfg, *reason)})
+	if err != nil {
+		return err
+	}
+
+	layout := "2006-01-02"
+	if l.IsShort() {
+		layout = "2006-01-02 15:04"
+	}
+	_, err = fmt.Fprintf(out, "loan %s renewed, due %s\n", l.ID, l.DueAt.Local().Format(layout))
+	return err
+}
+
+// loanOverride asks for an override as the operator when a reason is given.
+func loanOverride(cfg config.Config, reason string) dto.LoanOverride {
+	if strings.TrimSpace(reason) == "" {
+		return dto.LoanOverride{}
+	}
+	return dto.LoanOverride{By: cfg.Operator, Reason: reason}
+}
+
+func runReceipt(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("receipt", flag.ContinueOnError)
+	fs.SetOutput(out)
+	format := fs.String("format", "", "receipt format: text or pdf (default from -out, else text)")
+	outPath := fs.String("out", "", "write the receipt to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: lms receipt [flags] <loan-id...>")
+	}
+
+	// A single loan brings in the member's other loans from the same visit.
+	svc := services.Documents
+	var r dto.Receipt
+	var err error
+	if fs.NArg() == 1 {
+		r, err = svc.ReceiptFor(ctx, fs.Arg(0))
+	} else {
+		kind := dto.ReceiptCheckout
+		if l, lerr := services.Loans.GetByID(ctx, fs.Arg(0)); lerr == nil && l.ReturnedAt != nil {
+			kind = dto.ReceiptReturn
+		}
+		r, err = svc.Receipt(ctx, kind, fs.Args())
+	}
+	if err != nil {
+		return err
+	}
+	return writeDocument(out, *outPath, *format, func(w io.Writer, format string) error {
+		return document.Receipt(w, format, cfg.AppName, r)
+	})
+}
+
+func runNotices(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("notices", flag.ContinueOnError)
+	fs.SetOutput(out)
+	branch := fs.String("branch", "", "only copies owned by this branch")
+	format := fs.String("format", "", "letter format: text or pdf (default from -out, else text)")
+	outPath := fs.String("out", "", "write the letters to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	notices, err := services.Documents.OverdueNotices(ctx, strings.ToUpper(*branch), fs.Args())
+	if err != nil {
+		return err
+	}
+	if len(notices) == 0 {
+		fmt.Fprintln(out, "no overdue loans")
+		return nil
+	}
+	return writeDocument(out, *outPath, *format, func(w io.Writer, format string) error {
+		return document.OverdueNotices(w, format, cfg.AppName, notices)
+	})
+}
+
+// writeDocument renders to stdout or to outPath. Without a format, a .pdf
+// file gets a PDF and everything else plain text.
+func writeDocument(out io.Writer, outPath, format string, render func(io.Writer, string) error) (err error) {
+	if format == "" {
+		format = "text"
+		if strings.EqualFold(filepath.Ext(outPath), ".pdf") {
+			format = "pdf"
+		}
+	}
+	if outPath == "" {
+		return render(out, format)
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if err := render(file, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "wrote %s\n", outPath)
+	return nil
+}
+
+func exportWriter(format, institution string) (func(io.Writer, []dto.Holding) error, error) {
+	if strings.EqualFold(strings.TrimSpace(format), "dc") {
+		return dublincore.Write, nil
+	}
+
+	f, err := marc.ParseFormat(format)
+	if err != nil || f == marc.FormatAuto {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	return func(w io.Writer, holdings []dto.Holding) error {
+		records := make([]marc.Record, 0, len(holdings))
+		for _, h := range holdings {
+			records = append(records, marc.FromHolding(h, institution))
+		}
+		return marc.Write(w, records, f)
+	}, nil
+}
+
+func parseSince(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: use YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
+
+func runServe(ctx context.Context, cfg config.Config, logger *slog.Logger, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(out)
+	addr := fs.String("addr", cfg.HTTPAddr, "listen address")
+	opacAddr := fs.String("opac", "", "also serve the opac on this address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	api := httpapi.NewServer(httpapi.Services{
+		Books:      services.Books,
+		Copies:     services.Copies,
+		Members:    services.Members,
+		Loans:      services.Loans,
+		Transfers:  services.Transfers,
+		Duplicates: services.Duplicates,
+		Blocks:     services.Blocks,
+	}, timeutil.NewClock(), logger, cfg.Operator)
+
+	var site *opac.Server
+	if *opacAddr != "" {
+		var err error
+		if site, err = newOPAC(cfg, logger, services); err != nil {
+			return err
+		}
+	}
+
+	go runWebhookWorker(ctx, services, logger)
+	go runExpiryWorker(ctx, services, logger)
+	fmt.Fprintf(out, "serving api on http://%s/api/v1 (openapi at /api/v1/openapi.json)\n", *addr)
+	if site == nil {
+		return listen(ctx, *addr, api)
+	}
+
+	// The store can only be open in one process, so the API and the opac
+	// share this one when both are wanted.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errc := make(chan error, 2)
+	go func() { errc <- listen(ctx, *addr, api) }()
+	go func() { errc <- listen(ctx, *opacAddr, site) }()
+	fmt.Fprintf(out, "serving opac on http://%s/\n", *opacAddr)
+	return <-errc
+}
+
+func runOPAC(ctx context.Context, cfg config.Config, logger *slog.Logger, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("opac", flag.ContinueOnError)
+	fs.SetOutput(out)
+	addr := fs.String("addr", cfg.OPACAddr, "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	site, err := newOPAC(cfg, logger, services)
+	if err != nil {
+		return err
+	}
+
+	go runWebhookWorker(ctx, services, logger)
+	go runExpiryWorker(ctx, services, logger)
+	fmt.Fprintf(out, "serving opac on http://%s/\n", *addr)
+	return listen(ctx, *addr, site)
+}
+
+func newOPAC(cfg config.Config, logger *slog.Logger, services tui.Services) (*opac.Server, error) {
+	return opac.NewServer(opac.Services{
+		Books:   services.Books,
+		Copies:  services.Copies,
+		Members: services.Members,
+		Loans:   services.Loans,
+		Holds:   services.Holds,
+	}, timeutil.NewClock(), logger, []byte(cfg.OPACSecret))
+}
+
+func listen(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func runSetPIN(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("exactly one member id is required")
+	}
+
+	m, err := services.Members.GetByID(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "New PIN for %s (4-8 digits): ", m.Name)
+	pin, _ := bufio.NewReader(in).ReadString('\n')
+	if _, err := services.Members.SetPIN(ctx, m.ID, strings.TrimSpace(pin)); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "pin updated")
+	return nil
+}
+
+func runEvents(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	fs.SetOutput(out)
+	after := fs.Int64("after", 0, "only events with a sequence number above this")
+	limit := fs.Int("limit", 50, "maximum number of events")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	envelopes, err := services.Events.Since(ctx, *after, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tTIME\tTYPE\tPAYLOAD")
+	for _, env := range envelopes {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", env.Seq, env.OccurredAt.Format(time.RFC3339), env.Type, env.Payload)
+	}
+	return w.Flush()
+}
+
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func runWebhooks(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("webhooks action is required: add, list, remove, log, deliver or retry")
+	}
+
+	switch args[0] {
+	case "add":
+		return runWebhookAdd(ctx, services, args[1:], in, out)
+	case "list":
+		return runWebhookList(ctx, services, out)
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("exactly one webhook id is required")
+		}
+		if err := services.Webhooks.Remove(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "webhook removed")
+		return nil
+	case "log":
+		return runWebhookLog(ctx, services, args[1:], out)
+	case "deliver":
+		n, err := services.Webhooks.DeliverDue(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "attempted %d deliveries\n", n)
+		return nil
+	case "retry":
+		if len(args) != 2 {
+			return fmt.Errorf("exactly one delivery id is required")
+		}
+		if _, err := services.Webhooks.Retry(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "delivery queued")
+		return nil
+	default:
+		return fmt.Errorf("unknown webhooks action %q", args[0])
+	}
+}
+
+func runWebhookAdd(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("webhooks add", flag.ContinueOnError)
+	fs.SetOutput(out)
+	events := fs.String("events", "", "comma-separated event types (default: all circulation events)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one endpoint url is required")
+	}
+
+	var types []string
+	for _, t := range strings.Split(*events, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	fmt.Fprint(out, "Shared secret: ")
+	secret, _ := bufio.NewReader(in).ReadString('\n')
+	e, err := services.Webhooks.Register(ctx, dto.RegisterWebhookInput{
+		URL:    fs.Arg(0),
+		Secret: strings.TrimSpace(secret),
+		Events: types,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "webhook %s registered\n", e.ID)
+	return nil
+}
+
+func runWebhookList(ctx context.Context, services tui.Services, out io.Writer) error {
+	endpoints, err := services.Webhooks.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tURL\tEVENTS")
+	for _, e := range endpoints {
+		types := make([]string, 0, len(e.Events))
+		for _, t := range e.Events {
+			types = append(types, string(t))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.ID, e.URL, strings.Join(types, ","))
+	}
+	return w.Flush()
+}
+
+func runWebhookLog(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("webhooks log", flag.ContinueOnError)
+	fs.SetOutput(out)
+	limit := fs.Int("limit", 50, "maximum number of deliveries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	deliveries, err := services.Webhooks.Deliveries(ctx, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCREATED\tWEBHOOK\tEVENT\tSTATUS\tATTEMPTS\tLAST")
+	for _, d := range deliveries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			d.ID, d.CreatedAt.Format(time.RFC3339), d.EndpointID, d.EventType, d.Status, d.Attempts, d.Outcome())
+	}
+	return w.Flush()
+}
+
+func runReport(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fromFlag := fs.String("from", "", "only loans issued on or after this date (YYYY-MM-DD or RFC 3339)")
+	toFlag := fs.String("to", "", "only loans issued before this date (YYYY-MM-DD or RFC 3339)")
+	limit := fs.Int("limit", 20, "maximum rows for top-borrowed (0 for all)")
+	format := fs.String("format", "table", "output format: table or csv")
+	branch := fs.String("branch", "", "only copies owned by this branch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	kinds := make([]string, 0, len(dto.ReportKinds))
+	for _, k := range dto.ReportKinds {
+		kinds = append(kinds, string(k))
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one report name is required: %s", strings.Join(kinds, ", "))
+	}
+
+	from, err := parseSince(*fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseSince(*toFlag)
+	if err != nil {
+		return err
+	}
+
+	report, err := services.Reports.Table(ctx, dto.ReportKind(fs.Arg(0)), dto.ReportRange{From: from, To: to, Branch: strings.ToUpper(*branch)}, *limit)
+	if err != nil {
+		return err
+	}
+
+	return writeRows(out, *format, report.Columns, report.Rows)
+}
+
+func runOverdue(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("overdue", flag.ContinueOnError)
+	fs.SetOutput(out)
+	summary := fs.Bool("summary", false, "one row per member instead of one per loan")
+	format := fs.String("format", "table", "output format: table or csv")
+	branch := fs.String("branch", "", "only copies owned by this branch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	groups, err := services.Loans.OverdueByMember(ctx, strings.ToUpper(*branch))
+	if err != nil {
+		return err
+	}
+
+	if *summary {
+		columns := []string{"Member ID", "Name", "Email", "Phone", "Items", "Oldest Due", "1-7", "8-30", "31+"}
+		rows := make([][]string, 0, len(groups))
+		for _, g := range groups {
+			rows = append(rows, []string{
+				g.Member.ID, g.Member.Name, g.Member.Email, g.Member.Phone,
+				strconv.Itoa(len(g.Items)), g.OldestDue.Format("2006-01-02"),
+				strconv.Itoa(g.Aging.Days1To7), strconv.Itoa(g.Aging.Days8To30), strconv.Itoa(g.Aging.Days31AndOver),
+			})
+		}
+		return writeRows(out, *format, columns, rows)
+	}
+
+	columns := []string{"Member ID", "Name", "Email", "Phone", "Loan ID", "Barcode", "Due", "Days Overdue", "Aging"}
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		for _, it := range g.Items {
+			rows = append(rows, []string{
+				g.Member.ID, g.Member.Name, g.Member.Email, g.Member.Phone,
+				it.Loan.ID, it.Barcode, it.Loan.DueAt.Format("2006-01-02"),
+				strconv.Itoa(it.DaysOverdue), string(loan.AgingFor(it.DaysOverdue)),
+			})
+		}
+	}
+	return writeRows(out, *format, columns, rows)
+}
+
+func runStocktake(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("stocktake action is required: start, list, scan, report, mark-lost or close")
+	}
+
+	switch args[0] {
+	case "start":
+		fs := flag.NewFlagSet("stocktake start", flag.ContinueOnError)
+		fs.SetOutput(out)
+		branch := fs.String("branch", "", "only check copies at this branch")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		st, err := services.Stocktakes.Start(ctx, strings.Join(fs.Args(), " "), *branch)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "stocktake %s started\n", st.ID)
+		return nil
+	case "list":
+		sessions, err := services.Stocktakes.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tBRANCH\tSTARTED\tSTATUS\tSCANS")
+		for _, st := range sessions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n", st.ID, st.Name, st.Branch, st.StartedAt.Format(time.RFC3339), st.Status, len(st.Scans))
+		}
+		return w.Flush()
+	case "scan":
+		return runStocktakeScan(ctx, services, args[1:], in, out)
+	case "report":
+		return runStocktakeReport(ctx, services, args[1:], out)
+	case "mark-lost":
+		return runStocktakeMarkLost(ctx, services, args[1:], in, out)
+	case "close":
+		if len(args) != 2 {
+			return fmt.Errorf("exactly one stocktake id is required")
+		}
+		if _, err := services.Stocktakes.Close(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "stocktake closed")
+		return nil
+	default:
+		return fmt.Errorf("unknown stocktake action %q", args[0])
+	}
+}
+
+// runStocktakeScan reads one barcode per line. A line "shelf <name>" moves
+// on to the next shelf.
+func runStocktakeScan(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("stocktake scan", flag.ContinueOnError)
+	fs.SetOutput(out)
+	shelf := fs.String("shelf", "", "shelf the first barcodes are scanned on")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one stocktake id is required")
+	}
+
+	scanner := bufio.NewScanner(in)
+	count := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "shelf "); ok {
+			*shelf = strings.TrimSpace(name)
+			continue
+		}
+
+		r, err := services.Stocktakes.Scan(ctx, fs.Arg(0), *shelf, line)
+		if err != nil {
+			return err
+		}
+		count++
+
+		switch {
+		case !r.Known:
+			fmt.Fprintf(out, "%s: unknown barcode\n", r.Barcode)
+		case r.Repeat:
+			fmt.Fprintf(out, "%s: already scanned\n", r.Barcode)
+		case r.Status != copy.StatusAvailable:
+			fmt.Fprintf(out, "%s: recorded as %s\n", r.Barcode, r.Status)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d barcodes scanned\n", count)
+	return nil
+}
+
+func runStocktakeReport(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("stocktake report", flag.ContinueOnError)
+	fs.SetOutput(out)
+	format := fs.String("format", "table", "output format: table or csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one stocktake id is required")
+	}
+
+	rec, err := services.Stocktakes.Reconcile(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	add := func(finding string, items []dto.StocktakeItem) {
+		for _, it := range items {
+			rows = append(rows, []string{finding, it.Barcode, it.CopyID, it.BookID, it.Shelf})
+		}
+	}
+	add("missing", rec.Missing)
+	add("on loan", rec.OnLoan)
+	add("unknown", rec.Unknown)
+
+	if *format == "table" {
+		fmt.Fprintf(out, "%d barcodes scanned, %d matched: %d missing, %d on loan, %d unknown\n\n",
+			rec.Scanned, rec.Matched, len(rec.Missing), len(rec.OnLoan), len(rec.Unknown))
+	}
+	return writeRows(out, *format, []string{"Finding", "Barcode", "Copy ID", "Book ID", "Shelf"}, rows)
+}
+
+func runStocktakeMarkLost(ctx context.Context, services tui.Services, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("stocktake mark-lost", flag.ContinueOnError)
+	fs.SetOutput(out)
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one stocktake id is required")
+	}
+
+	st, err := services.Stocktakes.Get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if st.IsOpen() {
+		return fmt.Errorf("close stocktake %s before marking missing copies lost", st.ID)
+	}
+
+	rec, err := services.Stocktakes.Reconcile(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(rec.Missing) == 0 {
+		fmt.Fprintln(out, "no missing copies")
+		return nil
+	}
+	if !*yes && !confirm(in, out, fmt.Sprintf("Mark %d missing copies as lost?", len(rec.Missing))) {
+		fmt.Fprintln(out, "aborted")
+		return nil
+	}
+
+	marked, err := services.Stocktakes.MarkMissingLost(ctx, fs.Arg(0))
+	fmt.Fprintf(out, "%d copies marked lost\n", len(marked))
+	return err
+}
+
+func runMembers(ctx context.Context, cfg config.Config, services tui.Services, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("members action is required: renew, expire, expiring, duplicates, merge, block, unblock, blocked or review-blocks")
+	}
+
+	switch args[0] {
+	case "renew":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: lms members renew <member-id>")
+		}
+		m, err := services.Members.RenewMembership(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "membership of %s renewed until %s\n", m.ID, m.ExpiresAt.Format("2006-01-02"))
+		return nil
+	case "expire":
+		n, err := services.Members.ExpireMemberships(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%d memberships expired\n", n)
+		return nil
+	case "expiring":
+		fs := flag.NewFlagSet("members expiring", flag.ContinueOnError)
+		fs.SetOutput(out)
+		days := fs.Int("days", cfg.ExpiringDays, "memberships ending within this many days")
+		format := fs.String("format", "table", "output format: table or csv")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		items, err := services.Reports.ExpiringMembers(ctx, *days)
+		if err != nil {
+			return err
+		}
+		columns := []string{"Member ID", "Card", "Name", "Email", "Phone", "Expires", "Days Left", "Loans"}
+		rows := make([][]string, 0, len(items))
+		for _, it := range items {
+			rows = append(rows, []string{
+				it.Member.ID, it.Member.CardNumber, it.Member.Name, it.Member.Email, it.Member.Phone,
+				it.Member.ExpiresAt.Format("2006-01-02"), strconv.Itoa(it.DaysLeft), strconv.Itoa(it.ActiveLoans),
+			})
+		}
+		return writeRows(out, *format, columns, rows)
+	case "duplicates":
+		fs := flag.NewFlagSet("members duplicates", flag.ContinueOnError)
+		fs.SetOutput(out)
+		format := fs.String("format", "table", "output format: table or csv")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		dups, err := services.Duplicates.Find(ctx)
+		if err != nil {
+			return err
+		}
+		columns := []string{"Keep", "Merge", "Keep Name", "Merge Name", "Score", "Matched On"}
+		rows := make([][]string, 0, len(dups))
+		for _, d := range dups {
+			rows = append(rows, []string{
+				d.Keep.ID, d.Merge.ID, d.Keep.Name, d.Merge.Name,
+				strconv.FormatFloat(d.Score, 'f', 2, 64), strings.Join(d.Reasons, ", "),
+			})
+		}
+		return writeRows(out, *format, columns, rows)
+	case "merge":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: lms members merge <from-member-id> <into-member-id>")
+		}
+		res, err := services.Duplicates.Merge(ctx, dto.MergeMembersInput{FromID: args[1], IntoID: args[2]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "member %s merged into %s: %d loans and %d holds moved, %d duplicate holds cancelled\n",
+			res.Merged.ID, res.Member.ID, res.Loans, res.Holds, res.CancelledHolds)
+		return nil
+	case "block":
+		fs := flag.NewFlagSet("members block", flag.ContinueOnError)
+		fs.SetOutput(out)
+		reason := fs.String("reason", "", "why the member is blocked (required)")
+		note := fs.String("note", "", "staff note kept with the block")
+		until := fs.String("until", "", "lift the block on this date (YYYY-MM-DD)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: lms members block -reason <text> [-note <text>] [-until YYYY-MM-DD] <member-id>")
+		}
+
+		var expires time.Time
+		if *until != "" {
+			t, err := time.ParseInLocation("2006-01-02", *until, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid until %q: use YYYY-MM-DD", *until)
+			}
+			expires = t
+		}
+		m, err := services.Blocks.Block(ctx, dto.BlockMemberInput{
+			MemberID:  fs.Arg(0),
+			Reason:    *reason,
+			Note:      *note,
+			CreatedBy: cfg.Operator,
+			ExpiresAt: expires,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "member %s blocked: %s\n", m.ID, m.BlockReason(time.Now()))
+		return nil
+	case "unblock":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: lms members unblock <member-id>")
+		}
+		m, err := services.Blocks.Unblock(ctx, args[1], cfg.Operator)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "member %s is %s\n", m.ID, m.Status)
+		return nil
+	case "blocked":
+		fs := flag.NewFlagSet("members blocked", flag.ContinueOnError)
+		fs.SetOutput(out)
+		format := fs.String("format", "table", "output format: table or csv")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		members, err := services.Members.List(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		columns := []string{"Member ID", "Name", "Reason", "Note", "By", "Since", "Until"}
+		var rows [][]string
+		for _, m := range members {
+			if m.Status != member.StatusBlocked {
+				continue
+			}
+			if len(m.Blocks) == 0 {
+				rows = append(rows, []string{m.ID, m.Name, m.BlockReason(now), "", "", "", ""})
+			}
+			for _, b := range m.Blocks {
+				if !b.LiftedAt.IsZero() {
+					continue
+				}
+				until := ""
+				if !b.ExpiresAt.IsZero() {
+					until = b.ExpiresAt.Format("2006-01-02")
+				}
+				rows = append(rows, []string{m.ID, m.Name, b.Reason, b.Note, b.CreatedBy, b.CreatedAt.Format("2006-01-02"), until})
+			}
+		}
+		return writeRows(out, *format, columns, rows)
+	case "review-blocks":
+		r, err := services.Blocks.ReviewAll(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%d members blocked, %d unblocked\n", r.Blocked, r.Unblocked)
+		return nil
+	default:
+		return fmt.Errorf("unknown members action %q", args[0])
+	}
+}
+
+func runBranches(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("branches action is required: add or list")
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: lms branches add <code> <name>")
+		}
+		b, err := services.Branches.Add(ctx, dto.AddBranchInput{Code: args[1], Name: strings.Join(args[2:], " ")})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "branch %s added\n", b.Code)
+		return nil
+	case "list":
+		branches, err := services.Branches.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CODE\tNAME")
+		for _, b := range branches {
+			fmt.Fprintf(w, "%s\t%s\n", b.Code, b.Name)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown branches action %q", args[0])
+	}
+}
+
+func runTransfers(ctx context.Context, services tui.Services, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("transfers action is required: request, ship, receive, cancel or list")
+	}
+
+	switch args[0] {
+	case "request":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: lms transfers request <copy-id> <branch>")
+		}
+		t, err := services.Transfers.Request(ctx, dto.RequestTransferInput{CopyID: args[1], To: args[2]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "transfer %s requested: %s -> %s\n", t.ID, t.From, t.To)
+		return nil
+	case "ship", "receive", "cancel":
+		if len(args) != 2 {
+			return fmt.Errorf("exactly one transfer id is required")
+		}
+		step := map[string]func(context.Context, string) (transfer.Transfer, error){
+			"ship":    services.Transfers.Ship,
+			"receive": services.Transfers.Receive,
+			"cancel":  services.Transfers.Cancel,
+		}[args[0]]
+		t, err := step(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "transfer %s %s\n", t.ID, strings.ReplaceAll(string(t.Status), "_", " "))
+		return nil
+	case "list":
+		fs := flag.NewFlagSet("transfers list", flag.ContinueOnError)
+		fs.SetOutput(out)
+		open := fs.Bool("open", false, "only transfers not yet received or cancelled")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		transfers, err := services.Transfers.List(ctx, *open)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCOPY\tFROM\tTO\tSTATUS\tREQUESTED\tRETURN")
+		for _, t := range transfers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\n", t.ID, t.CopyID, t.From, t.To, t.Status, t.RequestedAt.Format(time.RFC3339), t.Return)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown transfers action %q", args[0])
+	}
+}
+
+func writeRows(out io.Writer, format string, columns []string, rows [][]string) error {
+	switch format {
+	case "csv":
+		w := csv.NewWriter(out)
+		if err := w.Write(columns); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	case "table":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, r := range rows {
+			fmt.Fprintln(w, strings.Join(r, "\t"))
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
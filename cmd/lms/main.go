@@ -4,69 +4,64 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/eventbus"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
 	"github.com/mibienpanjoe/LMS-bit/internal/config"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
 	"github.com/mibienpanjoe/LMS-bit/internal/infra/id"
 	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
 	timeutil "github.com/mibienpanjoe/LMS-bit/internal/infra/time"
+	webhookhttp "github.com/mibienpanjoe/LMS-bit/internal/infra/webhook"
 	"github.com/mibienpanjoe/LMS-bit/internal/logging"
 	"github.com/mibienpanjoe/LMS-bit/internal/ui/tui"
 )
 
+const (
+	webhookTimeout  = 10 * time.Second
+	webhookInterval = 5 * time.Second
+	expiryInterval  = 24 * time.Hour
+)
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	cfg := config.Load()
 	logger := logging.New(cfg.LogLevel)
-	store, err := jsonstore.Open(cfg.StoragePath)
+	services, err := newServices(cfg, logger)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "storage open error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "startup error: %v\n", err)
 		os.Exit(1)
 	}
 
-	bookRepo := jsonstore.NewBookRepository(store)
-	copyRepo := jsonstore.NewCopyRepository(store)
-	memberRepo := jsonstore.NewMemberRepository(store)
-	loanRepo := jsonstore.NewLoanRepository(store)
-
-	idGen := id.NewGenerator()
-	clock := timeutil.NewClock()
-
-	bookService := usecase.NewBookService(bookRepo, idGen)
-	copyService := usecase.NewCopyService(copyRepo, idGen)
-	memberService := usecase.NewMemberService(memberRepo, idGen, clock)
-	loanService := usecase.NewLoanService(
-		loanRepo,
-		copyRepo,
-		memberRepo,
-		idGen,
-		clock,
-		loan.Policy{
-			LoanDays:          cfg.LoanDays,
-			MaxLoansPerMember: cfg.MaxLoansPerUser,
-			MaxRenewals:       cfg.MaxLoanRenewals,
-		},
-	)
-
-	services := tui.Services{
-		Books:   bookService,
-		Copies:  copyService,
-		Members: memberService,
-		Loans:   loanService,
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, cfg, logger, services, os.Args[1:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	if err := seedInitialData(context.Background(), services); err != nil {
 		logger.Warn("seed data skipped", "error", err)
 	}
 
+	go runWebhookWorker(ctx, services, logger)
+	go runExpiryWorker(ctx, services, logger)
+
 	program := tea.NewProgram(tui.NewModel(cfg, logger, services), tea.WithAltScreen())
 
 	done := make(chan error, 1)
@@ -91,6 +86,181 @@ func main() {
 	}
 }
 
+// loanPolicy reads the loan rules, including the named loan periods and
+// the bounds on loan length. A bound of 0 is no bound.
+func loanPolicy(cfg config.Config) (loan.Policy, error) {
+	periods, err := loan.ParsePeriods(cfg.LoanPeriods)
+	if err != nil {
+		return loan.Policy{}, fmt.Errorf("LMS_LOAN_PERIODS: %w", err)
+	}
+
+	p := loan.Policy{
+		LoanDays:          cfg.LoanDays,
+		MaxLoansPerMember: cfg.MaxLoansPerUser,
+		MaxRenewals:       cfg.MaxLoanRenewals,
+		Supervisors:       cfg.Supervisors,
+		Periods:           periods,
+	}
+	if cfg.MinLoanLength != "0" {
+		if p.MinLength, err = loan.ParseLength(cfg.MinLoanLength); err != nil {
+			return loan.Policy{}, fmt.Errorf("LMS_LOAN_MIN: %w", err)
+		}
+	}
+	if cfg.MaxLoanLength != "0" {
+		if p.MaxLength, err = loan.ParseLength(cfg.MaxLoanLength); err != nil {
+			return loan.Policy{}, fmt.Errorf("LMS_LOAN_MAX: %w", err)
+		}
+	}
+	return p, p.Validate()
+}
+
+func newServices(cfg config.Config, logger *slog.Logger) (tui.Services, error) {
+	store, err := jsonstore.Open(cfg.StoragePath)
+	if err != nil {
+		return tui.Services{}, err
+	}
+
+	bookRepo := jsonstore.NewBookRepository(store)
+	copyRepo := jsonstore.NewCopyRepository(store)
+	memberRepo := jsonstore.NewMemberRepository(store)
+	loanRepo := jsonstore.NewLoanRepository(store)
+	holdRepo := jsonstore.NewHoldRepository(store)
+	branchRepo := jsonstore.NewBranchRepository(store)
+
+	idGen := id.NewGenerator()
+	clock := timeutil.NewClock()
+	events := eventbus.New(jsonstore.NewOutboxRepository(store), idGen, logger)
+
+	bookService := usecase.NewBookService(bookRepo, idGen, clock, book.Policy{UniqueISBN: cfg.UniqueISBN}, events)
+	sequences := jsonstore.NewSequenceRepository(store)
+	copyService := usecase.NewCopyService(
+		copyRepo,
+		sequences,
+		idGen,
+		clock,
+		copy.Policy{
+			AutoBarcode:   cfg.AutoBarcode,
+			BarcodePrefix: cfg.BarcodePrefix,
+			BarcodeDigits: cfg.BarcodeDigits,
+			BarcodeStart:  int64(cfg.BarcodeStart),
+		},
+		events,
+	)
+	memberService := usecase.NewMemberService(
+		memberRepo,
+		sequences,
+		idGen,
+		clock,
+		member.Policy{
+			TermDays:         cfg.MembershipDays,
+			CardPrefix:       cfg.CardPrefix,
+			CardDigits:       cfg.CardDigits,
+			CardStart:        int64(cfg.CardStart),
+			UniqueCard:       cfg.UniqueCard,
+			UniqueEmail:      cfg.UniqueEmail,
+			PhoneCountryCode: cfg.PhoneCountry,
+		},
+		events,
+	)
+	loanPolicy, err := loanPolicy(cfg)
+	if err != nil {
+		return tui.Services{}, err
+	}
+	loanService := usecase.NewLoanService(loanRepo, copyRepo, memberRepo, idGen, clock, loanPolicy, events)
+	blockService := usecase.NewBlockService(
+		memberRepo,
+		loanRepo,
+		clock,
+		member.BlockPolicy{OverdueItems: cfg.BlockOverdue, OverdueDays: cfg.BlockAfterDays},
+		events,
+	)
+	holdService := usecase.NewHoldService(holdRepo, bookRepo, memberRepo, idGen, clock)
+	webhookService := usecase.NewWebhookService(
+		jsonstore.NewWebhookRepository(store),
+		webhookhttp.NewClient(webhookTimeout),
+		idGen,
+		clock,
+	)
+
+	ctx := context.Background()
+	enqueue := func(ctx context.Context, msg eventbus.Message) error {
+		return webhookService.Enqueue(ctx, msg.ID, msg.Event)
+	}
+	if err := events.Subscribe(ctx, "webhooks", enqueue, webhook.Supported...); err != nil {
+		return tui.Services{}, err
+	}
+	review := func(ctx context.Context, msg eventbus.Message) error {
+		return blockService.HandleReturn(ctx, msg.Event)
+	}
+	if err := events.Subscribe(ctx, "blocks", review, event.TypeLoanReturned); err != nil {
+		return tui.Services{}, err
+	}
+	events.CatchUp(ctx)
+
+	return tui.Services{
+		Books:      bookService,
+		Copies:     copyService,
+		Members:    memberService,
+		Loans:      loanService,
+		Holds:      holdService,
+		Reports:    usecase.NewReportService(loanRepo, copyRepo, bookRepo, memberRepo, clock),
+		Webhooks:   webhookService,
+		Stocktakes: usecase.NewStocktakeService(jsonstore.NewStocktakeRepository(store), copyService, idGen, clock),
+		Branches:   usecase.NewBranchService(branchRepo, clock),
+		Transfers:  usecase.NewTransferService(jsonstore.NewTransferRepository(store), branchRepo, copyRepo, loanService, idGen, clock, events),
+		Documents:  usecase.NewDocumentService(bookService, copyService, memberService, loanService, clock),
+		Duplicates: usecase.NewDuplicateService(memberRepo, loanRepo, holdRepo, clock, events),
+		Blocks:     blockService,
+		Events:     events,
+	}, nil
+}
+
+// runWebhookWorker sends queued webhook deliveries until ctx is cancelled.
+// Failed sends are recorded on the delivery itself; only storage errors are
+// logged.
+func runWebhookWorker(ctx context.Context, services tui.Services, logger *slog.Logger) {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := services.Webhooks.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("webhook delivery stopped", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runExpiryWorker deactivates lapsed memberships and applies the automatic
+// block rules at startup and then once a day until ctx is cancelled.
+func runExpiryWorker(ctx context.Context, services tui.Services, logger *slog.Logger) {
+	ticker := time.NewTicker(expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		if r, err := services.Blocks.ReviewAll(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("block review stopped", "error", err)
+		} else if r.Blocked > 0 || r.Unblocked > 0 {
+			logger.Info("member blocks reviewed", "blocked", r.Blocked, "unblocked", r.Unblocked)
+		}
+		if n, err := services.Members.ExpireMemberships(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("membership expiry stopped", "error", err)
+		} else if n > 0 {
+			logger.Info("memberships expired", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func seedInitialData(ctx context.Context, services tui.Services) error {
 	books, err := services.Books.List(ctx)
 	if err != nil {
@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var errorStatuses = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{shared.ErrNotFound, http.StatusNotFound, "not_found"},
+	{shared.ErrDuplicateID, http.StatusConflict, "duplicate_id"},
+	{shared.ErrDuplicateBarcode, http.StatusConflict, "duplicate_barcode"},
+	{shared.ErrDuplicateISBN, http.StatusConflict, "duplicate_isbn"},
+	{shared.ErrDuplicateCard, http.StatusConflict, "duplicate_card"},
+	{shared.ErrDuplicateEmail, http.StatusConflict, "duplicate_email"},
+	{shared.ErrCopyNotAvailable, http.StatusConflict, "copy_not_available"},
+	{shared.ErrLoanAlreadyClosed, http.StatusConflict, "loan_already_closed"},
+	{shared.ErrTransferOpen, http.StatusConflict, "transfer_open"},
+	{shared.ErrTransferState, http.StatusConflict, "transfer_state"},
+	{shared.ErrAlreadyAtBranch, http.StatusConflict, "already_at_branch"},
+	{shared.ErrMemberNotEligible, http.StatusUnprocessableEntity, "member_not_eligible"},
+	{shared.ErrMembershipExpired, http.StatusUnprocessableEntity, "membership_expired"},
+	{shared.ErrLoanLimitReached, http.StatusUnprocessableEntity, "loan_limit_reached"},
+	{shared.ErrRenewalLimit, http.StatusUnprocessableEntity, "renewal_limit"},
+	{shared.ErrLoanAlreadyOverdue, http.StatusUnprocessableEntity, "loan_overdue"},
+	{shared.ErrOverrideNotAllowed, http.StatusForbidden, "override_not_allowed"},
+	{shared.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{errBadRequest, http.StatusBadRequest, "bad_request"},
+}
+
+func statusFor(err error) (int, string) {
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.err) {
+			return e.status, e.code
+		}
+	}
+	return http.StatusInternalServerError, "internal"
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := statusFor(err)
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		s.logger.Error("http request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		msg = "internal server error"
+	}
+	writeError(w, status, code, msg)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, errorBody{Error: errorDetail{Code: code, Message: msg}})
+}
@@ -0,0 +1,538 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+)
+
+func (s *Server) listBooks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var (
+		books []book.Book
+		err   error
+	)
+	if text := strings.TrimSpace(q.Get("q")); text != "" {
+		books, err = s.services.Books.Search(r.Context(), text, 0)
+	} else {
+		books, err = s.services.Books.List(r.Context())
+		sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	status, category, author, isbn := q.Get("status"), q.Get("category"), q.Get("author"), q.Get("isbn")
+	if isbn != "" {
+		if normalized, err := book.NormalizeISBN(isbn); err == nil {
+			isbn = normalized
+		}
+	}
+
+	filtered := books[:0]
+	for _, b := range books {
+		if status != "" && !strings.EqualFold(string(b.Status), status) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(b.Category, category) {
+			continue
+		}
+		if author != "" && !anyContains(b.Authors, author) {
+			continue
+		}
+		if isbn != "" && b.ISBN != isbn {
+			continue
+		}
+		filtered = append(filtered, b)
+	}
+
+	writePage(s, w, r, mapSlice(filtered, toBook))
+}
+
+func (s *Server) getBook(w http.ResponseWriter, r *http.Request) {
+	b, err := s.services.Books.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBook(b))
+}
+
+func (s *Server) createBook(w http.ResponseWriter, r *http.Request) {
+	var req bookRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	b, err := s.services.Books.Create(r.Context(), dto.CreateBookInput{
+		ID:        req.ID,
+		Title:     req.Title,
+		Authors:   req.Authors,
+		ISBN:      req.ISBN,
+		Category:  req.Category,
+		Publisher: req.Publisher,
+		Year:      req.Year,
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toBook(b))
+}
+
+func (s *Server) updateBook(w http.ResponseWriter, r *http.Request) {
+	var req bookRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	id, err := pathID(r, req.ID)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	b, err := s.services.Books.Update(r.Context(), dto.UpdateBookInput{
+		ID:        id,
+		Title:     req.Title,
+		Authors:   req.Authors,
+		ISBN:      req.ISBN,
+		Category:  req.Category,
+		Publisher: req.Publisher,
+		Year:      req.Year,
+		Status:    req.Status,
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBook(b))
+}
+
+func (s *Server) listCopies(w http.ResponseWriter, r *http.Request) {
+	copies, err := s.services.Copies.List(r.Context())
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	sort.Slice(copies, func(i, j int) bool { return copies[i].ID < copies[j].ID })
+
+	q := r.URL.Query()
+	bookID, status, barcode, branch := q.Get("book_id"), q.Get("status"), q.Get("barcode"), q.Get("branch")
+
+	filtered := copies[:0]
+	for _, c := range copies {
+		if bookID != "" && c.BookID != bookID {
+			continue
+		}
+		if status != "" && !strings.EqualFold(string(c.Status), status) {
+			continue
+		}
+		if barcode != "" && !strings.EqualFold(c.Barcode, barcode) {
+			continue
+		}
+		if branch != "" && !strings.EqualFold(c.Branch, branch) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	writePage(s, w, r, mapSlice(filtered, toCopy))
+}
+
+func (s *Server) getCopy(w http.ResponseWriter, r *http.Request) {
+	c, err := s.services.Copies.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toCopy(c))
+}
+
+func (s *Server) createCopy(w http.ResponseWriter, r *http.Request) {
+	var req copyRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	if req.BookID != "" {
+		if _, err := s.services.Books.GetByID(r.Context(), req.BookID); err != nil {
+			s.fail(w, r, err)
+			return
+		}
+	}
+
+	c, err := s.services.Copies.Create(r.Context(), dto.CreateCopyInput{
+		ID:            req.ID,
+		BookID:        req.BookID,
+		Barcode:       req.Barcode,
+		ConditionNote: req.ConditionNote,
+		Branch:        valueOr(req.Branch, ""),
+		Location:      valueOr(req.Location, ""),
+		CallNumber:    valueOr(req.CallNumber, ""),
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toCopy(c))
+}
+
+func (s *Server) updateCopy(w http.ResponseWriter, r *http.Request) {
+	var req copyRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	id, err := pathID(r, req.ID)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	current, err := s.services.Copies.GetByID(r.Context(), id)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	status := req.Status
+	if status == "" {
+		status = string(current.Status)
+	}
+
+	c, err := s.services.Copies.Update(r.Context(), dto.UpdateCopyInput{
+		ID:            id,
+		Barcode:       req.Barcode,
+		Status:        status,
+		ConditionNote: req.ConditionNote,
+		Branch:        valueOr(req.Branch, current.Branch),
+		Location:      valueOr(req.Location, current.Location),
+		CallNumber:    valueOr(req.CallNumber, current.CallNumber),
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toCopy(c))
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := s.services.Members.List(r.Context())
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+
+	q := r.URL.Query()
+	status, text := q.Get("status"), strings.ToLower(strings.TrimSpace(q.Get("q")))
+
+	filtered := members[:0]
+	for _, m := range members {
+		if status != "" && !strings.EqualFold(string(m.Status), status) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(m.CardNumber+" "+m.Name+" "+m.Email+" "+m.Phone), text) {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+
+	writePage(s, w, r, mapSlice(filtered, toMember))
+}
+
+func (s *Server) getMember(w http.ResponseWriter, r *http.Request) {
+	m, err := s.services.Members.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMember(m))
+}
+
+func (s *Server) createMember(w http.ResponseWriter, r *http.Request) {
+	var req memberRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	m, err := s.services.Members.Register(r.Context(), dto.RegisterMemberInput{
+		ID:         req.ID,
+		CardNumber: req.CardNumber,
+		Name:       req.Name,
+		Email:      req.Email,
+		Phone:      req.Phone,
+		Type:       req.Type,
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toMember(m))
+}
+
+func (s *Server) updateMember(w http.ResponseWriter, r *http.Request) {
+	var req memberRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	id, err := pathID(r, req.ID)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	m, err := s.services.Members.Update(r.Context(), dto.UpdateMemberInput{
+		ID:          id,
+		CardNumber:  req.CardNumber,
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Type:        req.Type,
+		Status:      req.Status,
+		BlockReason: req.BlockReason,
+		By:          s.operator,
+	})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMember(m))
+}
+
+func (s *Server) renewMembership(w http.ResponseWriter, r *http.Request) {
+	m, err := s.services.Members.RenewMembership(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMember(m))
+}
+
+// blockMember records a block on the member. Without expires_at the block
+// stays until it is lifted.
+func (s *Server) blockMember(w http.ResponseWriter, r *http.Request) {
+	var req blockRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	input := dto.BlockMemberInput{MemberID: r.PathValue("id"), Reason: req.Reason, Note: req.Note, CreatedBy: req.CreatedBy}
+	if req.ExpiresAt != nil {
+		input.ExpiresAt = *req.ExpiresAt
+	}
+	m, err := s.services.Blocks.Block(r.Context(), input)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMember(m))
+}
+
+func (s *Server) unblockMember(w http.ResponseWriter, r *http.Request) {
+	m, err := s.services.Blocks.Unblock(r.Context(), r.PathValue("id"), s.operator)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMember(m))
+}
+
+// listDuplicates pages through likely duplicate members, best match first.
+func (s *Server) listDuplicates(w http.ResponseWriter, r *http.Request) {
+	dups, err := s.services.Duplicates.Find(r.Context())
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writePage(s, w, r, mapSlice(dups, toDuplicate))
+}
+
+// mergeMember folds the member in the path into the one named in the body.
+func (s *Server) mergeMember(w http.ResponseWriter, r *http.Request) {
+	var req mergeRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	res, err := s.services.Duplicates.Merge(r.Context(), dto.MergeMembersInput{FromID: r.PathValue("id"), IntoID: req.Into})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, mergeResource{
+		Member:         toMember(res.Member),
+		Merged:         toMember(res.Merged),
+		Loans:          res.Loans,
+		Holds:          res.Holds,
+		CancelledHolds: res.CancelledHolds,
+	})
+}
+
+func (s *Server) listLoans(w http.ResponseWriter, r *http.Request) {
+	overdue, filterOverdue, err := queryBool(r, "overdue")
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	loans, err := s.services.Loans.List(r.Context())
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	sort.Slice(loans, func(i, j int) bool { return loans[i].ID < loans[j].ID })
+
+	q := r.URL.Query()
+	memberID, copyID, status, branch := q.Get("member_id"), q.Get("copy_id"), q.Get("status"), q.Get("branch")
+	now := s.clock.Now()
+
+	homes := map[string]string{}
+	if branch != "" {
+		copies, err := s.services.Copies.List(r.Context())
+		if err != nil {
+			s.fail(w, r, err)
+			return
+		}
+		for _, c := range copies {
+			homes[c.ID] = c.Branch
+		}
+	}
+
+	filtered := loans[:0]
+	for _, l := range loans {
+		if memberID != "" && l.MemberID != memberID {
+			continue
+		}
+		if branch != "" && !strings.EqualFold(homes[l.CopyID], branch) {
+			continue
+		}
+		if copyID != "" && l.CopyID != copyID {
+			continue
+		}
+		if status != "" && !strings.EqualFold(string(l.Status), status) {
+			continue
+		}
+		if filterOverdue && l.IsOverdue(now) != overdue {
+			continue
+		}
+		filtered = append(filtered, l)
+	}
+
+	writePage(s, w, r, mapSlice(filtered, func(l loan.Loan) loanResource { return toLoan(l, now) }))
+}
+
+func (s *Server) getLoan(w http.ResponseWriter, r *http.Request) {
+	l, err := s.services.Loans.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toLoan(l, s.clock.Now()))
+}
+
+func (s *Server) issueLoan(w http.ResponseWriter, r *http.Request) {
+	var req issueRequest
+	if err := decode(w, r, &req); err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	input := dto.IssueLoanInput{CopyID: req.CopyID, MemberID: req.MemberID, Period: req.Period, Override: s.overrideInput(req.Override)}
+	if req.DueAt != nil {
+		input.DueAt = *req.DueAt
+	}
+
+	l, err := s.services.Loans.Issue(r.Context(), input)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toLoan(l, s.clock.Now()))
+}
+
+// renewLoan renews a loan. The body is optional and only needed for a
+// supervisor override.
+func (s *Server) renewLoan(w http.ResponseWriter, r *http.Request) {
+	var req renewRequest
+	if r.ContentLength != 0 {
+		if err := decode(w, r, &req); err != nil {
+			s.fail(w, r, err)
+			return
+		}
+	}
+
+	l, err := s.services.Loans.Renew(r.Context(), dto.RenewLoanInput{LoanID: r.PathValue("id"), Override: s.overrideInput(req.Override)})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toLoan(l, s.clock.Now()))
+}
+
+// overrideInput reads the optional override of an issue or renewal. It is
+// given by the server's operator, never by a name sent in the request.
+func (s *Server) overrideInput(o *overrideRequest) dto.LoanOverride {
+	if o == nil {
+		return dto.LoanOverride{}
+	}
+	return dto.LoanOverride{By: s.operator, Reason: o.Reason}
+}
+
+// returnLoan checks a loan in. With ?branch= set the copy is returned at
+// that branch and goes into transit when it belongs elsewhere.
+func (s *Server) returnLoan(w http.ResponseWriter, r *http.Request) {
+	var (
+		l   loan.Loan
+		err error
+	)
+	if branch := r.URL.Query().Get("branch"); branch != "" {
+		l, _, err = s.services.Transfers.Return(r.Context(), r.PathValue("id"), branch)
+	} else {
+		l, err = s.services.Loans.Return(r.Context(), dto.ReturnLoanInput{LoanID: r.PathValue("id")})
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toLoan(l, s.clock.Now()))
+}
+
+func writePage[T any](s *Server, w http.ResponseWriter, r *http.Request, items []T) {
+	p, err := paginate(items, r)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func pathID(r *http.Request, bodyID string) (string, error) {
+	id := r.PathValue("id")
+	if bodyID != "" && bodyID != id {
+		return "", fmt.Errorf("%w: body id %q does not match path id %q", errBadRequest, bodyID, id)
+	}
+	return id, nil
+}
+
+func anyContains(values []string, needle string) bool {
+	needle = strings.ToLower(needle)
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), needle) {
+			return true
+		}
+	}
+	return false
+}
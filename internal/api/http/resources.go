@@ -0,0 +1,278 @@
+package httpapi
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+type bookResource struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Authors   []string   `json:"authors"`
+	ISBN      string     `json:"isbn,omitempty"`
+	Category  string     `json:"category,omitempty"`
+	Publisher string     `json:"publisher,omitempty"`
+	Year      int        `json:"year,omitempty"`
+	Status    string     `json:"status"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type bookRequest struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Authors   []string `json:"authors"`
+	ISBN      string   `json:"isbn"`
+	Category  string   `json:"category"`
+	Publisher string   `json:"publisher"`
+	Year      int      `json:"year"`
+	Status    string   `json:"status"`
+}
+
+type copyResource struct {
+	ID            string     `json:"id"`
+	BookID        string     `json:"book_id"`
+	Barcode       string     `json:"barcode,omitempty"`
+	Status        string     `json:"status"`
+	ConditionNote string     `json:"condition_note,omitempty"`
+	Branch        string     `json:"branch,omitempty"`
+	CurrentBranch string     `json:"current_branch,omitempty"`
+	Location      string     `json:"location,omitempty"`
+	CallNumber    string     `json:"call_number,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// copyRequest uses pointers for the location fields so an update that
+// omits them leaves them unchanged.
+type copyRequest struct {
+	ID            string  `json:"id"`
+	BookID        string  `json:"book_id"`
+	Barcode       string  `json:"barcode"`
+	Status        string  `json:"status"`
+	ConditionNote string  `json:"condition_note"`
+	Branch        *string `json:"branch"`
+	Location      *string `json:"location"`
+	CallNumber    *string `json:"call_number"`
+}
+
+type memberResource struct {
+	ID         string          `json:"id"`
+	CardNumber string          `json:"card_number,omitempty"`
+	Name       string          `json:"name"`
+	Email      string          `json:"email,omitempty"`
+	Phone      string          `json:"phone,omitempty"`
+	Type       string          `json:"type,omitempty"`
+	JoinedAt   time.Time       `json:"joined_at"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	Status     string          `json:"status"`
+	MergedInto string          `json:"merged_into,omitempty"`
+	Blocks     []blockResource `json:"blocks,omitempty"`
+}
+
+type blockResource struct {
+	Reason    string     `json:"reason"`
+	Note      string     `json:"note,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Rule      string     `json:"rule,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy  string     `json:"lifted_by,omitempty"`
+}
+
+type blockRequest struct {
+	Reason    string     `json:"reason"`
+	Note      string     `json:"note"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type memberRequest struct {
+	ID          string `json:"id"`
+	CardNumber  string `json:"card_number"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	BlockReason string `json:"block_reason"`
+}
+
+type duplicateResource struct {
+	Keep    memberResource `json:"keep"`
+	Merge   memberResource `json:"merge"`
+	Score   float64        `json:"score"`
+	Reasons []string       `json:"reasons"`
+}
+
+type mergeRequest struct {
+	Into string `json:"into"`
+}
+
+type mergeResource struct {
+	Member         memberResource `json:"member"`
+	Merged         memberResource `json:"merged"`
+	Loans          int            `json:"loans_moved"`
+	Holds          int            `json:"holds_moved"`
+	CancelledHolds int            `json:"holds_cancelled"`
+}
+
+type loanResource struct {
+	ID           string             `json:"id"`
+	CopyID       string             `json:"copy_id"`
+	MemberID     string             `json:"member_id"`
+	IssuedAt     time.Time          `json:"issued_at"`
+	DueAt        time.Time          `json:"due_at"`
+	ReturnedAt   *time.Time         `json:"returned_at,omitempty"`
+	RenewalCount int                `json:"renewal_count"`
+	Status       string             `json:"status"`
+	Overdue      bool               `json:"overdue"`
+	HoursOverdue int                `json:"hours_overdue,omitempty"`
+	Period       string             `json:"period,omitempty"`
+	Overrides    []overrideResource `json:"overrides,omitempty"`
+	MergedFrom   string             `json:"merged_from,omitempty"`
+}
+
+type overrideResource struct {
+	Rules  []string  `json:"rules"`
+	Reason string    `json:"reason"`
+	By     string    `json:"by"`
+	At     time.Time `json:"at"`
+}
+
+type issueRequest struct {
+	CopyID   string           `json:"copy_id"`
+	MemberID string           `json:"member_id"`
+	Period   string           `json:"period"`
+	DueAt    *time.Time       `json:"due_at"`
+	Override *overrideRequest `json:"override"`
+}
+
+type renewRequest struct {
+	Override *overrideRequest `json:"override"`
+}
+
+type overrideRequest struct {
+	Reason string `json:"reason"`
+}
+
+func toBook(b book.Book) bookResource {
+	authors := b.Authors
+	if authors == nil {
+		authors = []string{}
+	}
+	return bookResource{
+		ID:        b.ID,
+		Title:     b.Title,
+		Authors:   authors,
+		ISBN:      b.ISBN,
+		Category:  b.Category,
+		Publisher: b.Publisher,
+		Year:      b.Year,
+		Status:    string(b.Status),
+		UpdatedAt: optionalTime(b.UpdatedAt),
+	}
+}
+
+func toCopy(c copy.Copy) copyResource {
+	return copyResource{
+		ID:            c.ID,
+		BookID:        c.BookID,
+		Barcode:       c.Barcode,
+		Status:        string(c.Status),
+		ConditionNote: c.ConditionNote,
+		Branch:        c.Branch,
+		CurrentBranch: c.CurrentBranch,
+		Location:      c.Location,
+		CallNumber:    c.CallNumber,
+		UpdatedAt:     optionalTime(c.UpdatedAt),
+	}
+}
+
+func toMember(m member.Member) memberResource {
+	return memberResource{
+		ID:         m.ID,
+		CardNumber: m.CardNumber,
+		Name:       m.Name,
+		Email:      m.Email,
+		Phone:      m.Phone,
+		Type:       m.Type,
+		JoinedAt:   m.JoinedAt,
+		ExpiresAt:  optionalTime(m.ExpiresAt),
+		Status:     string(m.Status),
+		MergedInto: m.MergedInto,
+		Blocks:     mapSlice(m.Blocks, toBlock),
+	}
+}
+
+func toBlock(b member.Block) blockResource {
+	return blockResource{
+		Reason:    b.Reason,
+		Note:      b.Note,
+		CreatedBy: b.CreatedBy,
+		CreatedAt: b.CreatedAt,
+		ExpiresAt: optionalTime(b.ExpiresAt),
+		Rule:      string(b.Rule),
+		LiftedAt:  optionalTime(b.LiftedAt),
+		LiftedBy:  b.LiftedBy,
+	}
+}
+
+func toDuplicate(d member.Duplicate) duplicateResource {
+	reasons := d.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return duplicateResource{Keep: toMember(d.Keep), Merge: toMember(d.Merge), Score: d.Score, Reasons: reasons}
+}
+
+func toLoan(l loan.Loan, now time.Time) loanResource {
+	return loanResource{
+		ID:           l.ID,
+		CopyID:       l.CopyID,
+		MemberID:     l.MemberID,
+		IssuedAt:     l.IssuedAt,
+		DueAt:        l.DueAt,
+		ReturnedAt:   l.ReturnedAt,
+		RenewalCount: l.RenewalCount,
+		Status:       string(l.Status),
+		Overdue:      l.IsOverdue(now),
+		HoursOverdue: l.HoursOverdue(now),
+		Period:       l.Period,
+		Overrides:    mapSlice(l.Overrides, toOverride),
+		MergedFrom:   l.MergedFrom,
+	}
+}
+
+func toOverride(o loan.Override) overrideResource {
+	rules := make([]string, 0, len(o.Rules))
+	for _, r := range o.Rules {
+		rules = append(rules, string(r))
+	}
+	return overrideResource{Rules: rules, Reason: o.Reason, By: o.By, At: o.At}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func mapSlice[T, R any](items []T, fn func(T) R) []R {
+	out := make([]R, 0, len(items))
+	for _, it := range items {
+		out = append(out, fn(it))
+	}
+	return out
+}
+
+func valueOr(v *string, fallback string) string {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
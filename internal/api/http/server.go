@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+	maxBodyBytes = 1 << 20
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+var errBadRequest = errors.New("bad request")
+
+type Services struct {
+	Books      usecase.BookService
+	Copies     usecase.CopyService
+	Members    usecase.MemberService
+	Loans      usecase.LoanService
+	Transfers  usecase.TransferService
+	Duplicates usecase.DuplicateService
+	Blocks     usecase.BlockService
+}
+
+// Server serves the JSON API. The API has no logins, so operator is the
+// user the server runs as; supervisor overrides are given in their name.
+// Requests that change data run one at a time, so rules checked before a
+// write, such as loan eligibility, still hold when it is saved.
+type Server struct {
+	services Services
+	clock    ports.Clock
+	logger   *slog.Logger
+	operator string
+	mux      *http.ServeMux
+	writes   sync.Mutex
+}
+
+func NewServer(services Services, clock ports.Clock, logger *slog.Logger, operator string) *Server {
+	s := &Server{services: services, clock: clock, logger: logger, operator: operator, mux: http.NewServeMux()}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/openapi.json", s.handleOpenAPI)
+
+	s.mux.HandleFunc("GET /api/v1/books", s.listBooks)
+	s.mux.HandleFunc("POST /api/v1/books", s.createBook)
+	s.mux.HandleFunc("GET /api/v1/books/{id}", s.getBook)
+	s.mux.HandleFunc("PUT /api/v1/books/{id}", s.updateBook)
+
+	s.mux.HandleFunc("GET /api/v1/copies", s.listCopies)
+	s.mux.HandleFunc("POST /api/v1/copies", s.createCopy)
+	s.mux.HandleFunc("GET /api/v1/copies/{id}", s.getCopy)
+	s.mux.HandleFunc("PUT /api/v1/copies/{id}", s.updateCopy)
+
+	s.mux.HandleFunc("GET /api/v1/members", s.listMembers)
+	s.mux.HandleFunc("POST /api/v1/members", s.createMember)
+	s.mux.HandleFunc("GET /api/v1/members/duplicates", s.listDuplicates)
+	s.mux.HandleFunc("GET /api/v1/members/{id}", s.getMember)
+	s.mux.HandleFunc("PUT /api/v1/members/{id}", s.updateMember)
+	s.mux.HandleFunc("POST /api/v1/members/{id}/renew", s.renewMembership)
+	s.mux.HandleFunc("POST /api/v1/members/{id}/merge", s.mergeMember)
+	s.mux.HandleFunc("POST /api/v1/members/{id}/block", s.blockMember)
+	s.mux.HandleFunc("DELETE /api/v1/members/{id}/block", s.unblockMember)
+
+	s.mux.HandleFunc("GET /api/v1/loans", s.listLoans)
+	s.mux.HandleFunc("POST /api/v1/loans", s.issueLoan)
+	s.mux.HandleFunc("GET /api/v1/loans/{id}", s.getLoan)
+	s.mux.HandleFunc("POST /api/v1/loans/{id}/renew", s.renewLoan)
+	s.mux.HandleFunc("POST /api/v1/loans/{id}/return", s.returnLoan)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	defer func() {
+		if p := recover(); p != nil {
+			s.logger.Error("http handler panic", "method", r.Method, "path", r.URL.Path, "panic", p)
+			writeError(rec, http.StatusInternalServerError, "internal", "internal server error")
+		}
+		s.logger.Debug("http request", "method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+	}()
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		s.writes.Lock()
+		defer s.writes.Unlock()
+	}
+	s.mux.ServeHTTP(rec, r)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+type page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+func paginate[T any](items []T, r *http.Request) (page[T], error) {
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil {
+		return page[T]{}, err
+	}
+	if limit < 1 || limit > maxLimit {
+		return page[T]{}, fmt.Errorf("%w: limit must be between 1 and %d", errBadRequest, maxLimit)
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		return page[T]{}, err
+	}
+	if offset < 0 {
+		return page[T]{}, fmt.Errorf("%w: offset cannot be negative", errBadRequest)
+	}
+
+	p := page[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset < len(items) {
+		p.Items = items[offset:min(offset+limit, len(items))]
+	}
+	return p, nil
+}
+
+func queryInt(r *http.Request, key string, fallback int) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be an integer", errBadRequest, key)
+	}
+	return n, nil
+}
+
+func queryBool(r *http.Request, key string) (value, set bool, err error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return false, false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, false, fmt.Errorf("%w: %s must be true or false", errBadRequest, key)
+	}
+	return b, true, nil
+}
+
+func decode(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: request body is required", errBadRequest)
+		}
+		return fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	if dec.More() {
+		return fmt.Errorf("%w: request body must contain a single json object", errBadRequest)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
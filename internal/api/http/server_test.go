@@ -0,0 +1,454 @@
+package httpapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpapi "github.com/mibienpanjoe/LMS-bit/internal/api/http"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/id"
+	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
+)
+
+// fixedClock always reads now. A delay slows every read, which widens the
+// gap between a service's checks and its writes.
+type fixedClock struct {
+	now   time.Time
+	delay time.Duration
+}
+
+func (c *fixedClock) Now() time.Time {
+	time.Sleep(c.delay)
+	return c.now
+}
+
+func TestCirculationFlow(t *testing.T) {
+	t.Parallel()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	srv := newTestServer(t, clock)
+
+	var b struct{ ID, ISBN string }
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"title":"The Go Programming Language","authors":["Alan Donovan"],"isbn":"0306406152"}`, http.StatusCreated, &b)
+	if b.ISBN != "9780306406157" {
+		t.Fatalf("expected canonical isbn got %q", b.ISBN)
+	}
+
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"`+b.ID+`","barcode":"BC-1"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-1","name":"Ann Reader"}`, http.StatusCreated, nil)
+
+	var issued struct {
+		ID     string
+		Status string
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1"}`, http.StatusCreated, &issued)
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1"}`, http.StatusConflict, nil)
+
+	var renewed struct {
+		RenewalCount int `json:"renewal_count"`
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", "", http.StatusOK, &renewed)
+	if renewed.RenewalCount != 1 {
+		t.Fatalf("expected renewal count 1 got %d", renewed.RenewalCount)
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", "", http.StatusUnprocessableEntity, nil)
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", `{"override":{"by":"amy","reason":"exam week"}}`, http.StatusBadRequest, nil)
+
+	var overridden struct {
+		RenewalCount int `json:"renewal_count"`
+		Overrides    []struct {
+			Rules  []string
+			Reason string
+			By     string
+		}
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", `{"override":{"reason":"exam week"}}`, http.StatusOK, &overridden)
+	if overridden.RenewalCount != 2 || len(overridden.Overrides) != 1 || overridden.Overrides[0].By != "sam" || overridden.Overrides[0].Rules[0] != "renewal_limit" {
+		t.Fatalf("expected a recorded override got %+v", overridden)
+	}
+
+	clock.now = clock.now.AddDate(0, 0, 60)
+	var overdue struct {
+		Total int
+		Items []struct{ Overdue bool }
+	}
+	do(t, srv, http.MethodGet, "/api/v1/loans?member_id=m-1&overdue=true", "", http.StatusOK, &overdue)
+	if overdue.Total != 1 || !overdue.Items[0].Overdue {
+		t.Fatalf("expected one overdue loan got %+v", overdue)
+	}
+
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/return", "", http.StatusOK, nil)
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/return", "", http.StatusConflict, nil)
+
+	var c struct{ Status string }
+	do(t, srv, http.MethodGet, "/api/v1/copies/c-1", "", http.StatusOK, &c)
+	if c.Status != "available" {
+		t.Fatalf("expected returned copy to be available got %q", c.Status)
+	}
+}
+
+func TestShortLoans(t *testing.T) {
+	t.Parallel()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	srv := newTestServer(t, clock)
+
+	var b struct{ ID string }
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"title":"Go","authors":["Alan Donovan"]}`, http.StatusCreated, &b)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"`+b.ID+`"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-1","name":"Ann Reader"}`, http.StatusCreated, nil)
+
+	var body struct {
+		Error struct{ Code string }
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1","period":"week"}`, http.StatusBadRequest, &body)
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1","due_at":"2026-06-01T10:00:00Z"}`, http.StatusBadRequest, &body)
+	if body.Error.Code != "invalid_input" {
+		t.Fatalf("expected invalid_input got %q", body.Error.Code)
+	}
+
+	var issued struct {
+		ID     string
+		DueAt  time.Time `json:"due_at"`
+		Period string
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1","period":"reserve"}`, http.StatusCreated, &issued)
+	if issued.Period != "reserve" || !issued.DueAt.Equal(clock.now.Add(2*time.Hour)) {
+		t.Fatalf("expected a two hour reserve loan got %+v", issued)
+	}
+
+	clock.now = clock.now.Add(4*time.Hour + time.Minute)
+	var late struct {
+		Overdue      bool
+		HoursOverdue int `json:"hours_overdue"`
+	}
+	do(t, srv, http.MethodGet, "/api/v1/loans/"+issued.ID, "", http.StatusOK, &late)
+	if !late.Overdue || late.HoursOverdue != 3 {
+		t.Fatalf("expected three hours overdue got %+v", late)
+	}
+}
+
+func TestErrorsAndPagination(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)})
+	for _, id := range []string{"m-1", "m-2", "m-3"} {
+		do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"`+id+`","name":"Member `+id+`"}`, http.StatusCreated, nil)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{name: "not found", method: http.MethodGet, path: "/api/v1/members/nope", status: http.StatusNotFound, code: "not_found"},
+		{name: "duplicate id", method: http.MethodPost, path: "/api/v1/members", body: `{"id":"m-1","name":"Again"}`, status: http.StatusConflict, code: "duplicate_id"},
+		{name: "validation", method: http.MethodPost, path: "/api/v1/books", body: `{"title":"","authors":["A"]}`, status: http.StatusBadRequest, code: "invalid_input"},
+		{name: "bad isbn", method: http.MethodPost, path: "/api/v1/books", body: `{"title":"T","authors":["A"],"isbn":"1234567890"}`, status: http.StatusBadRequest, code: "invalid_input"},
+		{name: "bad email", method: http.MethodPost, path: "/api/v1/members", body: `{"name":"x","email":"x@"}`, status: http.StatusBadRequest, code: "invalid_input"},
+		{name: "bad phone", method: http.MethodPost, path: "/api/v1/members", body: `{"name":"x","phone":"06 12 ext 4"}`, status: http.StatusBadRequest, code: "invalid_input"},
+		{name: "unknown field", method: http.MethodPost, path: "/api/v1/members", body: `{"nme":"x"}`, status: http.StatusBadRequest, code: "bad_request"},
+		{name: "id mismatch", method: http.MethodPut, path: "/api/v1/members/m-1", body: `{"id":"m-2","name":"x"}`, status: http.StatusBadRequest, code: "bad_request"},
+		{name: "bad limit", method: http.MethodGet, path: "/api/v1/members?limit=0", status: http.StatusBadRequest, code: "bad_request"},
+		{name: "bad overdue", method: http.MethodGet, path: "/api/v1/loans?overdue=maybe", status: http.StatusBadRequest, code: "bad_request"},
+		{name: "blocked without reason", method: http.MethodPut, path: "/api/v1/members/m-3", body: `{"name":"Member m-3","status":"blocked"}`, status: http.StatusBadRequest, code: "invalid_input"},
+		{name: "blocked member", method: http.MethodPut, path: "/api/v1/members/m-3", body: `{"name":"Member m-3","status":"blocked","block_reason":"lost card"}`, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		var body struct {
+			Error struct{ Code string }
+		}
+		do(t, srv, tt.method, tt.path, tt.body, tt.status, &body)
+		if body.Error.Code != tt.code {
+			t.Fatalf("%s: expected code %q got %q", tt.name, tt.code, body.Error.Code)
+		}
+	}
+
+	var blocked struct {
+		Blocks []struct {
+			Reason    string
+			CreatedBy string `json:"created_by"`
+		}
+	}
+	do(t, srv, http.MethodGet, "/api/v1/members/m-3", "", http.StatusOK, &blocked)
+	if len(blocked.Blocks) != 1 || blocked.Blocks[0].Reason != "lost card" || blocked.Blocks[0].CreatedBy != "sam" {
+		t.Fatalf("expected the status change to record a block, got %+v", blocked)
+	}
+
+	var p struct {
+		Items  []struct{ ID string }
+		Total  int
+		Offset int
+	}
+	do(t, srv, http.MethodGet, "/api/v1/members?limit=2&offset=1", "", http.StatusOK, &p)
+	if p.Total != 3 || len(p.Items) != 2 || p.Items[0].ID != "m-2" {
+		t.Fatalf("unexpected page %+v", p)
+	}
+
+	do(t, srv, http.MethodGet, "/api/v1/members?status=blocked", "", http.StatusOK, &p)
+	if p.Total != 1 || p.Items[0].ID != "m-3" {
+		t.Fatalf("unexpected filtered page %+v", p)
+	}
+}
+
+func TestCopyLocationFields(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)})
+
+	var b struct{ ID string }
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"title":"Go","authors":["Alan Donovan"]}`, http.StatusCreated, &b)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"`+b.ID+`","branch":"East","location":"Stacks","call_number":"QA76.73 .G63"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-2","book_id":"`+b.ID+`","branch":"Main"}`, http.StatusCreated, nil)
+
+	var c struct {
+		Barcode    string
+		Branch     string
+		CallNumber string `json:"call_number"`
+	}
+	do(t, srv, http.MethodPut, "/api/v1/copies/c-1", `{"barcode":"BC-1"}`, http.StatusOK, &c)
+	if c.Barcode != "BC-1" || c.Branch != "EAST" || c.CallNumber != "QA76.73 .G63" {
+		t.Fatalf("expected location kept on update got %+v", c)
+	}
+
+	var page struct {
+		Total int
+		Items []struct{ ID string }
+	}
+	do(t, srv, http.MethodGet, "/api/v1/copies?branch=east", "", http.StatusOK, &page)
+	if page.Total != 1 || page.Items[0].ID != "c-1" {
+		t.Fatalf("expected only the east copy got %+v", page)
+	}
+}
+
+func TestOpenAPIDocumentIsValidJSON(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{})
+	var doc struct {
+		OpenAPI string
+		Paths   map[string]json.RawMessage
+	}
+	do(t, srv, http.MethodGet, "/api/v1/openapi.json", "", http.StatusOK, &doc)
+	if doc.OpenAPI == "" || doc.Paths["/loans/{id}/renew"] == nil {
+		t.Fatalf("unexpected openapi document")
+	}
+}
+
+func TestMemberDuplicatesAndMerge(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)})
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-1","name":"Ann Reader","email":"ann@example.com"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-2","name":"ann reader","email":"Ann@Example.com","phone":"+1 (555) 010-1234"}`, http.StatusCreated, nil)
+
+	var dups struct {
+		Total int
+		Items []struct {
+			Keep, Merge struct{ ID string }
+			Score       float64
+			Reasons     []string
+		}
+	}
+	do(t, srv, http.MethodGet, "/api/v1/members/duplicates", "", http.StatusOK, &dups)
+	if dups.Total != 1 || dups.Items[0].Keep.ID != "m-1" || dups.Items[0].Merge.ID != "m-2" || dups.Items[0].Score != 1 {
+		t.Fatalf("expected m-2 to be a duplicate of m-1, got %+v", dups)
+	}
+
+	var merged struct {
+		Member struct{ ID, Phone string }
+		Merged struct{ Status string }
+	}
+	do(t, srv, http.MethodPost, "/api/v1/members/m-2/merge", `{"into":"m-1"}`, http.StatusOK, &merged)
+	if merged.Member.Phone != "+15550101234" || merged.Merged.Status != "inactive" {
+		t.Fatalf("unexpected merge result %+v", merged)
+	}
+	do(t, srv, http.MethodPost, "/api/v1/members/m-2/merge", `{"into":"m-1"}`, http.StatusBadRequest, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members/m-9/merge", `{"into":"m-1"}`, http.StatusNotFound, nil)
+}
+
+func TestMemberBlocks(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)})
+	var b struct{ ID string }
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"title":"Go","authors":["Alan Donovan"]}`, http.StatusCreated, &b)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"`+b.ID+`","barcode":"BC-1"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-1","name":"Ann Reader"}`, http.StatusCreated, nil)
+
+	do(t, srv, http.MethodPost, "/api/v1/members/m-1/block", `{"note":"no reason"}`, http.StatusBadRequest, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members/m-1/block", `{"reason":"late"}`, http.StatusOK, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members/m-9/block", `{"reason":"late"}`, http.StatusNotFound, nil)
+
+	var blocked struct {
+		Status string
+		Blocks []struct {
+			Reason    string
+			CreatedBy string     `json:"created_by"`
+			ExpiresAt *time.Time `json:"expires_at"`
+		}
+	}
+	do(t, srv, http.MethodPost, "/api/v1/members/m-1/block", `{"reason":"lost card","note":"called","created_by":"amy","expires_at":"2026-03-08T00:00:00Z"}`, http.StatusOK, &blocked)
+	if blocked.Status != "blocked" || len(blocked.Blocks) != 2 || blocked.Blocks[1].CreatedBy != "amy" || blocked.Blocks[1].ExpiresAt == nil {
+		t.Fatalf("unexpected blocked member %+v", blocked)
+	}
+
+	var failed struct {
+		Error struct{ Code, Message string }
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1"}`, http.StatusUnprocessableEntity, &failed)
+	if failed.Error.Code != "member_not_eligible" || !strings.Contains(failed.Error.Message, "late; lost card") {
+		t.Fatalf("expected the block reasons in the error, got %+v", failed)
+	}
+
+	var unblocked struct {
+		Status string
+		Blocks []struct {
+			LiftedAt *time.Time `json:"lifted_at"`
+			LiftedBy string     `json:"lifted_by"`
+		}
+	}
+	do(t, srv, http.MethodDelete, "/api/v1/members/m-1/block", "", http.StatusOK, &unblocked)
+	if unblocked.Status != "active" || len(unblocked.Blocks) != 2 || unblocked.Blocks[0].LiftedAt == nil || unblocked.Blocks[0].LiftedBy != "sam" {
+		t.Fatalf("unexpected unblocked member %+v", unblocked)
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1"}`, http.StatusCreated, nil)
+}
+
+func TestConcurrentLoansForOneCopy(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), delay: time.Millisecond})
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"id":"b-1","title":"Go","authors":["A"]}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"b-1","barcode":"BC-1"}`, http.StatusCreated, nil)
+
+	const members = 8
+	for i := range members {
+		do(t, srv, http.MethodPost, "/api/v1/members", fmt.Sprintf(`{"id":"m-%d","name":"Member %d"}`, i, i), http.StatusCreated, nil)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int32
+	)
+	for i := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/loans", strings.NewReader(fmt.Sprintf(`{"copy_id":"c-1","member_id":"m-%d"}`, i)))
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			if rec.Code == http.StatusCreated {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := created.Load(); n != 1 {
+		t.Fatalf("expected one loan for the copy got %d", n)
+	}
+}
+
+func TestOverrideNeedsSupervisorOperator(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServerAs(t, &fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}, "amy")
+
+	do(t, srv, http.MethodPost, "/api/v1/books", `{"id":"b-1","title":"Go","authors":["A"]}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/copies", `{"id":"c-1","book_id":"b-1","barcode":"BC-1"}`, http.StatusCreated, nil)
+	do(t, srv, http.MethodPost, "/api/v1/members", `{"id":"m-1","name":"Ann Reader"}`, http.StatusCreated, nil)
+
+	var issued struct{ ID string }
+	do(t, srv, http.MethodPost, "/api/v1/loans", `{"copy_id":"c-1","member_id":"m-1"}`, http.StatusCreated, &issued)
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", "", http.StatusOK, nil)
+
+	var body struct {
+		Error struct{ Code string }
+	}
+	do(t, srv, http.MethodPost, "/api/v1/loans/"+issued.ID+"/renew", `{"override":{"reason":"exam week"}}`, http.StatusForbidden, &body)
+	if body.Error.Code != "override_not_allowed" {
+		t.Fatalf("expected override_not_allowed got %q", body.Error.Code)
+	}
+}
+
+func newTestServer(t *testing.T, clock *fixedClock) http.Handler {
+	t.Helper()
+	return newTestServerAs(t, clock, "sam")
+}
+
+// newTestServerAs builds a server run by operator; only "sam" supervises.
+func newTestServerAs(t *testing.T, clock *fixedClock, operator string) http.Handler {
+	t.Helper()
+
+	store, err := jsonstore.Open(filepath.Join(t.TempDir(), "storage.json"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	copyRepo := jsonstore.NewCopyRepository(store)
+	memberRepo := jsonstore.NewMemberRepository(store)
+	loanRepo := jsonstore.NewLoanRepository(store)
+	idGen := id.NewGenerator()
+
+	services := httpapi.Services{
+		Books:   usecase.NewBookService(jsonstore.NewBookRepository(store), idGen, clock, book.Policy{UniqueISBN: true}, nil),
+		Copies:  usecase.NewCopyService(copyRepo, nil, idGen, clock, copy.Policy{}, nil),
+		Members: usecase.NewMemberService(memberRepo, nil, idGen, clock, member.Policy{}, nil),
+		Loans: usecase.NewLoanService(
+			loanRepo,
+			copyRepo,
+			memberRepo,
+			idGen,
+			clock,
+			loan.Policy{
+				LoanDays:          14,
+				MaxLoansPerMember: 3,
+				MaxRenewals:       1,
+				Supervisors:       []string{"sam"},
+				Periods:           []loan.Period{{Name: "reserve", Length: 2 * time.Hour}},
+				MaxLength:         30 * 24 * time.Hour,
+			},
+			nil,
+		),
+		Duplicates: usecase.NewDuplicateService(memberRepo, loanRepo, jsonstore.NewHoldRepository(store), clock, nil),
+		Blocks:     usecase.NewBlockService(memberRepo, loanRepo, clock, member.BlockPolicy{}, nil),
+	}
+
+	return httpapi.NewServer(services, clock, slog.New(slog.NewTextHandler(io.Discard, nil)), operator)
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string, wantStatus int, out any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != wantStatus {
+		t.Fatalf("%s %s: expected status %d got %d: %s", method, path, wantStatus, rec.Code, rec.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode response: %v", method, path, err)
+		}
+	}
+}
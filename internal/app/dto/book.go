@@ -10,6 +10,7 @@ type CreateBookInput struct {
 	Year      int
 }
 
+// UpdateBookInput keeps the current status when Status is blank.
 type UpdateBookInput struct {
 	ID        string
 	Title     string
@@ -18,4 +19,5 @@ type UpdateBookInput struct {
 	Category  string
 	Publisher string
 	Year      int
+	Status    string
 }
@@ -0,0 +1,11 @@
+package dto
+
+type AddBranchInput struct {
+	Code string
+	Name string
+}
+
+type RequestTransferInput struct {
+	CopyID string
+	To     string
+}
@@ -5,6 +5,9 @@ type CreateCopyInput struct {
 	BookID        string
 	Barcode       string
 	ConditionNote string
+	Branch        string
+	Location      string
+	CallNumber    string
 }
 
 type UpdateCopyInput struct {
@@ -12,4 +15,7 @@ type UpdateCopyInput struct {
 	Barcode       string
 	Status        string
 	ConditionNote string
+	Branch        string
+	Location      string
+	CallNumber    string
 }
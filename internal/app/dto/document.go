@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+type ReceiptKind string
+
+const (
+	ReceiptCheckout ReceiptKind = "checkout"
+	ReceiptReturn   ReceiptKind = "return"
+)
+
+// DocumentItem is one loan printed on a receipt or notice. DaysOverdue is
+// how late a returned loan came back, or how late an open one is now.
+// Short loans are due at a time of day and late by HoursOverdue.
+type DocumentItem struct {
+	LoanID       string
+	Barcode      string
+	Title        string
+	IssuedAt     time.Time
+	DueAt        time.Time
+	ReturnedAt   *time.Time
+	DaysOverdue  int
+	Short        bool
+	HoursOverdue int
+}
+
+type Receipt struct {
+	Kind   ReceiptKind
+	Member member.Member
+	Items  []DocumentItem
+	At     time.Time
+}
+
+// OverdueNotice is the letter sent to one member, oldest due date first.
+type OverdueNotice struct {
+	Member member.Member
+	Items  []DocumentItem
+	At     time.Time
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+type Holding struct {
+	Book   book.Book
+	Copies []copy.Copy
+}
+
+func (h Holding) ChangedAt() time.Time {
+	latest := h.Book.UpdatedAt
+	for _, c := range h.Copies {
+		if c.UpdatedAt.After(latest) {
+			latest = c.UpdatedAt
+		}
+	}
+	return latest
+}
@@ -0,0 +1,6 @@
+package dto
+
+type PlaceHoldInput struct {
+	BookID   string
+	MemberID string
+}
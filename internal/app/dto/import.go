@@ -0,0 +1,29 @@
+package dto
+
+type ImportStatus string
+
+const (
+	ImportNew       ImportStatus = "new"
+	ImportDuplicate ImportStatus = "duplicate"
+	ImportInvalid   ImportStatus = "invalid"
+)
+
+type ImportItem struct {
+	Index      int
+	Input      CreateBookInput
+	Status     ImportStatus
+	ExistingID string
+	Problem    string
+}
+
+type ImportOptions struct {
+	CopiesPerRecord  int
+	AttachDuplicates bool
+}
+
+type ImportResult struct {
+	BooksCreated  int
+	CopiesCreated int
+	Skipped       int
+	Failed        []ImportItem
+}
@@ -0,0 +1,19 @@
+package dto
+
+// Label is the text printed for one copy.
+type Label struct {
+	CopyID     string
+	Barcode    string
+	Title      string
+	CallNumber string
+	Branch     string
+}
+
+// LabelSelection picks the copies of a label batch: the listed copy IDs or
+// barcodes in the given order, otherwise every copy of BookID and/or owned
+// by Branch in shelf order.
+type LabelSelection struct {
+	Copies []string
+	BookID string
+	Branch string
+}
@@ -1,14 +1,54 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+// IssueLoanInput lends a copy for the default loan period, or for the
+// named Period or until DueAt when one is given.
 type IssueLoanInput struct {
 	CopyID   string
 	MemberID string
+	Period   string
+	DueAt    time.Time
+	Override LoanOverride
 }
 
 type RenewLoanInput struct {
-	LoanID string
+	LoanID   string
+	Override LoanOverride
+}
+
+// LoanOverride asks to issue or renew despite the loan limit, renewal
+// limit or overdue rules. By must be a supervisor. The zero value asks for
+// no override.
+type LoanOverride struct {
+	By     string
+	Reason string
 }
 
 type ReturnLoanInput struct {
 	LoanID string
 }
+
+// Eligibility explains whether Copy may be issued to Member. Violations is
+// empty when it may.
+type Eligibility struct {
+	Copy        copy.Copy
+	Member      member.Member
+	ActiveLoans int
+	MaxLoans    int
+	Violations  []loan.Violation
+}
+
+// UncheckedNote tells staff reading an eligibility check what it leaves
+// out: fines and fees are not tracked, so there is no balance rule.
+const UncheckedNote = "balance not checked: fines and fees are not tracked"
+
+func (e Eligibility) Eligible() bool {
+	return len(e.Violations) == 0
+}
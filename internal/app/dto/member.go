@@ -1,15 +1,67 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+// RegisterMemberInput leaves CardNumber blank to take the next number from
+// the card sequence.
 type RegisterMemberInput struct {
-	ID    string
-	Name  string
-	Email string
-	Phone string
+	ID         string
+	CardNumber string
+	Name       string
+	Email      string
+	Phone      string
+	Type       string
 }
 
+// UpdateMemberInput keeps the current card number when CardNumber is blank,
+// and the current status when Status is. Setting Status to blocked needs a
+// BlockReason; By is recorded on the block placed or lifted.
 type UpdateMemberInput struct {
-	ID    string
-	Name  string
-	Email string
-	Phone string
+	ID          string
+	CardNumber  string
+	Name        string
+	Email       string
+	Phone       string
+	Type        string
+	Status      string
+	BlockReason string
+	By          string
+}
+
+// MergeMembersInput folds the member FromID into IntoID.
+type MergeMembersInput struct {
+	FromID string
+	IntoID string
+}
+
+// MergeResult is the surviving member, the record merged into it and how
+// many loans and holds moved. Holds the survivor already had on the same
+// title are cancelled rather than moved.
+type MergeResult struct {
+	Member         member.Member
+	Merged         member.Member
+	Loans          int
+	Holds          int
+	CancelledHolds int
+}
+
+// BlockMemberInput blocks a member. A zero ExpiresAt keeps the block until
+// it is lifted.
+type BlockMemberInput struct {
+	MemberID  string
+	Reason    string
+	Note      string
+	CreatedBy string
+	ExpiresAt time.Time
+}
+
+// BlockReview counts the members an automatic block review blocked and
+// unblocked.
+type BlockReview struct {
+	Blocked   int
+	Unblocked int
 }
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+type OverdueItem struct {
+	Loan        loan.Loan
+	Barcode     string
+	DaysOverdue int
+}
+
+type AgingCounts struct {
+	Days1To7      int
+	Days8To30     int
+	Days31AndOver int
+}
+
+func (a *AgingCounts) Add(bucket loan.Aging) {
+	switch bucket {
+	case loan.Aging1To7:
+		a.Days1To7++
+	case loan.Aging8To30:
+		a.Days8To30++
+	case loan.Aging31AndOver:
+		a.Days31AndOver++
+	}
+}
+
+// OverdueGroup is one member's overdue loans, oldest due date first.
+type OverdueGroup struct {
+	Member    member.Member
+	Items     []OverdueItem
+	OldestDue time.Time
+	Aging     AgingCounts
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+// ReportRange selects loans issued in [From, To). A zero bound is open.
+// Branch, when set, also limits reports to copies owned by that branch.
+type ReportRange struct {
+	From   time.Time
+	To     time.Time
+	Branch string
+}
+
+func (r ReportRange) Contains(t time.Time) bool {
+	if !r.From.IsZero() && t.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && !t.Before(r.To) {
+		return false
+	}
+	return true
+}
+
+type ReportKind string
+
+const (
+	ReportTopBorrowed   ReportKind = "top-borrowed"
+	ReportByCategory    ReportKind = "by-category"
+	ReportByMonth       ReportKind = "by-month"
+	ReportByMemberType  ReportKind = "by-member-type"
+	ReportTurnover      ReportKind = "turnover"
+	ReportNeverBorrowed ReportKind = "never-borrowed"
+)
+
+var ReportKinds = []ReportKind{
+	ReportTopBorrowed,
+	ReportByCategory,
+	ReportByMonth,
+	ReportByMemberType,
+	ReportTurnover,
+	ReportNeverBorrowed,
+}
+
+type TitleCount struct {
+	BookID string
+	Title  string
+	Loans  int
+}
+
+type GroupCount struct {
+	Key   string
+	Loans int
+}
+
+type CopyTurnover struct {
+	CopyID  string
+	Barcode string
+	BookID  string
+	Title   string
+	Loans   int
+	// PerYear is the loan count annualised over the report range.
+	PerYear float64
+}
+
+type CopyRef struct {
+	CopyID  string
+	Barcode string
+	BookID  string
+	Title   string
+	Status  string
+}
+
+// ExpiringMember is an active membership that lapses soon. DaysLeft is
+// negative once it has lapsed but the expiry job has not yet run.
+type ExpiringMember struct {
+	Member      member.Member
+	DaysLeft    int
+	ActiveLoans int
+}
+
+// ReportTable is a report flattened to text cells for display and CSV.
+type ReportTable struct {
+	Kind    ReportKind
+	Columns []string
+	Rows    [][]string
+}
+
+type Dashboard struct {
+	Titles          int
+	Copies          int
+	AvailableCopies int
+	Members         int
+	ActiveMembers   int
+	ActiveLoans     int
+	LoansToday      int
+	ReturnsToday    int
+	Overdue         int
+	// Issued and Returned hold one count per local calendar day, oldest
+	// first, ending with today.
+	Issued   []int
+	Returned []int
+}
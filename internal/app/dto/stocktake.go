@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+
+type ScanResult struct {
+	Barcode string
+	Shelf   string
+	CopyID  string
+	Status  copy.Status
+	Known   bool
+	Repeat  bool
+}
+
+type StocktakeItem struct {
+	CopyID  string
+	BookID  string
+	Barcode string
+	Shelf   string
+}
+
+// Reconciliation compares a session's scans with the copy records. Missing
+// are available copies nobody scanned, OnLoan are scanned copies still
+// recorded as loaned (missed returns) and Unknown are barcodes with no copy.
+type Reconciliation struct {
+	SessionID string
+	Scanned   int
+	Matched   int
+	Missing   []StocktakeItem
+	OnLoan    []StocktakeItem
+	Unknown   []StocktakeItem
+}
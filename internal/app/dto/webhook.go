@@ -0,0 +1,7 @@
+package dto
+
+type RegisterWebhookInput struct {
+	URL    string
+	Secret string
+	Events []string
+}
@@ -0,0 +1,205 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+)
+
+const batchSize = 100
+
+var ErrDuplicateSubscriber = errors.New("subscriber already registered")
+
+type Message struct {
+	Seq   int64
+	ID    string
+	Event event.Event
+}
+
+type Handler func(ctx context.Context, msg Message) error
+
+type subscriber struct {
+	name    string
+	types   map[event.Type]bool
+	handler Handler
+}
+
+func (s subscriber) wants(t event.Type) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// Bus appends published events to the outbox and then hands them to every
+// subscriber in sequence order. Each subscriber has a persisted cursor, so a
+// handler that fails, or a process that stops, resumes from the first
+// unhandled event on the next Publish or CatchUp.
+type Bus struct {
+	outbox ports.OutboxRepository
+	idGen  ports.IDGenerator
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	subscribers []subscriber
+}
+
+func New(outbox ports.OutboxRepository, idGen ports.IDGenerator, logger *slog.Logger) *Bus {
+	return &Bus{outbox: outbox, idGen: idGen, logger: logger}
+}
+
+// Subscribe registers a handler under a stable name. A subscriber seen for
+// the first time starts at the current head of the outbox; with no types it
+// receives every event.
+func (b *Bus) Subscribe(ctx context.Context, name string, handler Handler, types ...event.Type) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range b.subscribers {
+		if s.name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateSubscriber, name)
+		}
+	}
+
+	if _, ok, err := b.outbox.Cursor(ctx, name); err != nil {
+		return err
+	} else if !ok {
+		head, err := b.outbox.Head(ctx)
+		if err != nil {
+			return err
+		}
+		if err := b.outbox.SaveCursor(ctx, name, head); err != nil {
+			return err
+		}
+	}
+
+	sub := subscriber{name: name, handler: handler, types: map[event.Type]bool{}}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+	b.subscribers = append(b.subscribers, sub)
+	return nil
+}
+
+// delivering marks the context handed to subscribers, so events they
+// publish are left to the CatchUp already running.
+type delivering struct{}
+
+// Publish appends events to the outbox and delivers them. Services publish
+// after their own write is committed, so an outbox that cannot be written
+// is logged rather than failing an operation that already happened.
+func (b *Bus) Publish(ctx context.Context, events ...event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	envelopes := make([]event.Envelope, 0, len(events))
+	for _, e := range events {
+		env, err := event.Wrap(b.idGen.NewID(), e)
+		if err != nil {
+			return err
+		}
+		envelopes = append(envelopes, env)
+	}
+
+	if _, err := b.outbox.Append(ctx, envelopes); err != nil {
+		types := make([]event.Type, 0, len(envelopes))
+		for _, env := range envelopes {
+			types = append(types, env.Type)
+		}
+		b.logger.Error("events not recorded", "types", types, "error", err)
+		return nil
+	}
+
+	if ctx.Value(delivering{}) != nil {
+		return nil
+	}
+	b.CatchUp(ctx)
+	return nil
+}
+
+func (b *Bus) Since(ctx context.Context, seq int64, limit int) ([]event.Envelope, error) {
+	return b.outbox.After(ctx, seq, limit)
+}
+
+// CatchUp delivers every pending outbox event to every subscriber. Handler
+// failures are logged and retried on the next call; they never fail the
+// operation that published the event. Events published by a handler are
+// delivered before CatchUp returns.
+func (b *Bus) CatchUp(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ctx = context.WithValue(ctx, delivering{}, true)
+	for {
+		head, err := b.outbox.Head(ctx)
+		if err != nil {
+			b.logger.Warn("event outbox unavailable", "error", err)
+			return
+		}
+
+		for _, s := range b.subscribers {
+			if err := b.deliver(ctx, s); err != nil {
+				b.logger.Warn("event subscriber behind", "subscriber", s.name, "error", err)
+			}
+		}
+
+		if next, err := b.outbox.Head(ctx); err != nil || next == head {
+			return
+		}
+	}
+}
+
+// deliver hands a subscriber every pending event it wants. The cursor is
+// saved once the drain stops, after the last event handled, rather than
+// after every event; a process that stops part way through hands the
+// unsaved events over again.
+func (b *Bus) deliver(ctx context.Context, s subscriber) error {
+	start, _, err := b.outbox.Cursor(ctx, s.name)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := b.drain(ctx, s, start)
+	if cursor != start {
+		if serr := b.outbox.SaveCursor(ctx, s.name, cursor); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// drain returns the sequence number of the last event it got past.
+func (b *Bus) drain(ctx context.Context, s subscriber, cursor int64) (int64, error) {
+	for {
+		pending, err := b.outbox.After(ctx, cursor, batchSize)
+		if err != nil {
+			return cursor, err
+		}
+		if len(pending) == 0 {
+			return cursor, nil
+		}
+
+		for _, env := range pending {
+			if s.wants(env.Type) {
+				if err := b.handle(ctx, s, env); err != nil {
+					return cursor, err
+				}
+			}
+			cursor = env.Seq
+		}
+	}
+}
+
+func (b *Bus) handle(ctx context.Context, s subscriber, env event.Envelope) error {
+	e, err := env.Decode()
+	if err != nil {
+		return err
+	}
+	if err := s.handler(ctx, Message{Seq: env.Seq, ID: env.ID, Event: e}); err != nil {
+		return fmt.Errorf("handle event %d: %w", env.Seq, err)
+	}
+	return nil
+}
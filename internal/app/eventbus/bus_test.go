@@ -0,0 +1,246 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/eventbus"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
+)
+
+type seqID struct {
+	n int
+}
+
+func (g *seqID) NewID() string {
+	g.n++
+	return "evt-" + strconv.Itoa(g.n)
+}
+
+func TestBusDeliversInOrderWithFilters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bus := newBus(t, openStore(t, filepath.Join(t.TempDir(), "storage.json")))
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var all, loans []event.Type
+	if err := bus.Subscribe(ctx, "all", func(_ context.Context, msg eventbus.Message) error {
+		all = append(all, msg.Event.Type())
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := bus.Subscribe(ctx, "loans", func(_ context.Context, msg eventbus.Message) error {
+		if _, ok := msg.Event.(event.LoanIssued); !ok {
+			t.Errorf("expected typed LoanIssued got %T", msg.Event)
+		}
+		loans = append(loans, msg.Event.Type())
+		return nil
+	}, event.TypeLoanIssued); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := bus.Subscribe(ctx, "all", nil); !errors.Is(err, eventbus.ErrDuplicateSubscriber) {
+		t.Fatalf("expected duplicate subscriber error got %v", err)
+	}
+
+	err := bus.Publish(ctx,
+		event.LoanIssued{Loan: loan.Loan{ID: "l-1"}, At: at},
+		event.MemberStatusChanged{MemberID: "m-1", From: member.StatusActive, To: member.StatusBlocked, At: at},
+	)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(all) != 2 || all[0] != event.TypeLoanIssued || all[1] != event.TypeMemberStatusChanged {
+		t.Fatalf("unexpected events for all: %v", all)
+	}
+	if len(loans) != 1 {
+		t.Fatalf("unexpected events for loans: %v", loans)
+	}
+}
+
+func TestBusCatchesUpAfterFailureAndRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "storage.json")
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	store := openStore(t, path)
+	bus := newBus(t, store)
+	failing := true
+	var seen []int64
+	handler := func(_ context.Context, msg eventbus.Message) error {
+		if failing {
+			return errors.New("receiver down")
+		}
+		seen = append(seen, msg.Seq)
+		return nil
+	}
+	if err := bus.Subscribe(ctx, "audit", handler); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := bus.Publish(ctx, event.BookArchived{BookID: "b-" + strconv.Itoa(i), At: at}); err != nil {
+			t.Fatalf("publish should not fail when a subscriber fails: %v", err)
+		}
+	}
+	if len(seen) != 0 {
+		t.Fatalf("expected no deliveries while failing got %v", seen)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("close store: %v", err)
+	}
+	restarted := newBus(t, openStore(t, path))
+	failing = false
+	if err := restarted.Subscribe(ctx, "audit", handler); err != nil {
+		t.Fatalf("subscribe after restart: %v", err)
+	}
+	restarted.CatchUp(ctx)
+
+	if len(seen) != 3 || seen[0] != 1 || seen[2] != 3 {
+		t.Fatalf("expected events 1..3 after catch up got %v", seen)
+	}
+
+	var late []int64
+	if err := restarted.Subscribe(ctx, "late", func(_ context.Context, msg eventbus.Message) error {
+		late = append(late, msg.Seq)
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe late: %v", err)
+	}
+	restarted.CatchUp(ctx)
+	if len(late) != 0 {
+		t.Fatalf("expected new subscriber to start at head got %v", late)
+	}
+}
+
+func TestBusDeliversEventsPublishedByHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bus := newBus(t, openStore(t, filepath.Join(t.TempDir(), "storage.json")))
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var audit []event.Type
+	if err := bus.Subscribe(ctx, "audit", func(_ context.Context, msg eventbus.Message) error {
+		audit = append(audit, msg.Event.Type())
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := bus.Subscribe(ctx, "blocks", func(ctx context.Context, msg eventbus.Message) error {
+		return bus.Publish(ctx, event.MemberStatusChanged{MemberID: "m-1", From: member.StatusBlocked, To: member.StatusActive, At: at})
+	}, event.TypeLoanReturned); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	if err := bus.Publish(ctx, event.LoanReturned{Loan: loan.Loan{ID: "l-1", MemberID: "m-1"}, At: at}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(audit) != 2 || audit[1] != event.TypeMemberStatusChanged {
+		t.Fatalf("expected the handler's event to reach every subscriber, got %v", audit)
+	}
+}
+
+// countingOutbox counts cursor saves, each of which rewrites the store.
+type countingOutbox struct {
+	ports.OutboxRepository
+	saves int
+}
+
+func (o *countingOutbox) SaveCursor(ctx context.Context, subscriber string, seq int64) error {
+	o.saves++
+	return o.OutboxRepository.SaveCursor(ctx, subscriber, seq)
+}
+
+func TestBusSavesCursorOncePerDrain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	outbox := &countingOutbox{OutboxRepository: jsonstore.NewOutboxRepository(openStore(t, filepath.Join(t.TempDir(), "storage.json")))}
+	bus := eventbus.New(outbox, &seqID{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var seen []int64
+	if err := bus.Subscribe(ctx, "audit", func(_ context.Context, msg eventbus.Message) error {
+		seen = append(seen, msg.Seq)
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	outbox.saves = 0
+
+	events := make([]event.Event, 0, 250)
+	for i := range 250 {
+		events = append(events, event.BookArchived{BookID: "b-" + strconv.Itoa(i), At: at})
+	}
+	if err := bus.Publish(ctx, events...); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if len(seen) != 250 || outbox.saves != 1 {
+		t.Fatalf("expected 250 events and one cursor save got %d events and %d saves", len(seen), outbox.saves)
+	}
+	if cursor, _, _ := outbox.Cursor(ctx, "audit"); cursor != 250 {
+		t.Fatalf("expected the cursor at 250 got %d", cursor)
+	}
+}
+
+// brokenOutbox fails every append, as a full disk would.
+type brokenOutbox struct {
+	ports.OutboxRepository
+}
+
+func (brokenOutbox) Append(context.Context, []event.Envelope) ([]event.Envelope, error) {
+	return nil, errors.New("disk full")
+}
+
+func TestBusLogsEventsItCannotRecord(t *testing.T) {
+	t.Parallel()
+
+	store := openStore(t, filepath.Join(t.TempDir(), "storage.json"))
+
+	var logs strings.Builder
+	bus := eventbus.New(brokenOutbox{jsonstore.NewOutboxRepository(store)}, &seqID{}, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	if err := bus.Publish(context.Background(), event.LoanIssued{Loan: loan.Loan{ID: "l-1"}, At: at}); err != nil {
+		t.Fatalf("expected a committed operation not to fail got %v", err)
+	}
+	if !strings.Contains(logs.String(), "events not recorded") || !strings.Contains(logs.String(), "disk full") {
+		t.Fatalf("expected the lost events to be logged got %q", logs.String())
+	}
+}
+
+func newBus(t *testing.T, store *jsonstore.Store) *eventbus.Bus {
+	t.Helper()
+
+	return eventbus.New(jsonstore.NewOutboxRepository(store), &seqID{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func openStore(t *testing.T, path string) *jsonstore.Store {
+	t.Helper()
+
+	store, err := jsonstore.Open(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
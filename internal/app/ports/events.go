@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...event.Event) error
+}
+
+type OutboxRepository interface {
+	Append(ctx context.Context, envelopes []event.Envelope) ([]event.Envelope, error)
+	After(ctx context.Context, seq int64, limit int) ([]event.Envelope, error)
+	Head(ctx context.Context) (int64, error)
+	Cursor(ctx context.Context, subscriber string) (int64, bool, error)
+	SaveCursor(ctx context.Context, subscriber string, seq int64) error
+}
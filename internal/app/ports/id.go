@@ -1,5 +1,13 @@
 package ports
 
+import "context"
+
 type IDGenerator interface {
 	NewID() string
 }
+
+// SequenceRepository hands out increasing numbers per named counter. Next
+// never returns less than floor.
+type SequenceRepository interface {
+	Next(ctx context.Context, name string, floor int64) (int64, error)
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/branch"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/stocktake"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
 )
 
 type BookRepository interface {
 	Save(ctx context.Context, b book.Book) error
 	GetByID(ctx context.Context, id string) (book.Book, error)
+	GetByISBN(ctx context.Context, isbn string) (book.Book, error)
 	List(ctx context.Context) ([]book.Book, error)
 }
 
@@ -25,6 +30,7 @@ type CopyRepository interface {
 type MemberRepository interface {
 	Save(ctx context.Context, m member.Member) error
 	GetByID(ctx context.Context, id string) (member.Member, error)
+	GetByCardNumber(ctx context.Context, card string) (member.Member, error)
 	List(ctx context.Context) ([]member.Member, error)
 }
 
@@ -34,3 +40,27 @@ type LoanRepository interface {
 	CountActiveByMemberID(ctx context.Context, memberID string) (int, error)
 	List(ctx context.Context) ([]loan.Loan, error)
 }
+
+type HoldRepository interface {
+	Save(ctx context.Context, h hold.Hold) error
+	GetByID(ctx context.Context, id string) (hold.Hold, error)
+	List(ctx context.Context) ([]hold.Hold, error)
+}
+
+type StocktakeRepository interface {
+	Save(ctx context.Context, s stocktake.Session) error
+	GetByID(ctx context.Context, id string) (stocktake.Session, error)
+	List(ctx context.Context) ([]stocktake.Session, error)
+}
+
+type BranchRepository interface {
+	Save(ctx context.Context, b branch.Branch) error
+	GetByCode(ctx context.Context, code string) (branch.Branch, error)
+	List(ctx context.Context) ([]branch.Branch, error)
+}
+
+type TransferRepository interface {
+	Save(ctx context.Context, t transfer.Transfer) error
+	GetByID(ctx context.Context, id string) (transfer.Transfer, error)
+	List(ctx context.Context) ([]transfer.Transfer, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
+)
+
+type WebhookRepository interface {
+	SaveEndpoint(ctx context.Context, e webhook.Endpoint) error
+	GetEndpoint(ctx context.Context, id string) (webhook.Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	ListEndpoints(ctx context.Context) ([]webhook.Endpoint, error)
+	SaveDelivery(ctx context.Context, d webhook.Delivery) error
+	GetDelivery(ctx context.Context, id string) (webhook.Delivery, error)
+	ListDeliveries(ctx context.Context) ([]webhook.Delivery, error)
+}
+
+// WebhookSender posts a payload and reports the response status. A non-nil
+// error means no response was received.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, header http.Header, body []byte) (int, error)
+}
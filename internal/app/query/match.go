@@ -0,0 +1,198 @@
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var ErrUnknownField = errors.New("unknown search field")
+
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindNumber
+	KindDate
+	KindBool
+)
+
+type Field[T any] struct {
+	Kind   Kind
+	Text   func(T) []string
+	Number func(T) float64
+	Date   func(T) time.Time
+	Bool   func(T) bool
+}
+
+type Fields[T any] map[string]Field[T]
+
+func TextField[T any](get func(T) []string) Field[T] {
+	return Field[T]{Kind: KindText, Text: get}
+}
+
+func NumberField[T any](get func(T) float64) Field[T] {
+	return Field[T]{Kind: KindNumber, Number: get}
+}
+
+func DateField[T any](get func(T) time.Time) Field[T] {
+	return Field[T]{Kind: KindDate, Date: get}
+}
+
+func BoolField[T any](get func(T) bool) Field[T] {
+	return Field[T]{Kind: KindBool, Bool: get}
+}
+
+func (f Fields[T]) Names() []string {
+	out := make([]string, 0, len(f))
+	for name := range f {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+type Predicate[T any] func(T) bool
+
+// Compile binds q to the given fields. Terms without a field are matched as
+// case-insensitive substrings against freeText.
+func Compile[T any](q Query, fields Fields[T], freeText func(T) []string) (Predicate[T], error) {
+	preds := make([]Predicate[T], 0, len(q.Terms))
+	for _, t := range q.Terms {
+		p, err := compileTerm(t, fields, freeText)
+		if err != nil {
+			return nil, err
+		}
+		if t.Negate {
+			inner := p
+			p = func(v T) bool { return !inner(v) }
+		}
+		preds = append(preds, p)
+	}
+
+	return func(v T) bool {
+		for _, p := range preds {
+			if !p(v) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func Filter[T any](items []T, p Predicate[T]) []T {
+	out := make([]T, 0, len(items))
+	for _, it := range items {
+		if p(it) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func compileTerm[T any](t Term, fields Fields[T], freeText func(T) []string) (Predicate[T], error) {
+	if t.Field == "" {
+		return textPredicate(freeText, OpContains, t.Value)
+	}
+
+	f, ok := fields[t.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (try %s)", ErrUnknownField, t.Field, strings.Join(fields.Names(), ", "))
+	}
+
+	switch f.Kind {
+	case KindNumber:
+		return numberPredicate(t, f.Number)
+	case KindDate:
+		return datePredicate(t, f.Date)
+	case KindBool:
+		return boolPredicate(t, f.Bool)
+	default:
+		if t.Op != OpContains && t.Op != OpEqual {
+			return nil, fmt.Errorf("%w: %q does not support %s", ErrSyntax, t.Field, t.Op)
+		}
+		return textPredicate(f.Text, t.Op, t.Value)
+	}
+}
+
+func textPredicate[T any](get func(T) []string, op Op, value string) (Predicate[T], error) {
+	want := strings.ToLower(strings.TrimSpace(value))
+	return func(v T) bool {
+		for _, s := range get(v) {
+			s = strings.ToLower(s)
+			if op == OpEqual && s == want {
+				return true
+			}
+			if op == OpContains && strings.Contains(s, want) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func numberPredicate[T any](t Term, get func(T) float64) (Predicate[T], error) {
+	want, err := strconv.ParseFloat(strings.TrimSpace(t.Value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects a number", ErrSyntax, t.Field)
+	}
+
+	return func(v T) bool {
+		return compareOp(cmp.Compare(get(v), want), t.Op)
+	}, nil
+}
+
+func datePredicate[T any](t Term, get func(T) time.Time) (Predicate[T], error) {
+	want, err := time.Parse(dateLayout, strings.TrimSpace(t.Value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects a date like %s", ErrSyntax, t.Field, dateLayout)
+	}
+
+	return func(v T) bool {
+		got := get(v)
+		if got.IsZero() {
+			return false
+		}
+		day := time.Date(got.Year(), got.Month(), got.Day(), 0, 0, 0, 0, time.UTC)
+		return compareOp(day.Compare(want), t.Op)
+	}, nil
+}
+
+func boolPredicate[T any](t Term, get func(T) bool) (Predicate[T], error) {
+	if t.Op != OpContains && t.Op != OpEqual {
+		return nil, fmt.Errorf("%w: %q does not support %s", ErrSyntax, t.Field, t.Op)
+	}
+
+	var want bool
+	switch strings.ToLower(strings.TrimSpace(t.Value)) {
+	case "true", "yes", "1":
+		want = true
+	case "false", "no", "0":
+		want = false
+	default:
+		return nil, fmt.Errorf("%w: %q expects true or false", ErrSyntax, t.Field)
+	}
+
+	return func(v T) bool { return get(v) == want }, nil
+}
+
+func compareOp(c int, op Op) bool {
+	switch op {
+	case OpGreater:
+		return c > 0
+	case OpGreaterE:
+		return c >= 0
+	case OpLess:
+		return c < 0
+	case OpLessE:
+		return c <= 0
+	default:
+		return c == 0
+	}
+}
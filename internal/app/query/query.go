@@ -0,0 +1,179 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type Op string
+
+const (
+	OpContains Op = ""
+	OpEqual    Op = "="
+	OpGreater  Op = ">"
+	OpGreaterE Op = ">="
+	OpLess     Op = "<"
+	OpLessE    Op = "<="
+)
+
+var ErrSyntax = errors.New("invalid search query")
+
+type Term struct {
+	Field  string
+	Op     Op
+	Value  string
+	Negate bool
+}
+
+type Query struct {
+	Terms []Term
+}
+
+func (q Query) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
+
+func (q Query) FreeText() string {
+	parts := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		if t.Field == "" && !t.Negate {
+			parts = append(parts, t.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (q Query) WithoutFreeText() Query {
+	out := Query{Terms: make([]Term, 0, len(q.Terms))}
+	for _, t := range q.Terms {
+		if t.Field == "" && !t.Negate {
+			continue
+		}
+		out.Terms = append(out.Terms, t)
+	}
+	return out
+}
+
+func Parse(input string) (Query, error) {
+	p := parser{src: []rune(input)}
+
+	var q Query
+	for {
+		p.skipSpace()
+		if p.done() {
+			return q, nil
+		}
+
+		t, err := p.term()
+		if err != nil {
+			return Query{}, err
+		}
+		q.Terms = append(q.Terms, t)
+	}
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) term() (Term, error) {
+	var t Term
+	if p.peek() == '-' {
+		t.Negate = true
+		p.pos++
+		if p.done() || unicode.IsSpace(p.peek()) {
+			return Term{}, fmt.Errorf("%w: '-' must be followed by a term", ErrSyntax)
+		}
+	}
+
+	if p.peek() == '"' {
+		v, err := p.quoted()
+		if err != nil {
+			return Term{}, err
+		}
+		t.Value = v
+		return t, nil
+	}
+
+	word := p.word()
+	field, rest, hasField := strings.Cut(word, ":")
+	if !hasField {
+		t.Value = word
+		return t, nil
+	}
+
+	if field == "" {
+		return Term{}, fmt.Errorf("%w: missing field name before ':'", ErrSyntax)
+	}
+	t.Field = strings.ToLower(field)
+	t.Op, rest = splitOp(rest)
+
+	if rest == "" && p.peek() == '"' {
+		v, err := p.quoted()
+		if err != nil {
+			return Term{}, err
+		}
+		rest = v
+	}
+
+	if strings.TrimSpace(rest) == "" {
+		return Term{}, fmt.Errorf("%w: missing value for %q", ErrSyntax, t.Field)
+	}
+	t.Value = rest
+	return t, nil
+}
+
+func (p *parser) word() string {
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.peek()) && p.peek() != '"' {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) quoted() (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.done() && p.peek() != '"' {
+		p.pos++
+	}
+	if p.done() {
+		return "", fmt.Errorf("%w: unterminated quote", ErrSyntax)
+	}
+
+	v := string(p.src[start:p.pos])
+	p.pos++
+	if strings.TrimSpace(v) == "" {
+		return "", fmt.Errorf("%w: empty quoted phrase", ErrSyntax)
+	}
+	return v, nil
+}
+
+func splitOp(v string) (Op, string) {
+	for _, op := range []Op{OpGreaterE, OpLessE, OpGreater, OpLess, OpEqual} {
+		if strings.HasPrefix(v, string(op)) {
+			return op, strings.TrimPrefix(v, string(op))
+		}
+	}
+	return OpContains, v
+}
@@ -0,0 +1,122 @@
+package query_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+)
+
+type record struct {
+	author  string
+	year    int
+	active  bool
+	dueDate time.Time
+}
+
+var fields = query.Fields[record]{
+	"author": query.TextField(func(r record) []string { return []string{r.author} }),
+	"year":   query.NumberField(func(r record) float64 { return float64(r.year) }),
+	"active": query.BoolField(func(r record) bool { return r.active }),
+	"due":    query.DateField(func(r record) time.Time { return r.dueDate }),
+}
+
+func text(r record) []string {
+	return []string{r.author}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	q, err := query.Parse(`author:"alan donovan" year:>=2010 -active:true go`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want := []query.Term{
+		{Field: "author", Op: query.OpContains, Value: "alan donovan"},
+		{Field: "year", Op: query.OpGreaterE, Value: "2010"},
+		{Field: "active", Op: query.OpContains, Value: "true", Negate: true},
+		{Value: "go"},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("expected %d terms got %+v", len(want), q.Terms)
+	}
+	for i := range want {
+		if q.Terms[i] != want[i] {
+			t.Fatalf("term %d: expected %+v got %+v", i, want[i], q.Terms[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{`title:"open`, `year:`, `:go`, `- go`} {
+		if _, err := query.Parse(in); !errors.Is(err, query.ErrSyntax) {
+			t.Fatalf("%q: expected syntax error got %v", in, err)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+	r := record{author: "Alan Donovan", year: 2015, active: true, dueDate: due}
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"author:donovan", true},
+		{"author:=donovan", false},
+		{"year:>2010 active:true", true},
+		{"year:<2010", false},
+		{"-author:kernighan", true},
+		{`"alan don"`, true},
+		{"due:2026-03-01", true},
+		{"due:<2026-03-01", false},
+		{"kernighan", false},
+	}
+
+	for _, tc := range tests {
+		q, err := query.Parse(tc.in)
+		if err != nil {
+			t.Fatalf("%q: parse: %v", tc.in, err)
+		}
+		pred, err := query.Compile(q, fields, text)
+		if err != nil {
+			t.Fatalf("%q: compile: %v", tc.in, err)
+		}
+		if got := pred(r); got != tc.want {
+			t.Fatalf("%q: expected %v got %v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"publisher:acme", query.ErrUnknownField},
+		{"year:abc", query.ErrSyntax},
+		{"active:maybe", query.ErrSyntax},
+		{"author:>b", query.ErrSyntax},
+		{"due:yesterday", query.ErrSyntax},
+	}
+
+	for _, tc := range tests {
+		q, err := query.Parse(tc.in)
+		if err != nil {
+			t.Fatalf("%q: parse: %v", tc.in, err)
+		}
+		if _, err := query.Compile(q, fields, text); !errors.Is(err, tc.want) {
+			t.Fatalf("%q: expected %v got %v", tc.in, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldAuthor
+	fieldISBN
+	fieldCategory
+	fieldPublisher
+)
+
+var fieldWeights = map[field]float64{
+	fieldTitle:     3,
+	fieldAuthor:    2.5,
+	fieldISBN:      4,
+	fieldCategory:  1.5,
+	fieldPublisher: 1,
+}
+
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.8
+	fuzzyMatch  = 0.6
+	phraseBonus = 2.0
+)
+
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+type document struct {
+	title string
+	terms map[string]field
+}
+
+// Index is an in-memory inverted index over book catalog fields. It is safe
+// for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	loaded   bool
+	docs     map[string]document
+	postings map[string]map[string]field
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     map[string]document{},
+		postings: map[string]map[string]field{},
+	}
+}
+
+func (ix *Index) Loaded() bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.loaded
+}
+
+func (ix *Index) Load(books []book.Book) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.docs = make(map[string]document, len(books))
+	ix.postings = map[string]map[string]field{}
+	for _, b := range books {
+		ix.put(b)
+	}
+	ix.loaded = true
+}
+
+func (ix *Index) Put(b book.Book) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(b.ID)
+	ix.put(b)
+}
+
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+func (ix *Index) Search(q string, limit int) []Hit {
+	tokens := queryTokens(q)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	scores := map[string]float64{}
+	for i, tok := range tokens {
+		matched := ix.scoreToken(tok)
+		if i == 0 {
+			scores = matched
+			continue
+		}
+		for id := range scores {
+			s, ok := matched[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] += s
+		}
+	}
+
+	phrase := strings.Join(tokens, " ")
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		if len(tokens) > 1 && strings.Contains(ix.docs[id].title, phrase) {
+			s += phraseBonus
+		}
+		hits = append(hits, Hit{ID: id, Score: s})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+func (ix *Index) scoreToken(tok string) map[string]float64 {
+	out := map[string]float64{}
+	maxEdits := allowedEdits(tok)
+
+	for term, docs := range ix.postings {
+		quality := 0.0
+		switch {
+		case term == tok:
+			quality = exactMatch
+		case len(tok) >= 2 && strings.HasPrefix(term, tok):
+			quality = prefixMatch
+		case maxEdits > 0:
+			if d := shared.Distance(tok, term, maxEdits); d <= maxEdits {
+				quality = fuzzyMatch / float64(d)
+			} else if d := shared.Distance(tok, runePrefix(term, len([]rune(tok))), maxEdits); d <= maxEdits {
+				quality = prefixMatch * fuzzyMatch / float64(d)
+			}
+		}
+		if quality == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + float64(len(ix.docs))/float64(len(docs)))
+		for id, f := range docs {
+			s := quality * fieldWeights[f] * idf
+			if s > out[id] {
+				out[id] = s
+			}
+		}
+	}
+
+	return out
+}
+
+func allowedEdits(tok string) int {
+	switch n := len([]rune(tok)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (ix *Index) put(b book.Book) {
+	doc := document{title: strings.Join(Tokenize(b.Title), " "), terms: map[string]field{}}
+
+	add := func(f field, values ...string) {
+		for _, v := range values {
+			for _, tok := range Tokenize(v) {
+				if cur, ok := doc.terms[tok]; ok && fieldWeights[cur] >= fieldWeights[f] {
+					continue
+				}
+				doc.terms[tok] = f
+			}
+		}
+	}
+
+	add(fieldTitle, b.Title)
+	add(fieldAuthor, b.Authors...)
+	add(fieldCategory, b.Category)
+	add(fieldPublisher, b.Publisher)
+	if isbn := isbnDigits(b.ISBN); isbn != "" {
+		doc.terms[isbn] = fieldISBN
+		if isbn10, err := book.ISBN13To10(isbn); err == nil {
+			doc.terms[strings.ToLower(isbn10)] = fieldISBN
+		}
+	}
+
+	ix.docs[b.ID] = doc
+	for tok, f := range doc.terms {
+		if ix.postings[tok] == nil {
+			ix.postings[tok] = map[string]field{}
+		}
+		ix.postings[tok][b.ID] = f
+	}
+}
+
+func (ix *Index) remove(id string) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+
+	for tok := range doc.terms {
+		delete(ix.postings[tok], id)
+		if len(ix.postings[tok]) == 0 {
+			delete(ix.postings, tok)
+		}
+	}
+	delete(ix.docs, id)
+}
+
+func runePrefix(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+func queryTokens(q string) []string {
+	trimmed := strings.TrimSpace(q)
+	if trimmed != "" && strings.Trim(trimmed, "0123456789Xx- ") == "" {
+		if digits := isbnDigits(trimmed); len(digits) == 10 || len(digits) == 13 {
+			return []string{digits}
+		}
+	}
+	return Tokenize(q)
+}
+
+func isbnDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= '0' && r <= '9') || r == 'X' || r == 'x' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.ToLower(b.String())
+}
@@ -0,0 +1,93 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/search"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+)
+
+func catalog() []book.Book {
+	return []book.Book{
+		{ID: "b-1", Title: "The Go Programming Language", Authors: []string{"Alan Donovan", "Brian Kernighan"}, ISBN: "9780134190440", Category: "Programming"},
+		{ID: "b-2", Title: "The C Programming Language", Authors: []string{"Brian Kernighan", "Dennis Ritchie"}, ISBN: "9780131103627", Category: "Programming"},
+		{ID: "b-3", Title: "Gödel, Escher, Bach", Authors: []string{"Douglas Hofstadter"}, Category: "Language Arts"},
+		{ID: "b-4", Title: "Language Instinct", Authors: []string{"Steven Pinker"}, Category: "Linguistics"},
+	}
+}
+
+func TestNormalizeFoldsAccentsAndCase(t *testing.T) {
+	t.Parallel()
+
+	if got := search.Normalize("GÖDEL Ćrème Brûlée"); got != "godel creme brulee" {
+		t.Fatalf("unexpected normalization %q", got)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	t.Parallel()
+
+	ix := search.NewIndex()
+	ix.Load(catalog())
+
+	tests := []struct {
+		name  string
+		query string
+		first string
+		count int
+	}{
+		{name: "accent insensitive", query: "godel", first: "b-3", count: 1},
+		{name: "typo tolerant", query: "kernigan", first: "b-1", count: 2},
+		{name: "prefix", query: "hofst", first: "b-3", count: 1},
+		{name: "typo in prefix", query: "progrm", first: "b-1", count: 2},
+		{name: "hyphenated isbn", query: "978-0-13-110362-7", first: "b-2", count: 1},
+		{name: "isbn-10 of stored isbn-13", query: "0-13-419044-0", first: "b-1", count: 1},
+		{name: "all terms required", query: "go kernighan", first: "b-1", count: 1},
+		{name: "no match", query: "cryptonomicon", count: 0},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hits := ix.Search(tc.query, 0)
+			if len(hits) != tc.count {
+				t.Fatalf("expected %d hits got %+v", tc.count, hits)
+			}
+			if tc.count > 0 && hits[0].ID != tc.first {
+				t.Fatalf("expected %s first got %+v", tc.first, hits)
+			}
+		})
+	}
+}
+
+func TestIndexRanksTitleAboveCategory(t *testing.T) {
+	t.Parallel()
+
+	ix := search.NewIndex()
+	ix.Load(catalog())
+
+	hits := ix.Search("language", 0)
+	if len(hits) != 4 {
+		t.Fatalf("expected 4 hits got %+v", hits)
+	}
+	if hits[len(hits)-1].ID != "b-3" {
+		t.Fatalf("expected category-only match last got %+v", hits)
+	}
+}
+
+func TestIndexPutReplacesDocument(t *testing.T) {
+	t.Parallel()
+
+	ix := search.NewIndex()
+	ix.Load(catalog())
+
+	ix.Put(book.Book{ID: "b-4", Title: "The Stuff of Thought", Authors: []string{"Steven Pinker"}})
+	if hits := ix.Search("instinct", 0); len(hits) != 0 {
+		t.Fatalf("expected stale title to be removed got %+v", hits)
+	}
+	if hits := ix.Search("thought", 0); len(hits) != 1 || hits[0].ID != "b-4" {
+		t.Fatalf("expected updated title to be indexed got %+v", hits)
+	}
+}
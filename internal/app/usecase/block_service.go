@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// autoBlocker is recorded as the creator of blocks placed by a rule, and
+// as the one who lifts them.
+const autoBlocker = "system"
+
+// BlockService blocks and unblocks members, by hand or by the automatic
+// blocking rules.
+type BlockService struct {
+	members ports.MemberRepository
+	loans   ports.LoanRepository
+	clock   ports.Clock
+	policy  member.BlockPolicy
+	events  ports.EventPublisher
+}
+
+func NewBlockService(
+	members ports.MemberRepository,
+	loans ports.LoanRepository,
+	clock ports.Clock,
+	policy member.BlockPolicy,
+	events ports.EventPublisher,
+) BlockService {
+	return BlockService{members: members, loans: loans, clock: clock, policy: policy, events: events}
+}
+
+func (s BlockService) Block(ctx context.Context, input dto.BlockMemberInput) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, input.MemberID)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	blocked, err := member.AddBlock(m, member.Block{
+		Reason:    input.Reason,
+		Note:      input.Note,
+		CreatedBy: input.CreatedBy,
+		CreatedAt: s.clock.Now(),
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return member.Member{}, shared.Invalid(err)
+	}
+
+	return blocked, s.save(ctx, m, blocked)
+}
+
+// Unblock lifts every block of the member in the name of by, automatic
+// ones included. A rule that still applies blocks the member again on the
+// next review.
+func (s BlockService) Unblock(ctx context.Context, memberID, by string) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, memberID)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	unblocked := member.Unblock(m, s.clock.Now(), by)
+	return unblocked, s.save(ctx, m, unblocked)
+}
+
+// Review applies the automatic rules to one member, for example after a
+// return, and unblocks a member whose blocks have all expired.
+func (s BlockService) Review(ctx context.Context, memberID string) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, memberID)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	now := s.clock.Now()
+	return s.apply(ctx, m, overdueDays(loans, now)[m.ID], now)
+}
+
+// ReviewAll applies the automatic rules to every member. It is meant to
+// run daily.
+func (s BlockService) ReviewAll(ctx context.Context) (dto.BlockReview, error) {
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return dto.BlockReview{}, err
+	}
+
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return dto.BlockReview{}, err
+	}
+
+	now := s.clock.Now()
+	overdue := overdueDays(loans, now)
+
+	var review dto.BlockReview
+	for _, m := range members {
+		next, err := s.apply(ctx, m, overdue[m.ID], now)
+		if err != nil {
+			return review, err
+		}
+		switch {
+		case m.Status != member.StatusBlocked && next.Status == member.StatusBlocked:
+			review.Blocked++
+		case m.Status == member.StatusBlocked && next.Status != member.StatusBlocked:
+			review.Unblocked++
+		}
+	}
+	return review, nil
+}
+
+// HandleReturn reviews the borrower of a returned loan, so a member blocked
+// for overdue items can borrow again as soon as enough come back.
+func (s BlockService) HandleReturn(ctx context.Context, e event.Event) error {
+	returned, ok := e.(event.LoanReturned)
+	if !ok {
+		return nil
+	}
+	_, err := s.Review(ctx, returned.Loan.MemberID)
+	return err
+}
+
+func (s BlockService) apply(ctx context.Context, m member.Member, daysOverdue []int, now time.Time) (member.Member, error) {
+	// Members blocked by hand before blocks were recorded are left to staff.
+	if m.MergedInto != "" || (m.Status == member.StatusBlocked && len(m.Blocks) == 0) {
+		return m, nil
+	}
+
+	reason, breached := s.policy.OverdueReason(daysOverdue)
+	next, changed := member.LiftBlocks(m, now, autoBlocker, func(b member.Block) bool {
+		return b.Rule == member.BlockRuleOverdue && !breached
+	})
+
+	if breached && !hasRule(next.ActiveBlocks(now), member.BlockRuleOverdue) {
+		var err error
+		changed = true
+		next, err = member.AddBlock(next, member.Block{
+			Reason:    reason,
+			CreatedBy: autoBlocker,
+			CreatedAt: now,
+			Rule:      member.BlockRuleOverdue,
+		})
+		if err != nil {
+			return member.Member{}, err
+		}
+	}
+
+	if !changed {
+		return m, nil
+	}
+	return next, s.save(ctx, m, next)
+}
+
+func (s BlockService) save(ctx context.Context, before, after member.Member) error {
+	if err := s.members.Save(ctx, after); err != nil {
+		return err
+	}
+
+	if after.Status != before.Status {
+		return publish(ctx, s.events, event.MemberStatusChanged{MemberID: after.ID, From: before.Status, To: after.Status, At: s.clock.Now()})
+	}
+	return nil
+}
+
+func hasRule(blocks []member.Block, rule member.BlockRule) bool {
+	for _, b := range blocks {
+		if b.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// overdueDays lists, per member, how many days each open loan is overdue.
+func overdueDays(loans []loan.Loan, now time.Time) map[string][]int {
+	out := map[string][]int{}
+	for _, l := range loans {
+		if days := l.DaysOverdue(now); days > 0 {
+			out[l.MemberID] = append(out[l.MemberID], days)
+		}
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestBlockServiceManualBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Ann", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusActive},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "C1", Status: copy.StatusAvailable, UpdatedAt: now},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{}}
+	events := &recordingPublisher{}
+	svc := usecase.NewBlockService(members, loans, stubClock{now: now}, member.BlockPolicy{}, events)
+	loanSvc := usecase.NewLoanService(loans, copies, members, stubIDGen{id: "l-1"}, stubClock{now: now}, loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1}, nil)
+
+	if _, err := svc.Block(ctx, dto.BlockMemberInput{MemberID: "m-1"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a missing reason to be invalid, got %v", err)
+	}
+
+	m, err := svc.Block(ctx, dto.BlockMemberInput{MemberID: "m-1", Reason: "unpaid damage", Note: "torn cover", CreatedBy: "amy"})
+	if err != nil {
+		t.Fatalf("block: %v", err)
+	}
+	if m.Status != member.StatusBlocked || m.Blocks[0].CreatedBy != "amy" || !m.Blocks[0].CreatedAt.Equal(now) {
+		t.Fatalf("unexpected blocked member %+v", m)
+	}
+
+	_, err = loanSvc.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"})
+	if !errors.Is(err, shared.ErrMemberNotEligible) || !strings.Contains(err.Error(), "unpaid damage") {
+		t.Fatalf("expected the block reason on issue, got %v", err)
+	}
+
+	if m, err = svc.Unblock(ctx, "m-1", "bob"); err != nil || m.Status != member.StatusActive || len(m.ActiveBlocks(now)) != 0 {
+		t.Fatalf("expected the member unblocked, got %+v %v", m, err)
+	}
+	if b := m.Blocks[0]; b.Reason != "unpaid damage" || b.LiftedBy != "bob" || !b.LiftedAt.Equal(now) {
+		t.Fatalf("expected the lifted block kept on record, got %+v", m.Blocks)
+	}
+	if want := []event.Type{event.TypeMemberStatusChanged, event.TypeMemberStatusChanged}; !reflect.DeepEqual(events.types(), want) {
+		t.Fatalf("expected %v got %v", want, events.types())
+	}
+}
+
+func TestBlockServiceOverdueRule(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	overdue := func(id, memberID string, days int) loan.Loan {
+		due := now.AddDate(0, 0, -days)
+		return loan.Loan{ID: id, CopyID: "c-" + id, MemberID: memberID, IssuedAt: due.AddDate(0, 0, -14), DueAt: due, Status: loan.StatusActive}
+	}
+
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Ann", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusActive},
+		"m-2": {ID: "m-2", Name: "Bo", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusActive},
+		"m-3": {ID: "m-3", Name: "Cy", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusBlocked},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": overdue("l-1", "m-1", 40),
+		"l-2": overdue("l-2", "m-1", 35),
+		"l-3": overdue("l-3", "m-2", 40),
+		"l-4": overdue("l-4", "m-2", 3),
+		"l-5": overdue("l-5", "m-3", 40),
+		"l-6": overdue("l-6", "m-3", 40),
+	}}
+	events := &recordingPublisher{}
+	svc := usecase.NewBlockService(members, loans, stubClock{now: now}, member.BlockPolicy{OverdueItems: 2, OverdueDays: 30}, events)
+
+	review, err := svc.ReviewAll(ctx)
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if review != (dto.BlockReview{Blocked: 1}) {
+		t.Fatalf("expected only m-1 blocked, got %+v", review)
+	}
+	ann := members.members["m-1"]
+	if ann.Status != member.StatusBlocked || ann.Blocks[0].Rule != member.BlockRuleOverdue || ann.Blocks[0].Reason != "2 items overdue more than 30 days" {
+		t.Fatalf("unexpected automatic block %+v", ann)
+	}
+	if len(members.members["m-3"].Blocks) != 0 {
+		t.Fatalf("expected a member blocked by hand without records to be left alone")
+	}
+	if review, _ := svc.ReviewAll(ctx); review != (dto.BlockReview{}) {
+		t.Fatalf("expected a second review to change nothing, got %+v", review)
+	}
+
+	if _, err := svc.Block(ctx, dto.BlockMemberInput{MemberID: "m-1", Reason: "rude to staff", CreatedBy: "amy"}); err != nil {
+		t.Fatalf("block: %v", err)
+	}
+
+	returned := now
+	l := loans.loans["l-1"]
+	l.Status, l.ReturnedAt = loan.StatusReturned, &returned
+	loans.loans["l-1"] = l
+	if err := svc.HandleReturn(ctx, event.LoanReturned{Loan: l, At: now}); err != nil {
+		t.Fatalf("handle return: %v", err)
+	}
+	ann = members.members["m-1"]
+	if ann.Status != member.StatusBlocked || ann.BlockReason(now) != "rude to staff" || ann.Blocks[0].LiftedBy != "system" {
+		t.Fatalf("expected only the automatic block lifted, got %+v", ann)
+	}
+
+	if _, err := svc.Unblock(ctx, "m-1", "amy"); err != nil {
+		t.Fatalf("unblock: %v", err)
+	}
+	if got := members.members["m-1"].Status; got != member.StatusActive {
+		t.Fatalf("expected m-1 active, got %s", got)
+	}
+}
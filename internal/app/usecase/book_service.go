@@ -3,20 +3,27 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/search"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
 type BookService struct {
-	books ports.BookRepository
-	idGen ports.IDGenerator
+	books  ports.BookRepository
+	idGen  ports.IDGenerator
+	clock  ports.Clock
+	policy book.Policy
+	events ports.EventPublisher
+	index  *search.Index
 }
 
-func NewBookService(books ports.BookRepository, idGen ports.IDGenerator) BookService {
-	return BookService{books: books, idGen: idGen}
+func NewBookService(books ports.BookRepository, idGen ports.IDGenerator, clock ports.Clock, policy book.Policy, events ports.EventPublisher) BookService {
+	return BookService{books: books, idGen: idGen, clock: clock, policy: policy, events: events, index: search.NewIndex()}
 }
 
 func (s BookService) Create(ctx context.Context, input dto.CreateBookInput) (book.Book, error) {
@@ -31,25 +38,32 @@ func (s BookService) Create(ctx context.Context, input dto.CreateBookInput) (boo
 		return book.Book{}, err
 	}
 
+	isbn, err := s.checkISBN(ctx, id, input.ISBN)
+	if err != nil {
+		return book.Book{}, err
+	}
+
 	b := book.Book{
 		ID:        id,
 		Title:     input.Title,
 		Authors:   input.Authors,
-		ISBN:      input.ISBN,
+		ISBN:      isbn,
 		Category:  input.Category,
 		Publisher: input.Publisher,
 		Year:      input.Year,
 		Status:    book.StatusActive,
+		UpdatedAt: s.clock.Now(),
 	}
 
 	if err := b.Validate(); err != nil {
-		return book.Book{}, err
+		return book.Book{}, shared.Invalid(err)
 	}
 
 	if err := s.books.Save(ctx, b); err != nil {
 		return book.Book{}, err
 	}
 
+	s.index.Put(b)
 	return b, nil
 }
 
@@ -59,44 +73,77 @@ func (s BookService) Update(ctx context.Context, input dto.UpdateBookInput) (boo
 		return book.Book{}, err
 	}
 
+	isbn := b.ISBN
+	if strings.TrimSpace(input.ISBN) != b.ISBN {
+		isbn, err = s.checkISBN(ctx, b.ID, input.ISBN)
+		if err != nil {
+			return book.Book{}, err
+		}
+	}
+
+	from := b.Status
 	b.Title = input.Title
 	b.Authors = input.Authors
-	b.ISBN = input.ISBN
+	b.ISBN = isbn
 	b.Category = input.Category
 	b.Publisher = input.Publisher
 	b.Year = input.Year
+	if status := book.Status(strings.ToLower(strings.TrimSpace(input.Status))); status != "" {
+		b.Status = status
+	}
+	b.UpdatedAt = s.clock.Now()
 
 	if err := b.Validate(); err != nil {
-		return book.Book{}, err
+		return book.Book{}, shared.Invalid(err)
 	}
 
 	if err := s.books.Save(ctx, b); err != nil {
 		return book.Book{}, err
 	}
 
-	return b, nil
+	s.index.Put(b)
+	return b, s.publishArchived(ctx, from, b)
 }
 
 func (s BookService) GetByID(ctx context.Context, id string) (book.Book, error) {
 	return s.books.GetByID(ctx, id)
 }
 
+func (s BookService) GetByISBN(ctx context.Context, isbn string) (book.Book, error) {
+	normalized, err := book.NormalizeISBN(isbn)
+	if err != nil {
+		return book.Book{}, err
+	}
+
+	return s.books.GetByISBN(ctx, normalized)
+}
+
 func (s BookService) SetStatus(ctx context.Context, id string, status book.Status) (book.Book, error) {
 	b, err := s.books.GetByID(ctx, id)
 	if err != nil {
 		return book.Book{}, err
 	}
 
+	from := b.Status
 	b.Status = status
+	b.UpdatedAt = s.clock.Now()
 	if err := b.Validate(); err != nil {
-		return book.Book{}, err
+		return book.Book{}, shared.Invalid(err)
 	}
 
 	if err := s.books.Save(ctx, b); err != nil {
 		return book.Book{}, err
 	}
 
-	return b, nil
+	s.index.Put(b)
+	return b, s.publishArchived(ctx, from, b)
+}
+
+func (s BookService) publishArchived(ctx context.Context, from book.Status, b book.Book) error {
+	if from != book.StatusArchived && b.Status == book.StatusArchived {
+		return publish(ctx, s.events, event.BookArchived{BookID: b.ID, Title: b.Title, At: b.UpdatedAt})
+	}
+	return nil
 }
 
 func (s BookService) Archive(ctx context.Context, id string) (book.Book, error) {
@@ -106,3 +153,64 @@ func (s BookService) Archive(ctx context.Context, id string) (book.Book, error)
 func (s BookService) List(ctx context.Context) ([]book.Book, error) {
 	return s.books.List(ctx)
 }
+
+func (s BookService) Search(ctx context.Context, query string, limit int) ([]book.Book, error) {
+	if err := s.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	hits := s.index.Search(query, limit)
+	out := make([]book.Book, 0, len(hits))
+	for _, h := range hits {
+		b, err := s.books.GetByID(ctx, h.ID)
+		if errors.Is(err, shared.ErrNotFound) {
+			s.index.Remove(h.ID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+
+	return out, nil
+}
+
+func (s BookService) checkISBN(ctx context.Context, id, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+
+	isbn, err := book.NormalizeISBN(raw)
+	if err != nil {
+		return "", shared.Invalid(err)
+	}
+
+	if !s.policy.UniqueISBN {
+		return isbn, nil
+	}
+
+	existing, err := s.books.GetByISBN(ctx, isbn)
+	if err == nil && existing.ID != id {
+		return "", shared.ErrDuplicateISBN
+	}
+	if err != nil && !errors.Is(err, shared.ErrNotFound) {
+		return "", err
+	}
+
+	return isbn, nil
+}
+
+func (s BookService) ensureIndex(ctx context.Context) error {
+	if s.index.Loaded() {
+		return nil
+	}
+
+	books, err := s.books.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.index.Load(books)
+	return nil
+}
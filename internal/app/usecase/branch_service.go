@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/branch"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type BranchService struct {
+	branches ports.BranchRepository
+	clock    ports.Clock
+}
+
+func NewBranchService(branches ports.BranchRepository, clock ports.Clock) BranchService {
+	return BranchService{branches: branches, clock: clock}
+}
+
+// Add registers a branch. Codes are stored upper-case.
+func (s BranchService) Add(ctx context.Context, input dto.AddBranchInput) (branch.Branch, error) {
+	b := branch.Branch{
+		Code:      strings.ToUpper(strings.TrimSpace(input.Code)),
+		Name:      strings.TrimSpace(input.Name),
+		CreatedAt: s.clock.Now(),
+	}
+	if err := b.Validate(); err != nil {
+		return branch.Branch{}, shared.Invalid(err)
+	}
+
+	if _, err := s.branches.GetByCode(ctx, b.Code); err == nil {
+		return branch.Branch{}, shared.ErrDuplicateBranch
+	} else if !errors.Is(err, shared.ErrNotFound) {
+		return branch.Branch{}, err
+	}
+
+	if err := s.branches.Save(ctx, b); err != nil {
+		return branch.Branch{}, err
+	}
+
+	return b, nil
+}
+
+func (s BranchService) GetByCode(ctx context.Context, code string) (branch.Branch, error) {
+	return s.branches.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func (s BranchService) List(ctx context.Context) ([]branch.Branch, error) {
+	items, err := s.branches.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Code < items[j].Code })
+	return items, nil
+}
@@ -8,16 +8,25 @@ import (
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
+// barcodeAttempts bounds the search for a generated barcode that is not
+// already taken by a hand-entered one.
+const barcodeAttempts = 1000
+
 type CopyService struct {
-	copies ports.CopyRepository
-	idGen  ports.IDGenerator
+	copies    ports.CopyRepository
+	sequences ports.SequenceRepository
+	idGen     ports.IDGenerator
+	clock     ports.Clock
+	policy    copy.Policy
+	events    ports.EventPublisher
 }
 
-func NewCopyService(copies ports.CopyRepository, idGen ports.IDGenerator) CopyService {
-	return CopyService{copies: copies, idGen: idGen}
+func NewCopyService(copies ports.CopyRepository, sequences ports.SequenceRepository, idGen ports.IDGenerator, clock ports.Clock, policy copy.Policy, events ports.EventPublisher) CopyService {
+	return CopyService{copies: copies, sequences: sequences, idGen: idGen, clock: clock, policy: policy, events: events}
 }
 
 func (s CopyService) Create(ctx context.Context, input dto.CreateCopyInput) (copy.Copy, error) {
@@ -32,24 +41,35 @@ func (s CopyService) Create(ctx context.Context, input dto.CreateCopyInput) (cop
 		return copy.Copy{}, err
 	}
 
-	if strings.TrimSpace(input.Barcode) != "" {
-		if _, err := s.copies.GetByBarcode(ctx, input.Barcode); err == nil {
+	barcode := input.Barcode
+	if strings.TrimSpace(barcode) != "" {
+		if _, err := s.copies.GetByBarcode(ctx, barcode); err == nil {
 			return copy.Copy{}, shared.ErrDuplicateBarcode
 		} else if !errors.Is(err, shared.ErrNotFound) {
 			return copy.Copy{}, err
 		}
+	} else if s.autoBarcode() {
+		next, err := s.nextBarcode(ctx)
+		if err != nil {
+			return copy.Copy{}, err
+		}
+		barcode = next
 	}
 
 	c := copy.Copy{
 		ID:            id,
 		BookID:        input.BookID,
-		Barcode:       input.Barcode,
+		Barcode:       barcode,
 		Status:        copy.StatusAvailable,
 		ConditionNote: input.ConditionNote,
+		Branch:        strings.ToUpper(strings.TrimSpace(input.Branch)),
+		Location:      strings.TrimSpace(input.Location),
+		CallNumber:    strings.TrimSpace(input.CallNumber),
+		UpdatedAt:     s.clock.Now(),
 	}
 
 	if err := c.Validate(); err != nil {
-		return copy.Copy{}, err
+		return copy.Copy{}, shared.Invalid(err)
 	}
 
 	if err := s.copies.Save(ctx, c); err != nil {
@@ -75,25 +95,80 @@ func (s CopyService) Update(ctx context.Context, input dto.UpdateCopyInput) (cop
 		}
 	}
 
+	from := c.Status
 	c.Barcode = input.Barcode
 	c.ConditionNote = input.ConditionNote
+	c.Branch = strings.ToUpper(strings.TrimSpace(input.Branch))
+	c.Location = strings.TrimSpace(input.Location)
+	c.CallNumber = strings.TrimSpace(input.CallNumber)
 	c.Status = copy.Status(strings.ToLower(strings.TrimSpace(input.Status)))
+	c.UpdatedAt = s.clock.Now()
 
 	if err := c.Validate(); err != nil {
-		return copy.Copy{}, err
+		return copy.Copy{}, shared.Invalid(err)
 	}
 
 	if err := s.copies.Save(ctx, c); err != nil {
 		return copy.Copy{}, err
 	}
 
+	if c.Status != from {
+		return c, publish(ctx, s.events, event.CopyStatusChanged{CopyID: c.ID, BookID: c.BookID, From: from, To: c.Status, At: c.UpdatedAt})
+	}
 	return c, nil
 }
 
+// AssignBarcode gives a copy without a barcode the next generated one. The
+// copy is returned unchanged when it already has a barcode or automatic
+// barcodes are off.
+func (s CopyService) AssignBarcode(ctx context.Context, id string) (copy.Copy, error) {
+	c, err := s.copies.GetByID(ctx, id)
+	if err != nil {
+		return copy.Copy{}, err
+	}
+	if strings.TrimSpace(c.Barcode) != "" || !s.autoBarcode() {
+		return c, nil
+	}
+
+	if c.Barcode, err = s.nextBarcode(ctx); err != nil {
+		return copy.Copy{}, err
+	}
+	c.UpdatedAt = s.clock.Now()
+	if err := s.copies.Save(ctx, c); err != nil {
+		return copy.Copy{}, err
+	}
+	return c, nil
+}
+
+func (s CopyService) autoBarcode() bool {
+	return s.policy.AutoBarcode && s.sequences != nil
+}
+
+func (s CopyService) nextBarcode(ctx context.Context) (string, error) {
+	for range barcodeAttempts {
+		n, err := s.sequences.Next(ctx, s.policy.SequenceName(), s.policy.BarcodeStart)
+		if err != nil {
+			return "", err
+		}
+
+		barcode := s.policy.Barcode(n)
+		if _, err := s.copies.GetByBarcode(ctx, barcode); errors.Is(err, shared.ErrNotFound) {
+			return barcode, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("no free barcode in sequence " + s.policy.SequenceName())
+}
+
 func (s CopyService) GetByID(ctx context.Context, id string) (copy.Copy, error) {
 	return s.copies.GetByID(ctx, id)
 }
 
+func (s CopyService) GetByBarcode(ctx context.Context, barcode string) (copy.Copy, error) {
+	return s.copies.GetByBarcode(ctx, barcode)
+}
+
 func (s CopyService) List(ctx context.Context) ([]copy.Copy, error) {
 	return s.copies.List(ctx)
 }
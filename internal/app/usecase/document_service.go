@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// DocumentService gathers what goes on receipts and overdue letters;
+// rendering is left to the caller.
+type DocumentService struct {
+	books   BookService
+	copies  CopyService
+	members MemberService
+	loans   LoanService
+	clock   ports.Clock
+}
+
+func NewDocumentService(books BookService, copies CopyService, members MemberService, loans LoanService, clock ports.Clock) DocumentService {
+	return DocumentService{books: books, copies: copies, members: members, loans: loans, clock: clock}
+}
+
+// Receipt lists the given loans of one member. A return receipt only
+// accepts returned loans.
+func (s DocumentService) Receipt(ctx context.Context, kind dto.ReceiptKind, loanIDs []string) (dto.Receipt, error) {
+	if kind != dto.ReceiptCheckout && kind != dto.ReceiptReturn {
+		return dto.Receipt{}, shared.Invalid(fmt.Errorf("unknown receipt kind %q", kind))
+	}
+	if len(loanIDs) == 0 {
+		return dto.Receipt{}, shared.Invalid(errors.New("at least one loan is required"))
+	}
+
+	loans := make([]loan.Loan, 0, len(loanIDs))
+	for _, id := range loanIDs {
+		l, err := s.loans.GetByID(ctx, id)
+		if err != nil {
+			return dto.Receipt{}, err
+		}
+		if len(loans) > 0 && l.MemberID != loans[0].MemberID {
+			return dto.Receipt{}, shared.Invalid(errors.New("loans belong to different members"))
+		}
+		if kind == dto.ReceiptReturn && l.ReturnedAt == nil {
+			return dto.Receipt{}, shared.Invalid(fmt.Errorf("loan %s is not returned", l.ID))
+		}
+		loans = append(loans, l)
+	}
+
+	return s.receipt(ctx, kind, loans)
+}
+
+// ReceiptFor builds the receipt a desk would hand over for one loan: the
+// member's loans issued the same day while it is out, or returned the same
+// day once it is back.
+func (s DocumentService) ReceiptFor(ctx context.Context, loanID string) (dto.Receipt, error) {
+	l, err := s.loans.GetByID(ctx, loanID)
+	if err != nil {
+		return dto.Receipt{}, err
+	}
+
+	kind, day := dto.ReceiptCheckout, l.IssuedAt
+	if l.ReturnedAt != nil {
+		kind, day = dto.ReceiptReturn, *l.ReturnedAt
+	}
+
+	all, err := s.loans.List(ctx)
+	if err != nil {
+		return dto.Receipt{}, err
+	}
+
+	var loans []loan.Loan
+	for _, other := range all {
+		if other.MemberID != l.MemberID {
+			continue
+		}
+		switch {
+		case kind == dto.ReceiptReturn && other.ReturnedAt != nil && sameDay(*other.ReturnedAt, day),
+			kind == dto.ReceiptCheckout && other.ReturnedAt == nil && sameDay(other.IssuedAt, day):
+			loans = append(loans, other)
+		}
+	}
+
+	return s.receipt(ctx, kind, loans)
+}
+
+// OverdueNotices builds one letter per member with overdue loans, limited
+// to memberIDs when any are given and to copies owned by branch when set.
+func (s DocumentService) OverdueNotices(ctx context.Context, branch string, memberIDs []string) ([]dto.OverdueNotice, error) {
+	groups, err := s.loans.OverdueByMember(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, id := range memberIDs {
+		wanted[id] = true
+	}
+
+	titles := s.titles(ctx)
+	now := s.clock.Now()
+	var out []dto.OverdueNotice
+	for _, g := range groups {
+		if len(wanted) > 0 && !wanted[g.Member.ID] {
+			continue
+		}
+
+		n := dto.OverdueNotice{Member: g.Member, At: now}
+		for _, it := range g.Items {
+			item, err := s.item(ctx, it.Loan, titles)
+			if err != nil {
+				return nil, err
+			}
+			item.DaysOverdue = it.DaysOverdue
+			item.HoursOverdue = it.Loan.HoursOverdue(now)
+			n.Items = append(n.Items, item)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func (s DocumentService) receipt(ctx context.Context, kind dto.ReceiptKind, loans []loan.Loan) (dto.Receipt, error) {
+	if len(loans) == 0 {
+		return dto.Receipt{}, shared.ErrNotFound
+	}
+
+	m, err := s.members.GetByID(ctx, loans[0].MemberID)
+	if err != nil {
+		return dto.Receipt{}, err
+	}
+
+	sort.SliceStable(loans, func(i, j int) bool { return loans[i].DueAt.Before(loans[j].DueAt) })
+
+	titles := s.titles(ctx)
+	r := dto.Receipt{Kind: kind, Member: m, At: s.clock.Now()}
+	for _, l := range loans {
+		item, err := s.item(ctx, l, titles)
+		if err != nil {
+			return dto.Receipt{}, err
+		}
+		r.Items = append(r.Items, item)
+	}
+	return r, nil
+}
+
+func (s DocumentService) item(ctx context.Context, l loan.Loan, titles map[string]string) (dto.DocumentItem, error) {
+	item := dto.DocumentItem{LoanID: l.ID, IssuedAt: l.IssuedAt, DueAt: l.DueAt, ReturnedAt: l.ReturnedAt, Short: l.IsShort()}
+	if l.ReturnedAt != nil {
+		open := l
+		open.ReturnedAt = nil
+		item.DaysOverdue = open.DaysOverdue(*l.ReturnedAt)
+		item.HoursOverdue = open.HoursOverdue(*l.ReturnedAt)
+	}
+
+	c, err := s.copies.GetByID(ctx, l.CopyID)
+	if errors.Is(err, shared.ErrNotFound) {
+		return item, nil
+	}
+	if err != nil {
+		return dto.DocumentItem{}, err
+	}
+
+	item.Barcode = c.Barcode
+	item.Title = titles[c.BookID]
+	return item, nil
+}
+
+func (s DocumentService) titles(ctx context.Context) map[string]string {
+	books, _ := s.books.List(ctx)
+	titles := make(map[string]string, len(books))
+	for _, b := range books {
+		titles[b.ID] = b.Title
+	}
+	return titles
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
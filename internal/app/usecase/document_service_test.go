@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestDocumentService(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+	returned := at(20, 9)
+
+	books := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Go", Status: book.StatusActive},
+		"b-2": {ID: "b-2", Title: "Rust", Status: book.StatusActive},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "C001", Status: copy.StatusLoaned},
+		"c-2": {ID: "c-2", BookID: "b-2", Barcode: "C002", Status: copy.StatusLoaned},
+		"c-3": {ID: "c-3", BookID: "b-1", Barcode: "C003", Status: copy.StatusLoaned},
+		"c-4": {ID: "c-4", BookID: "b-2", Barcode: "C004", Status: copy.StatusAvailable},
+		"c-5": {ID: "c-5", BookID: "b-1", Barcode: "C005", Status: copy.StatusLoaned},
+	}}
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Joe", Status: member.StatusActive},
+		"m-2": {ID: "m-2", Name: "Ann", Status: member.StatusActive},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", CopyID: "c-1", MemberID: "m-1", IssuedAt: at(20, 10), DueAt: at(20, 10).AddDate(0, 0, 14), Status: loan.StatusActive},
+		"l-2": {ID: "l-2", CopyID: "c-2", MemberID: "m-1", IssuedAt: at(20, 11), DueAt: at(20, 10).AddDate(0, 0, 12), Status: loan.StatusActive},
+		"l-3": {ID: "l-3", CopyID: "c-3", MemberID: "m-1", IssuedAt: at(1, 10), DueAt: at(15, 10), Status: loan.StatusActive},
+		"l-4": {ID: "l-4", CopyID: "c-4", MemberID: "m-1", IssuedAt: at(4, 10), DueAt: at(18, 10), ReturnedAt: &returned, Status: loan.StatusReturned},
+		"l-5": {ID: "l-5", CopyID: "c-5", MemberID: "m-2", IssuedAt: at(1, 10), DueAt: at(10, 10), Status: loan.StatusActive},
+	}}
+
+	clock := stubClock{now: now}
+	svc := usecase.NewDocumentService(
+		usecase.NewBookService(books, stubIDGen{id: "ignored"}, clock, book.Policy{}, nil),
+		usecase.NewCopyService(copies, nil, stubIDGen{id: "ignored"}, clock, copy.Policy{}, nil),
+		usecase.NewMemberService(members, nil, stubIDGen{id: "ignored"}, clock, member.Policy{}, nil),
+		usecase.NewLoanService(loans, copies, members, stubIDGen{id: "ignored"}, clock, loan.Policy{LoanDays: 14, MaxLoansPerMember: 5, MaxRenewals: 1}, nil),
+		clock,
+	)
+
+	checkout, err := svc.ReceiptFor(ctx, "l-1")
+	if err != nil {
+		t.Fatalf("checkout receipt: %v", err)
+	}
+	if checkout.Kind != dto.ReceiptCheckout || checkout.Member.Name != "Joe" || !checkout.At.Equal(now) {
+		t.Fatalf("unexpected checkout receipt %+v", checkout)
+	}
+	// Only today's loans, soonest due first; the overdue loan from earlier
+	// in the month is left off.
+	if got := receiptLines(checkout.Items); !reflect.DeepEqual(got, []string{"l-2 C002 Rust", "l-1 C001 Go"}) {
+		t.Fatalf("unexpected checkout items %v", got)
+	}
+
+	ret, err := svc.ReceiptFor(ctx, "l-4")
+	if err != nil {
+		t.Fatalf("return receipt: %v", err)
+	}
+	if ret.Kind != dto.ReceiptReturn || len(ret.Items) != 1 || ret.Items[0].DaysOverdue != 2 {
+		t.Fatalf("expected a return receipt with one item two days late, got %+v", ret)
+	}
+
+	invalid := []struct {
+		kind  dto.ReceiptKind
+		loans []string
+	}{
+		{kind: dto.ReceiptReturn, loans: []string{"l-1"}},
+		{kind: dto.ReceiptCheckout, loans: []string{"l-1", "l-5"}},
+		{kind: dto.ReceiptCheckout},
+		{kind: "renewal", loans: []string{"l-1"}},
+	}
+	for _, tt := range invalid {
+		if _, err := svc.Receipt(ctx, tt.kind, tt.loans); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Fatalf("%s %v: expected invalid input, got %v", tt.kind, tt.loans, err)
+		}
+	}
+
+	notices, err := svc.OverdueNotices(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("notices: %v", err)
+	}
+	if len(notices) != 2 || notices[0].Member.ID != "m-2" || notices[1].Member.ID != "m-1" {
+		t.Fatalf("expected a notice for each member, oldest first, got %+v", notices)
+	}
+
+	notices, err = svc.OverdueNotices(ctx, "", []string{"m-1"})
+	if err != nil {
+		t.Fatalf("notices for m-1: %v", err)
+	}
+	if len(notices) != 1 || receiptLines(notices[0].Items)[0] != "l-3 C003 Go" || notices[0].Items[0].DaysOverdue != 6 {
+		t.Fatalf("expected one notice for m-1, got %+v", notices)
+	}
+}
+
+func receiptLines(items []dto.DocumentItem) []string {
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		out = append(out, it.LoanID+" "+it.Barcode+" "+it.Title)
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// DuplicateService finds members registered twice and merges them.
+type DuplicateService struct {
+	members ports.MemberRepository
+	loans   ports.LoanRepository
+	holds   ports.HoldRepository
+	clock   ports.Clock
+	events  ports.EventPublisher
+}
+
+func NewDuplicateService(
+	members ports.MemberRepository,
+	loans ports.LoanRepository,
+	holds ports.HoldRepository,
+	clock ports.Clock,
+	events ports.EventPublisher,
+) DuplicateService {
+	return DuplicateService{members: members, loans: loans, holds: holds, clock: clock, events: events}
+}
+
+// Find lists likely duplicate pairs, best match first.
+func (s DuplicateService) Find(ctx context.Context) ([]member.Duplicate, error) {
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return member.FindDuplicates(members, member.DuplicateThreshold), nil
+}
+
+// Merge moves every loan and hold of FromID to IntoID and deactivates
+// FromID. Loans keep their IDs and dates and note the member they were
+// first lent to, and the merged record stays on file pointing at the
+// survivor, so past activity can still be traced. The members are saved
+// first; a merge that stops part way is finished by running it again,
+// while merging a finished pair again is refused.
+func (s DuplicateService) Merge(ctx context.Context, input dto.MergeMembersInput) (dto.MergeResult, error) {
+	from, err := s.members.GetByID(ctx, input.FromID)
+	if err != nil {
+		return dto.MergeResult{}, err
+	}
+	into, err := s.members.GetByID(ctx, input.IntoID)
+	if err != nil {
+		return dto.MergeResult{}, err
+	}
+
+	merged, kept := from, into
+	resumed := from.MergedInto != "" && from.MergedInto == into.ID
+	if !resumed {
+		merged, kept, err = member.Merge(from, into)
+		if err != nil {
+			return dto.MergeResult{}, shared.Invalid(err)
+		}
+		if err := s.members.Save(ctx, kept); err != nil {
+			return dto.MergeResult{}, err
+		}
+		if err := s.members.Save(ctx, merged); err != nil {
+			return dto.MergeResult{}, err
+		}
+	}
+
+	result := dto.MergeResult{Member: kept, Merged: merged}
+
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return dto.MergeResult{}, err
+	}
+	for _, l := range loans {
+		if l.MemberID != from.ID {
+			continue
+		}
+		if l.MergedFrom == "" {
+			l.MergedFrom = from.ID
+		}
+		l.MemberID = into.ID
+		if err := s.loans.Save(ctx, l); err != nil {
+			return dto.MergeResult{}, err
+		}
+		result.Loans++
+	}
+
+	holds, err := s.holds.List(ctx)
+	if err != nil {
+		return dto.MergeResult{}, err
+	}
+	waiting := map[string]bool{}
+	for _, h := range holds {
+		if h.MemberID == into.ID && h.IsWaiting() {
+			waiting[h.BookID] = true
+		}
+	}
+	now := s.clock.Now()
+	for _, h := range holds {
+		if h.MemberID != from.ID {
+			continue
+		}
+		if h.IsWaiting() && waiting[h.BookID] {
+			cancelled, err := hold.Cancel(h, now)
+			if err != nil {
+				return dto.MergeResult{}, err
+			}
+			if err := s.holds.Save(ctx, cancelled); err != nil {
+				return dto.MergeResult{}, err
+			}
+			result.CancelledHolds++
+			continue
+		}
+
+		if h.MergedFrom == "" {
+			h.MergedFrom = from.ID
+		}
+		h.MemberID = into.ID
+		if err := s.holds.Save(ctx, h); err != nil {
+			return dto.MergeResult{}, err
+		}
+		result.Holds++
+	}
+
+	if resumed && result.Loans == 0 && result.Holds == 0 && result.CancelledHolds == 0 {
+		return dto.MergeResult{}, shared.Invalid(fmt.Errorf("member %s was already merged into %s", from.ID, into.ID))
+	}
+
+	events := []event.Event{event.MemberMerged{From: from.ID, Into: into.ID, Loans: result.Loans, Holds: result.Holds, At: now}}
+	if merged.Status != from.Status {
+		events = append(events, event.MemberStatusChanged{MemberID: from.ID, From: from.Status, To: merged.Status, At: now})
+	}
+	return result, publish(ctx, s.events, events...)
+}
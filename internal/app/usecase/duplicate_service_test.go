@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestDuplicateServiceFindAndMerge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	returned := now.AddDate(0, 0, -3)
+
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Joe Smith", Email: "joe.smith@example.com", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusActive},
+		"m-2": {ID: "m-2", Name: "Joe  Smith", Email: "joe.smiht@example.com", Phone: "555 0101", JoinedAt: now.AddDate(0, -1, 0), Status: member.StatusActive},
+		"m-3": {ID: "m-3", Name: "Ann Lee", JoinedAt: now, Status: member.StatusActive},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", CopyID: "c-1", MemberID: "m-2", IssuedAt: now.AddDate(0, 0, -10), DueAt: now.AddDate(0, 0, 4), Status: loan.StatusActive},
+		"l-2": {ID: "l-2", CopyID: "c-2", MemberID: "m-2", IssuedAt: now.AddDate(0, 0, -20), DueAt: now.AddDate(0, 0, -6), ReturnedAt: &returned, Status: loan.StatusReturned},
+		"l-3": {ID: "l-3", CopyID: "c-3", MemberID: "m-3", IssuedAt: now.AddDate(0, 0, -1), DueAt: now.AddDate(0, 0, 13), Status: loan.StatusActive},
+	}}
+	holds := &holdRepo{holds: map[string]hold.Hold{
+		"h-1": {ID: "h-1", BookID: "b-1", MemberID: "m-1", PlacedAt: now, Status: hold.StatusWaiting},
+		"h-2": {ID: "h-2", BookID: "b-1", MemberID: "m-2", PlacedAt: now, Status: hold.StatusWaiting},
+		"h-3": {ID: "h-3", BookID: "b-2", MemberID: "m-2", PlacedAt: now, Status: hold.StatusWaiting},
+	}}
+	events := &recordingPublisher{}
+	svc := usecase.NewDuplicateService(members, loans, holds, stubClock{now: now}, events)
+
+	dups, err := svc.Find(ctx)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(dups) != 1 || dups[0].Keep.ID != "m-1" || dups[0].Merge.ID != "m-2" {
+		t.Fatalf("expected m-2 to be listed as a duplicate of m-1, got %+v", dups)
+	}
+	if want := []string{"same name", "similar email"}; !reflect.DeepEqual(dups[0].Reasons, want) {
+		t.Fatalf("expected reasons %v got %v", want, dups[0].Reasons)
+	}
+
+	res, err := svc.Merge(ctx, dto.MergeMembersInput{FromID: "m-2", IntoID: "m-1"})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if res.Loans != 2 || res.Holds != 1 || res.CancelledHolds != 1 {
+		t.Fatalf("unexpected merge counts %+v", res)
+	}
+	if res.Member.Phone != "555 0101" || res.Merged.Status != member.StatusInactive || res.Merged.MergedInto != "m-1" {
+		t.Fatalf("unexpected merged members %+v", res)
+	}
+
+	for _, id := range []string{"l-1", "l-2"} {
+		if l := loans.loans[id]; l.MemberID != "m-1" || l.MergedFrom != "m-2" {
+			t.Fatalf("expected loan %s to move to m-1, got %+v", id, l)
+		}
+	}
+	if l := loans.loans["l-2"]; l.ReturnedAt == nil || !l.ReturnedAt.Equal(returned) {
+		t.Fatalf("expected loan history to be kept, got %+v", l)
+	}
+	if h := holds.holds["h-2"]; h.Status != hold.StatusCancelled || h.MemberID != "m-2" {
+		t.Fatalf("expected the duplicate hold to be cancelled, got %+v", h)
+	}
+	if h := holds.holds["h-3"]; h.MemberID != "m-1" || h.MergedFrom != "m-2" || !h.IsWaiting() {
+		t.Fatalf("expected the other hold to move, got %+v", h)
+	}
+	if want := []event.Type{event.TypeMemberMerged, event.TypeMemberStatusChanged}; !reflect.DeepEqual(events.types(), want) {
+		t.Fatalf("expected events %v got %v", want, events.types())
+	}
+
+	if dups, _ := svc.Find(ctx); len(dups) != 0 {
+		t.Fatalf("expected no duplicates after the merge, got %+v", dups)
+	}
+	if _, err := svc.Merge(ctx, dto.MergeMembersInput{FromID: "m-2", IntoID: "m-3"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected merging twice to be refused, got %v", err)
+	}
+
+	// A merge that stopped after saving the members is finished by running
+	// it again.
+	loans.loans["l-4"] = loan.Loan{ID: "l-4", CopyID: "c-4", MemberID: "m-2", IssuedAt: now, DueAt: now.AddDate(0, 0, 14), Status: loan.StatusActive}
+	res, err = svc.Merge(ctx, dto.MergeMembersInput{FromID: "m-2", IntoID: "m-1"})
+	if err != nil || res.Loans != 1 || loans.loans["l-4"].MemberID != "m-1" || loans.loans["l-4"].MergedFrom != "m-2" {
+		t.Fatalf("expected the rerun to move the leftover loan, got %+v %v", res, err)
+	}
+	if want := []event.Type{event.TypeMemberMerged, event.TypeMemberStatusChanged, event.TypeMemberMerged}; !reflect.DeepEqual(events.types(), want) {
+		t.Fatalf("expected events %v got %v", want, events.types())
+	}
+	if _, err := svc.Merge(ctx, dto.MergeMembersInput{FromID: "m-2", IntoID: "m-1"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a finished merge to be refused, got %v", err)
+	}
+
+	memberSvc := usecase.NewMemberService(members, nil, stubIDGen{id: "ignored"}, stubClock{now: now}, member.Policy{}, nil)
+	if _, err := memberSvc.SetStatus(ctx, "m-2", member.StatusActive); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a merged member to stay inactive, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+)
+
+func publish(ctx context.Context, p ports.EventPublisher, events ...event.Event) error {
+	if p == nil || len(events) == 0 {
+		return nil
+	}
+	return p.Publish(ctx, events...)
+}
@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, events ...event.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func (p *recordingPublisher) types() []event.Type {
+	out := make([]event.Type, 0, len(p.events))
+	for _, e := range p.events {
+		out = append(out, e.Type())
+	}
+	return out
+}
+
+func TestServicesPublishDomainEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	pub := &recordingPublisher{}
+
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable},
+		"c-2": {ID: "c-2", BookID: "b-1", Status: copy.StatusAvailable},
+	}}
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Joe", JoinedAt: now, Status: member.StatusActive},
+	}}
+	books := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Go", Authors: []string{"A"}, Status: book.StatusActive},
+	}}
+	clock := stubClock{now: now}
+
+	loans := usecase.NewLoanService(
+		&loanRepo{loans: map[string]loan.Loan{}},
+		copies,
+		members,
+		stubIDGen{id: "l-1"},
+		clock,
+		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		pub,
+	)
+	memberSvc := usecase.NewMemberService(members, nil, stubIDGen{id: "ignored"}, clock, member.Policy{}, pub)
+	bookSvc := usecase.NewBookService(books, stubIDGen{id: "ignored"}, clock, book.Policy{}, pub)
+	copySvc := usecase.NewCopyService(copies, nil, stubIDGen{id: "ignored"}, clock, copy.Policy{}, pub)
+
+	if _, err := loans.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"}); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := loans.Renew(ctx, dto.RenewLoanInput{LoanID: "l-1"}); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if _, err := loans.Return(ctx, dto.ReturnLoanInput{LoanID: "l-1"}); err != nil {
+		t.Fatalf("return: %v", err)
+	}
+	if _, err := memberSvc.SetStatus(ctx, "m-1", member.StatusInactive); err != nil {
+		t.Fatalf("deactivate member: %v", err)
+	}
+	if _, err := memberSvc.SetStatus(ctx, "m-1", member.StatusInactive); err != nil {
+		t.Fatalf("deactivate member again: %v", err)
+	}
+	if _, err := memberSvc.SetStatus(ctx, "m-1", member.StatusBlocked); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected blocking without a reason to be refused, got %v", err)
+	}
+	if _, err := copySvc.Update(ctx, dto.UpdateCopyInput{ID: "c-2", Status: "lost"}); err != nil {
+		t.Fatalf("lose copy: %v", err)
+	}
+	if _, err := copySvc.Update(ctx, dto.UpdateCopyInput{ID: "c-2", Status: "lost", ConditionNote: "noted"}); err != nil {
+		t.Fatalf("update lost copy: %v", err)
+	}
+	if _, err := bookSvc.Archive(ctx, "b-1"); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	want := []event.Type{
+		event.TypeLoanIssued,
+		event.TypeCopyStatusChanged,
+		event.TypeLoanRenewed,
+		event.TypeLoanReturned,
+		event.TypeCopyStatusChanged,
+		event.TypeMemberStatusChanged,
+		event.TypeCopyStatusChanged,
+		event.TypeBookArchived,
+	}
+	got := pub.types()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v got %v", want, got)
+		}
+	}
+
+	lost := pub.events[6].(event.CopyStatusChanged)
+	if lost.CopyID != "c-2" || lost.From != copy.StatusAvailable || lost.To != copy.StatusLost {
+		t.Fatalf("unexpected copy status event %+v", lost)
+	}
+}
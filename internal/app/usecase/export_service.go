@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+type ExportService struct {
+	books  BookService
+	copies CopyService
+}
+
+func NewExportService(books BookService, copies CopyService) ExportService {
+	return ExportService{books: books, copies: copies}
+}
+
+// Holdings returns every title with its copies. With a non-zero since only
+// titles whose record or copies changed after that instant are returned;
+// records without a change stamp are always included.
+func (s ExportService) Holdings(ctx context.Context, since time.Time) ([]dto.Holding, error) {
+	books, err := s.books.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := s.copies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byBook := map[string][]copy.Copy{}
+	for _, c := range copies {
+		byBook[c.BookID] = append(byBook[c.BookID], c)
+	}
+
+	out := make([]dto.Holding, 0, len(books))
+	for _, b := range books {
+		h := dto.Holding{Book: b, Copies: byBook[b.ID]}
+		sort.Slice(h.Copies, func(i, j int) bool { return h.Copies[i].ID < h.Copies[j].ID })
+
+		if !since.IsZero() && !changedSince(h, since) {
+			continue
+		}
+		out = append(out, h)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Book.ID < out[j].Book.ID })
+	return out, nil
+}
+
+func changedSince(h dto.Holding, since time.Time) bool {
+	if h.Book.UpdatedAt.IsZero() {
+		return true
+	}
+	for _, c := range h.Copies {
+		if c.UpdatedAt.IsZero() {
+			return true
+		}
+	}
+	return h.ChangedAt().After(since)
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+func TestExportServiceHoldingsSince(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	books := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Old", Authors: []string{"A"}, Status: book.StatusActive, UpdatedAt: base},
+		"b-2": {ID: "b-2", Title: "Edited", Authors: []string{"B"}, Status: book.StatusActive, UpdatedAt: base.AddDate(0, 0, 5)},
+		"b-3": {ID: "b-3", Title: "Copy moved", Authors: []string{"C"}, Status: book.StatusActive, UpdatedAt: base},
+		"b-4": {ID: "b-4", Title: "Legacy", Authors: []string{"D"}, Status: book.StatusActive},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable, UpdatedAt: base},
+		"c-2": {ID: "c-2", BookID: "b-3", Status: copy.StatusLoaned, UpdatedAt: base.AddDate(0, 0, 3)},
+	}}
+
+	svc := usecase.NewExportService(
+		usecase.NewBookService(books, stubIDGen{id: "ignored"}, stubClock{}, book.Policy{}, nil),
+		usecase.NewCopyService(copies, nil, stubIDGen{id: "ignored"}, stubClock{}, copy.Policy{}, nil),
+	)
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string
+	}{
+		{name: "full export", want: []string{"b-1", "b-2", "b-3", "b-4"}},
+		{name: "incremental", since: base.AddDate(0, 0, 1), want: []string{"b-2", "b-3", "b-4"}},
+		{name: "nothing newer", since: base.AddDate(0, 0, 10), want: []string{"b-4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			holdings, err := svc.Holdings(context.Background(), tt.since)
+			if err != nil {
+				t.Fatalf("holdings: %v", err)
+			}
+
+			got := make([]string, 0, len(holdings))
+			for _, h := range holdings {
+				got = append(got, h.Book.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBookServiceStampsUpdatedAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	repo := &bookRepo{books: map[string]book.Book{}}
+	svc := usecase.NewBookService(repo, stubIDGen{id: "b-1"}, stubClock{now: now}, book.Policy{}, nil)
+
+	created, err := svc.Create(context.Background(), dto.CreateBookInput{Title: "Stamped", Authors: []string{"A"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !created.UpdatedAt.Equal(now) {
+		t.Fatalf("expected updated at %v got %v", now, created.UpdatedAt)
+	}
+}
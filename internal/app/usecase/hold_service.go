@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+)
+
+type HoldService struct {
+	holds   ports.HoldRepository
+	books   ports.BookRepository
+	members ports.MemberRepository
+	idGen   ports.IDGenerator
+	clock   ports.Clock
+}
+
+func NewHoldService(
+	holds ports.HoldRepository,
+	books ports.BookRepository,
+	members ports.MemberRepository,
+	idGen ports.IDGenerator,
+	clock ports.Clock,
+) HoldService {
+	return HoldService{holds: holds, books: books, members: members, idGen: idGen, clock: clock}
+}
+
+func (s HoldService) Place(ctx context.Context, input dto.PlaceHoldInput) (hold.Hold, error) {
+	b, err := s.books.GetByID(ctx, input.BookID)
+	if err != nil {
+		return hold.Hold{}, err
+	}
+
+	m, err := s.members.GetByID(ctx, input.MemberID)
+	if err != nil {
+		return hold.Hold{}, err
+	}
+
+	existing, err := s.ListByMember(ctx, m.ID)
+	if err != nil {
+		return hold.Hold{}, err
+	}
+
+	if err := hold.CanPlace(b, m, existing, s.clock.Now()); err != nil {
+		return hold.Hold{}, err
+	}
+
+	h := hold.Hold{
+		ID:       s.idGen.NewID(),
+		BookID:   b.ID,
+		MemberID: m.ID,
+		PlacedAt: s.clock.Now(),
+		Status:   hold.StatusWaiting,
+	}
+	if err := s.holds.Save(ctx, h); err != nil {
+		return hold.Hold{}, err
+	}
+
+	return h, nil
+}
+
+func (s HoldService) Cancel(ctx context.Context, id string) (hold.Hold, error) {
+	current, err := s.holds.GetByID(ctx, id)
+	if err != nil {
+		return hold.Hold{}, err
+	}
+
+	cancelled, err := hold.Cancel(current, s.clock.Now())
+	if err != nil {
+		return hold.Hold{}, err
+	}
+
+	if err := s.holds.Save(ctx, cancelled); err != nil {
+		return hold.Hold{}, err
+	}
+
+	return cancelled, nil
+}
+
+func (s HoldService) GetByID(ctx context.Context, id string) (hold.Hold, error) {
+	return s.holds.GetByID(ctx, id)
+}
+
+func (s HoldService) ListByMember(ctx context.Context, memberID string) ([]hold.Hold, error) {
+	return s.list(ctx, func(h hold.Hold) bool { return h.MemberID == memberID })
+}
+
+// Queue returns the waiting holds on a title in the order they were placed.
+func (s HoldService) Queue(ctx context.Context, bookID string) ([]hold.Hold, error) {
+	return s.list(ctx, func(h hold.Hold) bool { return h.BookID == bookID && h.IsWaiting() })
+}
+
+func (s HoldService) List(ctx context.Context) ([]hold.Hold, error) {
+	return s.list(ctx, func(hold.Hold) bool { return true })
+}
+
+func (s HoldService) list(ctx context.Context, keep func(hold.Hold) bool) ([]hold.Hold, error) {
+	all, err := s.holds.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]hold.Hold, 0)
+	for _, h := range all {
+		if keep(h) {
+			out = append(out, h)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].PlacedAt.Equal(out[j].PlacedAt) {
+			return out[i].PlacedAt.Before(out[j].PlacedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestHoldServicePlace(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	newService := func() usecase.HoldService {
+		books := &bookRepo{books: map[string]book.Book{
+			"b-1": {ID: "b-1", Title: "Go", Authors: []string{"A"}, Status: book.StatusActive},
+			"b-2": {ID: "b-2", Title: "Old", Authors: []string{"B"}, Status: book.StatusArchived},
+		}}
+		members := &memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Name: "Ann", JoinedAt: now, Status: member.StatusActive},
+			"m-2": {ID: "m-2", Name: "Bo", JoinedAt: now, Status: member.StatusBlocked},
+		}}
+		holds := &holdRepo{holds: map[string]hold.Hold{
+			"h-0": {ID: "h-0", BookID: "b-1", MemberID: "m-1", PlacedAt: now.Add(-time.Hour), Status: hold.StatusCancelled},
+		}}
+		return usecase.NewHoldService(holds, books, members, &seqIDGen{}, stubClock{now: now})
+	}
+
+	tests := []struct {
+		name   string
+		inputs []dto.PlaceHoldInput
+		want   error
+	}{
+		{name: "first hold", inputs: []dto.PlaceHoldInput{{BookID: "b-1", MemberID: "m-1"}}},
+		{name: "duplicate", inputs: []dto.PlaceHoldInput{{BookID: "b-1", MemberID: "m-1"}, {BookID: "b-1", MemberID: "m-1"}}, want: shared.ErrDuplicateHold},
+		{name: "archived title", inputs: []dto.PlaceHoldInput{{BookID: "b-2", MemberID: "m-1"}}, want: shared.ErrHoldNotAllowed},
+		{name: "blocked member", inputs: []dto.PlaceHoldInput{{BookID: "b-1", MemberID: "m-2"}}, want: shared.ErrMemberNotEligible},
+		{name: "unknown title", inputs: []dto.PlaceHoldInput{{BookID: "nope", MemberID: "m-1"}}, want: shared.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := newService()
+			var err error
+			for _, in := range tt.inputs {
+				_, err = svc.Place(context.Background(), in)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestHoldServiceQueueAndCancel(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	holds := &holdRepo{holds: map[string]hold.Hold{
+		"h-2": {ID: "h-2", BookID: "b-1", MemberID: "m-2", PlacedAt: now.Add(-time.Hour), Status: hold.StatusWaiting},
+		"h-1": {ID: "h-1", BookID: "b-1", MemberID: "m-1", PlacedAt: now.Add(-2 * time.Hour), Status: hold.StatusWaiting},
+	}}
+	svc := usecase.NewHoldService(holds, &bookRepo{}, &memberRepo{}, &seqIDGen{}, stubClock{now: now})
+
+	queue, err := svc.Queue(context.Background(), "b-1")
+	if err != nil {
+		t.Fatalf("queue: %v", err)
+	}
+	if len(queue) != 2 || queue[0].ID != "h-1" {
+		t.Fatalf("expected oldest hold first got %+v", queue)
+	}
+
+	if _, err := svc.Cancel(context.Background(), "h-1"); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := svc.Cancel(context.Background(), "h-1"); !errors.Is(err, shared.ErrHoldClosed) {
+		t.Fatalf("expected closed hold error got %v", err)
+	}
+
+	queue, _ = svc.Queue(context.Background(), "b-1")
+	if len(queue) != 1 || queue[0].ID != "h-2" {
+		t.Fatalf("expected only h-2 waiting got %+v", queue)
+	}
+}
+
+func TestMemberServicePIN(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Ann", JoinedAt: now, Status: member.StatusActive},
+	}}
+	svc := usecase.NewMemberService(repo, nil, stubIDGen{id: "ignored"}, stubClock{now: now}, member.Policy{}, nil)
+
+	if _, err := svc.Authenticate(context.Background(), "m-1", "1234"); !errors.Is(err, shared.ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials without pin got %v", err)
+	}
+	if _, err := svc.SetPIN(context.Background(), "m-1", "12a4"); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected invalid pin error got %v", err)
+	}
+
+	updated, err := svc.SetPIN(context.Background(), "m-1", "1234")
+	if err != nil {
+		t.Fatalf("set pin: %v", err)
+	}
+	if updated.PINHash == "" || updated.PINHash == "1234" {
+		t.Fatalf("expected hashed pin got %q", updated.PINHash)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		pin  string
+		want error
+	}{
+		{name: "correct", id: "m-1", pin: "1234"},
+		{name: "wrong pin", id: "m-1", pin: "4321", want: shared.ErrInvalidCredentials},
+		{name: "unknown member", id: "m-9", pin: "1234", want: shared.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		if _, err := svc.Authenticate(context.Background(), tt.id, tt.pin); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: expected %v got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+type holdRepo struct {
+	holds map[string]hold.Hold
+}
+
+func (r *holdRepo) Save(_ context.Context, h hold.Hold) error {
+	r.holds[h.ID] = h
+	return nil
+}
+
+func (r *holdRepo) GetByID(_ context.Context, id string) (hold.Hold, error) {
+	h, ok := r.holds[id]
+	if !ok {
+		return hold.Hold{}, shared.ErrNotFound
+	}
+	return h, nil
+}
+
+func (r *holdRepo) List(_ context.Context) ([]hold.Hold, error) {
+	out := make([]hold.Hold, 0, len(r.holds))
+	for _, h := range r.holds {
+		out = append(out, h)
+	}
+	return out, nil
+}
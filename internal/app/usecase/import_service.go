@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type ImportService struct {
+	books  BookService
+	copies CopyService
+}
+
+func NewImportService(books BookService, copies CopyService) ImportService {
+	return ImportService{books: books, copies: copies}
+}
+
+func (s ImportService) Preview(ctx context.Context, inputs []dto.CreateBookInput) ([]dto.ImportItem, error) {
+	seen := map[string]int{}
+	out := make([]dto.ImportItem, 0, len(inputs))
+
+	for i, in := range inputs {
+		item := dto.ImportItem{Index: i + 1, Input: in, Status: dto.ImportNew}
+
+		isbn := ""
+		if strings.TrimSpace(in.ISBN) != "" {
+			normalized, err := book.NormalizeISBN(in.ISBN)
+			if err != nil {
+				item.Status = dto.ImportInvalid
+				item.Problem = err.Error()
+				out = append(out, item)
+				continue
+			}
+			isbn = normalized
+		}
+
+		candidate := book.Book{
+			ID:      "preview",
+			Title:   in.Title,
+			Authors: in.Authors,
+			ISBN:    isbn,
+			Status:  book.StatusActive,
+		}
+		if err := candidate.Validate(); err != nil {
+			item.Status = dto.ImportInvalid
+			item.Problem = err.Error()
+			out = append(out, item)
+			continue
+		}
+
+		if isbn != "" {
+			item.Input.ISBN = isbn
+
+			if first, ok := seen[isbn]; ok {
+				item.Status = dto.ImportDuplicate
+				item.Problem = fmt.Sprintf("same isbn as record %d", first)
+				out = append(out, item)
+				continue
+			}
+			seen[isbn] = item.Index
+
+			existing, err := s.books.GetByISBN(ctx, isbn)
+			if err == nil {
+				item.Status = dto.ImportDuplicate
+				item.ExistingID = existing.ID
+				item.Problem = "isbn already in catalog"
+			} else if !errors.Is(err, shared.ErrNotFound) {
+				return nil, err
+			}
+		}
+
+		out = append(out, item)
+	}
+
+	return out, nil
+}
+
+func (s ImportService) Import(ctx context.Context, items []dto.ImportItem, opts dto.ImportOptions) (dto.ImportResult, error) {
+	var result dto.ImportResult
+
+	for _, item := range items {
+		bookID := ""
+		switch {
+		case item.Status == dto.ImportNew:
+			created, err := s.books.Create(ctx, item.Input)
+			if err != nil {
+				item.Problem = err.Error()
+				result.Failed = append(result.Failed, item)
+				continue
+			}
+			result.BooksCreated++
+			bookID = created.ID
+		case item.Status == dto.ImportDuplicate && opts.AttachDuplicates && item.ExistingID != "":
+			bookID = item.ExistingID
+		default:
+			result.Skipped++
+			continue
+		}
+
+		for n := 0; n < opts.CopiesPerRecord; n++ {
+			if _, err := s.copies.Create(ctx, dto.CreateCopyInput{BookID: bookID}); err != nil {
+				return result, fmt.Errorf("create copy for record %d: %w", item.Index, err)
+			}
+			result.CopiesCreated++
+		}
+	}
+
+	return result, nil
+}
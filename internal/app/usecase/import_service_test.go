@@ -0,0 +1,58 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+func TestImportServicePreviewAndImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	books := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Go", Authors: []string{"Alan Donovan"}, ISBN: "9780134190440", Status: book.StatusActive},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{}}
+	ids := &seqIDGen{}
+
+	importer := usecase.NewImportService(
+		usecase.NewBookService(books, ids, stubClock{}, book.Policy{UniqueISBN: true}, nil),
+		usecase.NewCopyService(copies, nil, ids, stubClock{}, copy.Policy{}, nil),
+	)
+
+	items, err := importer.Preview(ctx, []dto.CreateBookInput{
+		{Title: "Numbers", Authors: []string{"A"}, ISBN: "0-306-40615-2"},
+		{Title: "Go again", Authors: []string{"B"}, ISBN: "978-0-13-419044-0"},
+		{Title: "Numbers twice", Authors: []string{"C"}, ISBN: "9780306406157"},
+		{Title: "", Authors: []string{"D"}},
+	})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+
+	want := []dto.ImportStatus{dto.ImportNew, dto.ImportDuplicate, dto.ImportDuplicate, dto.ImportInvalid}
+	for i, it := range items {
+		if it.Status != want[i] {
+			t.Fatalf("record %d: expected %s got %s (%s)", it.Index, want[i], it.Status, it.Problem)
+		}
+	}
+	if items[1].ExistingID != "b-1" {
+		t.Fatalf("expected duplicate to reference b-1 got %q", items[1].ExistingID)
+	}
+
+	result, err := importer.Import(ctx, items, dto.ImportOptions{CopiesPerRecord: 2, AttachDuplicates: true})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.BooksCreated != 1 || result.CopiesCreated != 4 || result.Skipped != 2 || len(result.Failed) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(books.books) != 2 || len(copies.copies) != 4 {
+		t.Fatalf("unexpected repo state: %d books %d copies", len(books.books), len(copies.copies))
+	}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type LabelService struct {
+	books  BookService
+	copies CopyService
+}
+
+func NewLabelService(books BookService, copies CopyService) LabelService {
+	return LabelService{books: books, copies: copies}
+}
+
+// Labels resolves a selection into printable labels. Copies without a
+// barcode are given one first when automatic barcodes are on.
+func (s LabelService) Labels(ctx context.Context, sel dto.LabelSelection) ([]dto.Label, error) {
+	copies, err := s.selectCopies(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := map[string]string{}
+	out := make([]dto.Label, 0, len(copies))
+	for _, c := range copies {
+		if c.Barcode == "" {
+			if c, err = s.copies.AssignBarcode(ctx, c.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		title, ok := titles[c.BookID]
+		if !ok {
+			if b, err := s.books.GetByID(ctx, c.BookID); err == nil {
+				title = b.Title
+			} else if !errors.Is(err, shared.ErrNotFound) {
+				return nil, err
+			}
+			titles[c.BookID] = title
+		}
+
+		out = append(out, dto.Label{CopyID: c.ID, Barcode: c.Barcode, Title: title, CallNumber: c.CallNumber, Branch: c.Branch})
+	}
+
+	return out, nil
+}
+
+func (s LabelService) selectCopies(ctx context.Context, sel dto.LabelSelection) ([]copy.Copy, error) {
+	if len(sel.Copies) > 0 {
+		out := make([]copy.Copy, 0, len(sel.Copies))
+		for _, ref := range sel.Copies {
+			c, err := s.copies.GetByID(ctx, ref)
+			if errors.Is(err, shared.ErrNotFound) {
+				c, err = s.copies.GetByBarcode(ctx, ref)
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, c)
+		}
+		return out, nil
+	}
+
+	branch := strings.ToUpper(strings.TrimSpace(sel.Branch))
+	if sel.BookID == "" && branch == "" {
+		return nil, shared.Invalid(errors.New("select copies, a book or a branch"))
+	}
+
+	all, err := s.copies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := all[:0]
+	for _, c := range all {
+		if c.Status == copy.StatusLost || (sel.BookID != "" && c.BookID != sel.BookID) || (branch != "" && c.Branch != branch) {
+			continue
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if n := copy.CompareCallNumbers(out[i].CallNumber, out[j].CallNumber); n != 0 {
+			return n < 0
+		}
+		return out[i].Barcode < out[j].Barcode
+	})
+
+	return out, nil
+}
@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestCopyServiceAutoBarcode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := &copyRepo{copies: map[string]copy.Copy{
+		"c-0": {ID: "c-0", BookID: "b-1", Barcode: "LIB0101", Status: copy.StatusAvailable},
+	}}
+	seq := &sequenceRepo{}
+	policy := copy.Policy{AutoBarcode: true, BarcodePrefix: "LIB", BarcodeDigits: 4, BarcodeStart: 100}
+	svc := usecase.NewCopyService(repo, seq, &seqIDGen{}, stubClock{}, policy, nil)
+
+	var got []string
+	for _, in := range []dto.CreateCopyInput{{BookID: "b-1"}, {BookID: "b-1", Barcode: "HAND-1"}, {BookID: "b-1"}} {
+		c, err := svc.Create(ctx, in)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		got = append(got, c.Barcode)
+	}
+
+	// LIB0101 is already taken by hand, so the sequence skips it.
+	want := []string{"LIB0100", "HAND-1", "LIB0102"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected barcodes %v got %v", want, got)
+	}
+
+	off := usecase.NewCopyService(repo, seq, &seqIDGen{n: 10}, stubClock{}, copy.Policy{}, nil)
+	c, err := off.Create(ctx, dto.CreateCopyInput{BookID: "b-1"})
+	if err != nil || c.Barcode != "" {
+		t.Fatalf("expected no barcode with auto barcodes off, got %q %v", c.Barcode, err)
+	}
+}
+
+func TestLabelServiceLabels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	books := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Go", Status: book.StatusActive},
+		"b-2": {ID: "b-2", Title: "Rust", Status: book.StatusActive},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "BC-1", Status: copy.StatusAvailable, Branch: "MAIN", CallNumber: "QA76.9 .D3"},
+		"c-2": {ID: "c-2", BookID: "b-1", Status: copy.StatusLoaned, Branch: "MAIN", CallNumber: "QA76.73 .G63"},
+		"c-3": {ID: "c-3", BookID: "b-1", Barcode: "BC-3", Status: copy.StatusLost, Branch: "MAIN"},
+		"c-4": {ID: "c-4", BookID: "b-2", Barcode: "BC-4", Status: copy.StatusAvailable, Branch: "EAST"},
+	}}
+	policy := copy.Policy{AutoBarcode: true, BarcodePrefix: "C", BarcodeDigits: 3, BarcodeStart: 1}
+	svc := usecase.NewLabelService(
+		usecase.NewBookService(books, stubIDGen{id: "ignored"}, stubClock{}, book.Policy{}, nil),
+		usecase.NewCopyService(copies, &sequenceRepo{}, stubIDGen{id: "ignored"}, stubClock{}, policy, nil),
+	)
+
+	tests := []struct {
+		name string
+		sel  dto.LabelSelection
+		want []dto.Label
+	}{
+		{
+			name: "book in shelf order without lost copies",
+			sel:  dto.LabelSelection{BookID: "b-1"},
+			want: []dto.Label{
+				{CopyID: "c-2", Barcode: "C001", Title: "Go", CallNumber: "QA76.73 .G63", Branch: "MAIN"},
+				{CopyID: "c-1", Barcode: "BC-1", Title: "Go", CallNumber: "QA76.9 .D3", Branch: "MAIN"},
+			},
+		},
+		{
+			name: "listed copies by id or barcode",
+			sel:  dto.LabelSelection{Copies: []string{"BC-4", "c-1"}},
+			want: []dto.Label{
+				{CopyID: "c-4", Barcode: "BC-4", Title: "Rust", Branch: "EAST"},
+				{CopyID: "c-1", Barcode: "BC-1", Title: "Go", CallNumber: "QA76.9 .D3", Branch: "MAIN"},
+			},
+		},
+		{
+			name: "branch",
+			sel:  dto.LabelSelection{Branch: "east"},
+			want: []dto.Label{{CopyID: "c-4", Barcode: "BC-4", Title: "Rust", Branch: "EAST"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := svc.Labels(ctx, tt.sel)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: expected %+v got %+v", tt.name, tt.want, got)
+		}
+	}
+
+	if c, _ := copies.GetByID(ctx, "c-2"); c.Barcode != "C001" {
+		t.Fatalf("expected the generated barcode to be saved, got %q", c.Barcode)
+	}
+	if _, err := svc.Labels(ctx, dto.LabelSelection{}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected invalid input for an empty selection, got %v", err)
+	}
+}
+
+type sequenceRepo struct {
+	values map[string]int64
+}
+
+func (r *sequenceRepo) Next(_ context.Context, name string, floor int64) (int64, error) {
+	if r.values == nil {
+		r.values = map[string]int64{}
+	}
+	r.values[name] = max(r.values[name]+1, floor)
+	return r.values[name], nil
+}
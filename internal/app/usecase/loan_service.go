@@ -2,11 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
 type LoanService struct {
@@ -16,6 +22,7 @@ type LoanService struct {
 	idGen   ports.IDGenerator
 	clock   ports.Clock
 	policy  loan.Policy
+	events  ports.EventPublisher
 }
 
 func NewLoanService(
@@ -25,6 +32,7 @@ func NewLoanService(
 	idGen ports.IDGenerator,
 	clock ports.Clock,
 	policy loan.Policy,
+	events ports.EventPublisher,
 ) LoanService {
 	return LoanService{
 		loans:   loans,
@@ -33,44 +41,112 @@ func NewLoanService(
 		idGen:   idGen,
 		clock:   clock,
 		policy:  policy,
+		events:  events,
 	}
 }
 
+// Issue lends the copy to the member. A supervisor's override lets the
+// loan through when the only rules it breaks are ones that may be
+// overridden, and is recorded on the loan.
 func (s LoanService) Issue(ctx context.Context, input dto.IssueLoanInput) (loan.Loan, error) {
-	c, err := s.copies.GetByID(ctx, input.CopyID)
+	e, err := s.CheckEligibility(ctx, input.CopyID, input.MemberID)
 	if err != nil {
 		return loan.Loan{}, err
 	}
 
-	m, err := s.members.GetByID(ctx, input.MemberID)
+	c, m := e.Copy, e.Member
+	now := s.clock.Now()
+
+	var overrides []loan.Override
+	if !e.Eligible() {
+		o, err := s.overrule(e.Violations, input.Override, now)
+		if err != nil {
+			return loan.Loan{}, err
+		}
+		overrides = []loan.Override{o}
+	}
+
+	terms := loan.Terms{Period: input.Period, DueAt: input.DueAt}
+	created, err := loan.NewWithTerms(s.idGen.NewID(), input.CopyID, m.ID, now, terms, s.policy)
 	if err != nil {
 		return loan.Loan{}, err
 	}
+	created.Overrides = overrides
 
-	activeCount, err := s.loans.CountActiveByMemberID(ctx, input.MemberID)
-	if err != nil {
+	if err := s.loans.Save(ctx, created); err != nil {
 		return loan.Loan{}, err
 	}
 
-	if err := loan.CanIssue(c, m, activeCount, s.policy); err != nil {
+	from := c.Status
+	c.Status = copy.StatusLoaned
+	c.UpdatedAt = created.IssuedAt
+	if err := s.copies.Save(ctx, c); err != nil {
 		return loan.Loan{}, err
 	}
 
-	created, err := loan.New(s.idGen.NewID(), input.CopyID, input.MemberID, s.clock.Now(), s.policy)
+	events := []event.Event{event.LoanIssued{Loan: created, At: created.IssuedAt}}
+	for _, o := range overrides {
+		events = append(events, event.LoanOverridden{LoanID: created.ID, MemberID: m.ID, Override: o, At: now})
+	}
+	events = append(events, event.CopyStatusChanged{CopyID: c.ID, BookID: c.BookID, From: from, To: c.Status, At: created.IssuedAt})
+	return created, publish(ctx, s.events, events...)
+}
+
+// overrule applies the override asked for in input to the violations.
+// Without one the first violation is the error.
+func (s LoanService) overrule(violations []loan.Violation, input dto.LoanOverride, now time.Time) (loan.Override, error) {
+	if input == (dto.LoanOverride{}) {
+		return loan.Override{}, violations[0].Err
+	}
+	return loan.Overrule(violations, loan.Override{Reason: input.Reason, By: input.By, At: now}, s.policy)
+}
+
+// DueAt previews when a loan issued now on terms t would fall due.
+func (s LoanService) DueAt(t loan.Terms) (time.Time, error) {
+	return s.policy.DueAt(s.clock.Now(), t)
+}
+
+// Periods lists the named loan periods the desk may pick.
+func (s LoanService) Periods() []loan.Period {
+	return s.policy.Periods
+}
+
+// MayOverride reports whether user is a supervisor who may override loan
+// rules.
+func (s LoanService) MayOverride(user string) bool {
+	return s.policy.MayOverride(user)
+}
+
+// CheckEligibility runs every issue rule for the copy and member without
+// issuing anything, so staff can see all the reasons a loan would be
+// refused. The member may be given by ID or card number. There is no
+// balance rule, as fines and fees are not tracked.
+func (s LoanService) CheckEligibility(ctx context.Context, copyID, memberID string) (dto.Eligibility, error) {
+	c, err := s.copies.GetByID(ctx, copyID)
 	if err != nil {
-		return loan.Loan{}, err
+		return dto.Eligibility{}, err
 	}
 
-	if err := s.loans.Save(ctx, created); err != nil {
-		return loan.Loan{}, err
+	// The desk may scan the library card instead of typing the member ID.
+	m, err := s.members.GetByID(ctx, memberID)
+	if errors.Is(err, shared.ErrNotFound) {
+		m, err = s.members.GetByCardNumber(ctx, memberID)
+	}
+	if err != nil {
+		return dto.Eligibility{}, err
 	}
 
-	c.Status = copy.StatusLoaned
-	if err := s.copies.Save(ctx, c); err != nil {
-		return loan.Loan{}, err
+	active, err := s.loans.CountActiveByMemberID(ctx, m.ID)
+	if err != nil {
+		return dto.Eligibility{}, err
+	}
+
+	violations, err := loan.Violations(c, m, active, s.clock.Now(), s.policy)
+	if err != nil {
+		return dto.Eligibility{}, err
 	}
 
-	return created, nil
+	return dto.Eligibility{Copy: c, Member: m, ActiveLoans: active, MaxLoans: s.policy.MaxLoansPerMember, Violations: violations}, nil
 }
 
 func (s LoanService) Renew(ctx context.Context, input dto.RenewLoanInput) (loan.Loan, error) {
@@ -79,7 +155,13 @@ func (s LoanService) Renew(ctx context.Context, input dto.RenewLoanInput) (loan.
 		return loan.Loan{}, err
 	}
 
-	renewed, err := loan.Renew(current, s.clock.Now(), s.policy)
+	now := s.clock.Now()
+	var renewed loan.Loan
+	if input.Override == (dto.LoanOverride{}) {
+		renewed, err = loan.Renew(current, now, s.policy)
+	} else {
+		renewed, err = loan.RenewOverride(current, loan.Override{Reason: input.Override.Reason, By: input.Override.By, At: now}, now, s.policy)
+	}
 	if err != nil {
 		return loan.Loan{}, err
 	}
@@ -88,11 +170,25 @@ func (s LoanService) Renew(ctx context.Context, input dto.RenewLoanInput) (loan.
 		return loan.Loan{}, err
 	}
 
-	return renewed, nil
+	events := []event.Event{event.LoanRenewed{Loan: renewed, At: now}}
+	if len(renewed.Overrides) > len(current.Overrides) {
+		o := renewed.Overrides[len(renewed.Overrides)-1]
+		events = append(events, event.LoanOverridden{LoanID: renewed.ID, MemberID: renewed.MemberID, Override: o, At: now})
+	}
+	return renewed, publish(ctx, s.events, events...)
 }
 
 func (s LoanService) Return(ctx context.Context, input dto.ReturnLoanInput) (loan.Loan, error) {
-	current, err := s.loans.GetByID(ctx, input.LoanID)
+	return s.checkIn(ctx, input.LoanID, func(c copy.Copy, _ time.Time) copy.Copy {
+		c.Status = copy.StatusAvailable
+		return c
+	})
+}
+
+// checkIn returns a loan and lets place decide where its copy goes, so the
+// copy is saved once and its status change is published once.
+func (s LoanService) checkIn(ctx context.Context, loanID string, place func(c copy.Copy, at time.Time) copy.Copy) (loan.Loan, error) {
+	current, err := s.loans.GetByID(ctx, loanID)
 	if err != nil {
 		return loan.Loan{}, err
 	}
@@ -111,18 +207,97 @@ func (s LoanService) Return(ctx context.Context, input dto.ReturnLoanInput) (loa
 		return loan.Loan{}, err
 	}
 
-	c.Status = copy.StatusAvailable
+	at := *returned.ReturnedAt
+	from := c.Status
+	c = place(c, at)
+	c.UpdatedAt = at
 	if err := s.copies.Save(ctx, c); err != nil {
 		return loan.Loan{}, err
 	}
 
-	return returned, nil
+	err = publish(ctx, s.events,
+		event.LoanReturned{Loan: returned, At: at},
+		event.CopyStatusChanged{CopyID: c.ID, BookID: c.BookID, From: from, To: c.Status, At: at},
+	)
+	return returned, err
+}
+
+func (s LoanService) CanRenew(l loan.Loan) error {
+	_, err := loan.Renew(l, s.clock.Now(), s.policy)
+	return err
+}
+
+func (s LoanService) GetByID(ctx context.Context, id string) (loan.Loan, error) {
+	return s.loans.GetByID(ctx, id)
 }
 
 func (s LoanService) List(ctx context.Context) ([]loan.Loan, error) {
 	return s.loans.List(ctx)
 }
 
+// OverdueByMember groups overdue loans by borrower. Groups with the oldest
+// due date come first. A non-empty branch keeps only loans of copies that
+// branch owns.
+func (s LoanService) OverdueByMember(ctx context.Context, branch string) ([]dto.OverdueGroup, error) {
+	overdue, err := s.ListOverdue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := s.copies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	barcodes := make(map[string]string, len(copies))
+	homes := make(map[string]string, len(copies))
+	for _, c := range copies {
+		barcodes[c.ID] = c.Barcode
+		homes[c.ID] = c.Branch
+	}
+
+	now := s.clock.Now()
+	groups := map[string]*dto.OverdueGroup{}
+	for _, l := range overdue {
+		if branch != "" && homes[l.CopyID] != branch {
+			continue
+		}
+		g, ok := groups[l.MemberID]
+		if !ok {
+			m, err := s.members.GetByID(ctx, l.MemberID)
+			if errors.Is(err, shared.ErrNotFound) {
+				m = member.Member{ID: l.MemberID}
+			} else if err != nil {
+				return nil, err
+			}
+			g = &dto.OverdueGroup{Member: m}
+			groups[l.MemberID] = g
+		}
+
+		days := l.DaysOverdue(now)
+		g.Items = append(g.Items, dto.OverdueItem{Loan: l, Barcode: barcodes[l.CopyID], DaysOverdue: days})
+		g.Aging.Add(loan.AgingFor(days))
+		if g.OldestDue.IsZero() || l.DueAt.Before(g.OldestDue) {
+			g.OldestDue = l.DueAt
+		}
+	}
+
+	out := make([]dto.OverdueGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Slice(g.Items, func(i, j int) bool {
+			return g.Items[i].Loan.DueAt.Before(g.Items[j].Loan.DueAt)
+		})
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].OldestDue.Equal(out[j].OldestDue) {
+			return out[i].OldestDue.Before(out[j].OldestDue)
+		}
+		return out[i].Member.ID < out[j].Member.ID
+	})
+
+	return out, nil
+}
+
 func (s LoanService) ListOverdue(ctx context.Context) ([]loan.Loan, error) {
 	all, err := s.loans.List(ctx)
 	if err != nil {
@@ -40,6 +40,7 @@ func BenchmarkLoanServiceListOverdue10k(b *testing.B) {
 		stubIDGen{id: "l-1"},
 		stubClock{now: now},
 		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		nil,
 	)
 
 	b.ResetTimer()
@@ -3,6 +3,8 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
@@ -35,6 +38,7 @@ func TestLoanServiceIssue(t *testing.T) {
 		stubIDGen{id: "l-1"},
 		stubClock{now: now},
 		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		nil,
 	)
 
 	issued, err := svc.Issue(context.Background(), dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"})
@@ -75,6 +79,7 @@ func TestLoanServiceIssueFailsWhenBlocked(t *testing.T) {
 		stubIDGen{id: "l-1"},
 		stubClock{now: now},
 		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		nil,
 	)
 
 	_, err := svc.Issue(context.Background(), dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"})
@@ -83,6 +88,189 @@ func TestLoanServiceIssueFailsWhenBlocked(t *testing.T) {
 	}
 }
 
+func TestLoanServiceIssueWithOverride(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	loanRepo := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", CopyID: "c-2", MemberID: "m-1", Status: loan.StatusActive},
+	}}
+	events := &recordingPublisher{}
+	svc := usecase.NewLoanService(
+		loanRepo,
+		&copyRepo{copies: map[string]copy.Copy{
+			"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable},
+		}},
+		&memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Name: "Joe", JoinedAt: now, Status: member.StatusActive},
+		}},
+		stubIDGen{id: "l-2"},
+		stubClock{now: now},
+		loan.Policy{LoanDays: 14, MaxLoansPerMember: 1, MaxRenewals: 1, Supervisors: []string{"sam"}},
+		events,
+	)
+
+	ctx := context.Background()
+	if _, err := svc.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"}); !errors.Is(err, shared.ErrLoanLimitReached) {
+		t.Fatalf("expected %v without an override got %v", shared.ErrLoanLimitReached, err)
+	}
+	if _, err := svc.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1", Override: dto.LoanOverride{By: "amy", Reason: "thesis"}}); !errors.Is(err, shared.ErrOverrideNotAllowed) {
+		t.Fatalf("expected %v for a non-supervisor got %v", shared.ErrOverrideNotAllowed, err)
+	}
+
+	issued, err := svc.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1", Override: dto.LoanOverride{By: "sam", Reason: "thesis"}})
+	if err != nil {
+		t.Fatalf("issue with override: %v", err)
+	}
+	want := loan.Override{Rules: []loan.Rule{loan.RuleLoanLimit}, Reason: "thesis", By: "sam", At: now}
+	if len(issued.Overrides) != 1 || !reflect.DeepEqual(issued.Overrides[0], want) {
+		t.Fatalf("expected override %+v got %+v", want, issued.Overrides)
+	}
+	if got := events.types(); !reflect.DeepEqual(got, []event.Type{event.TypeLoanIssued, event.TypeLoanOverridden, event.TypeCopyStatusChanged}) {
+		t.Fatalf("unexpected events %v", got)
+	}
+}
+
+func TestLoanServiceIssueForPeriodOrDueDate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	newService := func() usecase.LoanService {
+		return usecase.NewLoanService(
+			&loanRepo{loans: map[string]loan.Loan{}},
+			&copyRepo{copies: map[string]copy.Copy{
+				"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable},
+			}},
+			&memberRepo{members: map[string]member.Member{
+				"m-1": {ID: "m-1", Name: "Joe", JoinedAt: now, Status: member.StatusActive},
+			}},
+			stubIDGen{id: "l-1"},
+			stubClock{now: now},
+			loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1, Periods: []loan.Period{{Name: "reserve", Length: 2 * time.Hour}}, MinLength: time.Hour},
+			nil,
+		)
+	}
+
+	ctx := context.Background()
+	reserve, err := newService().Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1", Period: "reserve"})
+	if err != nil || reserve.Period != "reserve" || !reserve.DueAt.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("expected a reserve loan got %+v, %v", reserve, err)
+	}
+
+	due := time.Date(2026, 2, 20, 17, 0, 0, 0, time.UTC)
+	custom, err := newService().Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1", DueAt: due})
+	if err != nil || custom.Period != "" || !custom.DueAt.Equal(due) {
+		t.Fatalf("expected a loan due %v got %+v, %v", due, custom, err)
+	}
+
+	svc := newService()
+	if _, err := svc.Issue(ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1", DueAt: now.Add(10 * time.Minute)}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a loan under the minimum to be invalid got %v", err)
+	}
+	if c, _ := svc.CheckEligibility(ctx, "c-1", "m-1"); !c.Eligible() {
+		t.Fatalf("a refused loan must leave the copy available")
+	}
+}
+
+func TestLoanServiceCheckEligibility(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	loanRepo := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", CopyID: "c-2", MemberID: "m-1", Status: loan.StatusActive},
+	}}
+	svc := usecase.NewLoanService(
+		loanRepo,
+		&copyRepo{copies: map[string]copy.Copy{
+			"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusLoaned},
+		}},
+		&memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Name: "Joe", CardNumber: "P00000001", JoinedAt: now, ExpiresAt: now.AddDate(0, 0, -1), Status: member.StatusActive},
+		}},
+		stubIDGen{id: "l-2"},
+		stubClock{now: now},
+		loan.Policy{LoanDays: 14, MaxLoansPerMember: 1, MaxRenewals: 1},
+		nil,
+	)
+
+	e, err := svc.CheckEligibility(context.Background(), "c-1", "P00000001")
+	if err != nil {
+		t.Fatalf("check eligibility: %v", err)
+	}
+	if e.Eligible() || e.Member.ID != "m-1" || e.ActiveLoans != 1 || e.MaxLoans != 1 {
+		t.Fatalf("unexpected eligibility %+v", e)
+	}
+
+	var rules []loan.Rule
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	want := []loan.Rule{loan.RuleCopyAvailable, loan.RuleMembership, loan.RuleLoanLimit}
+	if len(rules) != len(want) || rules[0] != want[0] || rules[1] != want[1] || rules[2] != want[2] {
+		t.Fatalf("expected rules %v got %v", want, rules)
+	}
+	if len(loanRepo.loans) != 1 {
+		t.Fatalf("checking eligibility must not issue a loan")
+	}
+
+	if _, err := svc.CheckEligibility(context.Background(), "c-1", "nobody"); !errors.Is(err, shared.ErrNotFound) {
+		t.Fatalf("expected %v for an unknown member got %v", shared.ErrNotFound, err)
+	}
+}
+
+func TestLoanServiceOverdueByMember(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	overdue := func(id, copyID, memberID string, days int) loan.Loan {
+		due := now.AddDate(0, 0, -days)
+		return loan.Loan{ID: id, CopyID: copyID, MemberID: memberID, IssuedAt: due.AddDate(0, 0, -14), DueAt: due, Status: loan.StatusActive}
+	}
+
+	svc := usecase.NewLoanService(
+		&loanRepo{loans: map[string]loan.Loan{
+			"l-1": overdue("l-1", "c-1", "m-1", 3),
+			"l-2": overdue("l-2", "c-2", "m-1", 45),
+			"l-3": overdue("l-3", "c-3", "m-1", 10),
+			"l-4": overdue("l-4", "c-4", "m-2", 5),
+			"l-5": overdue("l-5", "c-5", "m-2", -2),
+		}},
+		&copyRepo{copies: map[string]copy.Copy{
+			"c-2": {ID: "c-2", Barcode: "BC-2"},
+		}},
+		&memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Name: "Ada"},
+			"m-2": {ID: "m-2", Name: "Bo"},
+		}},
+		stubIDGen{},
+		stubClock{now: now},
+		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		nil,
+	)
+
+	groups, err := svc.OverdueByMember(context.Background(), "")
+	if err != nil {
+		t.Fatalf("overdue by member: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Member.Name != "Ada" || groups[1].Member.Name != "Bo" {
+		t.Fatalf("expected Ada then Bo, got %+v", groups)
+	}
+
+	ada := groups[0]
+	if !ada.OldestDue.Equal(now.AddDate(0, 0, -45)) {
+		t.Fatalf("unexpected oldest due %v", ada.OldestDue)
+	}
+	if ada.Aging != (dto.AgingCounts{Days1To7: 1, Days8To30: 1, Days31AndOver: 1}) {
+		t.Fatalf("unexpected aging %+v", ada.Aging)
+	}
+	if ada.Items[0].Loan.ID != "l-2" || ada.Items[0].Barcode != "BC-2" || ada.Items[0].DaysOverdue != 45 {
+		t.Fatalf("expected oldest loan first, got %+v", ada.Items[0])
+	}
+	if len(groups[1].Items) != 1 || groups[1].Aging.Days1To7 != 1 {
+		t.Fatalf("loans not yet due must be left out, got %+v", groups[1])
+	}
+}
+
 type stubClock struct {
 	now time.Time
 }
@@ -151,6 +339,15 @@ func (r *memberRepo) GetByID(_ context.Context, id string) (member.Member, error
 	return m, nil
 }
 
+func (r *memberRepo) GetByCardNumber(_ context.Context, card string) (member.Member, error) {
+	for _, m := range r.members {
+		if card != "" && m.CardNumber == card {
+			return m, nil
+		}
+	}
+	return member.Member{}, shared.ErrNotFound
+}
+
 func (r *memberRepo) List(_ context.Context) ([]member.Member, error) {
 	out := make([]member.Member, 0, len(r.members))
 	for _, m := range r.members {
@@ -219,3 +416,21 @@ func (r *bookRepo) List(_ context.Context) ([]book.Book, error) {
 	}
 	return out, nil
 }
+
+func (r *bookRepo) GetByISBN(_ context.Context, isbn string) (book.Book, error) {
+	for _, b := range r.books {
+		if b.ISBN != "" && b.ISBN == isbn {
+			return b, nil
+		}
+	}
+	return book.Book{}, shared.ErrNotFound
+}
+
+type seqIDGen struct {
+	n int
+}
+
+func (g *seqIDGen) NewID() string {
+	g.n++
+	return "id-" + strconv.Itoa(g.n)
+}
@@ -3,6 +3,7 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,10 +19,10 @@ func TestBookServiceUpdate(t *testing.T) {
 	t.Parallel()
 
 	repo := &bookRepo{books: map[string]book.Book{
-		"b-1": {ID: "b-1", Title: "Old", Authors: []string{"Author"}, Status: book.StatusActive},
+		"b-1": {ID: "b-1", Title: "Old", Authors: []string{"Author"}, ISBN: "1234567890", Status: book.StatusActive},
 	}}
 
-	svc := usecase.NewBookService(repo, stubIDGen{id: "ignored"})
+	svc := usecase.NewBookService(repo, stubIDGen{id: "ignored"}, stubClock{}, book.Policy{UniqueISBN: true}, nil)
 
 	updated, err := svc.Update(context.Background(), dto.UpdateBookInput{
 		ID:        "b-1",
@@ -31,14 +32,20 @@ func TestBookServiceUpdate(t *testing.T) {
 		Category:  "Tech",
 		Publisher: "Pub",
 		Year:      2026,
+		Status:    "Archived",
 	})
 	if err != nil {
 		t.Fatalf("update book: %v", err)
 	}
 
-	if updated.Title != "New Title" || updated.Category != "Tech" {
+	if updated.Title != "New Title" || updated.Category != "Tech" || updated.ISBN != "1234567890" || updated.Status != book.StatusArchived {
 		t.Fatalf("unexpected updated book: %+v", updated)
 	}
+
+	_, err = svc.Update(context.Background(), dto.UpdateBookInput{ID: "b-1", Title: "New Title", Authors: []string{"New Author"}, ISBN: "1234567891"})
+	if !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a changed isbn to be checked got %v", err)
+	}
 }
 
 func TestBookServiceSetStatus(t *testing.T) {
@@ -48,7 +55,7 @@ func TestBookServiceSetStatus(t *testing.T) {
 		"b-1": {ID: "b-1", Title: "Old", Authors: []string{"Author"}, Status: book.StatusActive},
 	}}
 
-	svc := usecase.NewBookService(repo, stubIDGen{id: "ignored"})
+	svc := usecase.NewBookService(repo, stubIDGen{id: "ignored"}, stubClock{}, book.Policy{UniqueISBN: true}, nil)
 
 	archived, err := svc.SetStatus(context.Background(), "b-1", book.StatusArchived)
 	if err != nil {
@@ -75,7 +82,7 @@ func TestMemberServiceRegisterDuplicateID(t *testing.T) {
 		"m-1": {ID: "m-1", Name: "Existing", JoinedAt: now, Status: member.StatusActive},
 	}}
 
-	svc := usecase.NewMemberService(repo, stubIDGen{id: "m-1"}, stubClock{now: now})
+	svc := usecase.NewMemberService(repo, nil, stubIDGen{id: "m-1"}, stubClock{now: now}, member.Policy{}, nil)
 
 	_, err := svc.Register(context.Background(), dto.RegisterMemberInput{Name: "Joe"})
 	if !errors.Is(err, shared.ErrDuplicateID) {
@@ -91,18 +98,147 @@ func TestMemberServiceUpdate(t *testing.T) {
 		"m-1": {ID: "m-1", Name: "Old", JoinedAt: now, Status: member.StatusActive},
 	}}
 
-	svc := usecase.NewMemberService(repo, stubIDGen{id: "ignored"}, stubClock{now: now})
+	svc := usecase.NewMemberService(repo, nil, stubIDGen{id: "ignored"}, stubClock{now: now}, member.Policy{}, nil)
 
-	updated, err := svc.Update(context.Background(), dto.UpdateMemberInput{ID: "m-1", Name: "New", Email: "new@example.com", Phone: "123"})
+	updated, err := svc.Update(context.Background(), dto.UpdateMemberInput{ID: "m-1", Name: "New", Email: "new@example.com", Phone: "+33 6 12 34 56 78"})
 	if err != nil {
 		t.Fatalf("update member: %v", err)
 	}
 
-	if updated.Name != "New" || updated.Email != "new@example.com" || updated.Phone != "123" {
+	if updated.Name != "New" || updated.Email != "new@example.com" || updated.Phone != "+33612345678" {
 		t.Fatalf("unexpected updated member: %+v", updated)
 	}
 }
 
+func TestMemberServiceMembership(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memberRepo{members: map[string]member.Member{
+		"m-0": {ID: "m-0", CardNumber: "P0002", Name: "Hand", JoinedAt: now, Status: member.StatusActive},
+	}}
+	seq := &sequenceRepo{}
+	policy := member.Policy{TermDays: 365, CardPrefix: "P", CardDigits: 4, CardStart: 1, UniqueCard: true}
+	svc := usecase.NewMemberService(repo, seq, &seqIDGen{}, stubClock{now: now}, policy, nil)
+
+	var cards []string
+	for _, in := range []dto.RegisterMemberInput{{Name: "Joe"}, {Name: "Ann"}, {Name: "Bo", CardNumber: "LIB-9"}} {
+		m, err := svc.Register(ctx, in)
+		if err != nil {
+			t.Fatalf("register %s: %v", in.Name, err)
+		}
+		if !m.ExpiresAt.Equal(now.AddDate(1, 0, 0)) {
+			t.Fatalf("expected a one-year membership, got %v", m.ExpiresAt)
+		}
+		cards = append(cards, m.CardNumber)
+	}
+	// P0002 was handed out by hand, so the sequence skips it.
+	if want := []string{"P0001", "P0003", "LIB-9"}; !reflect.DeepEqual(cards, want) {
+		t.Fatalf("expected cards %v got %v", want, cards)
+	}
+	if _, err := svc.Register(ctx, dto.RegisterMemberInput{Name: "Dup", CardNumber: "P0001"}); !errors.Is(err, shared.ErrDuplicateCard) {
+		t.Fatalf("expected duplicate card error got %v", err)
+	}
+
+	joe, err := svc.GetByCardNumber(ctx, "P0001")
+	if err != nil {
+		t.Fatalf("get by card: %v", err)
+	}
+
+	// A year and a day later Joe's membership has lapsed.
+	lapsed := now.AddDate(1, 0, 1)
+	later := usecase.NewMemberService(repo, seq, &seqIDGen{}, stubClock{now: lapsed}, policy, nil)
+	if err := joe.CanBorrow(lapsed); !errors.Is(err, shared.ErrMembershipExpired) {
+		t.Fatalf("expected an expired membership, got %v", err)
+	}
+
+	n, err := later.ExpireMemberships(ctx)
+	if err != nil || n != 3 {
+		t.Fatalf("expected three registered members to expire, got %d %v", n, err)
+	}
+	if m, _ := later.GetByID(ctx, "m-0"); m.Status != member.StatusActive {
+		t.Fatalf("expected a member without an expiry date to stay active, got %s", m.Status)
+	}
+
+	renewed, err := later.RenewMembership(ctx, joe.ID)
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if renewed.Status != member.StatusActive || !renewed.ExpiresAt.Equal(lapsed.AddDate(0, 0, 365)) {
+		t.Fatalf("expected renewal from today and reactivation, got %+v", renewed)
+	}
+}
+
+func TestMemberServiceContactDetails(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	newRepo := func() *memberRepo {
+		return &memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Name: "Ann", Email: "ann@example.com", Phone: "555 0101", JoinedAt: now, Status: member.StatusActive},
+			"m-2": {ID: "m-2", Name: "Old Ann", Email: "ann2@example.com", JoinedAt: now, Status: member.StatusInactive, MergedInto: "m-1"},
+			"m-3": {ID: "m-3", Name: "Bo", Email: "bo@", JoinedAt: now, Status: member.StatusActive},
+			"m-4": {ID: "m-4", Name: "Ann's twin", Email: "ann@example.com", JoinedAt: now, Status: member.StatusActive},
+		}}
+	}
+	unique := member.Policy{UniqueEmail: true, PhoneCountryCode: "33"}
+
+	tests := []struct {
+		name   string
+		policy member.Policy
+		input  dto.RegisterMemberInput
+		err    error
+		email  string
+		phone  string
+	}{
+		{name: "normalized", policy: unique, input: dto.RegisterMemberInput{Name: "Joe", Email: " joe@Example.COM ", Phone: "06 12 34 56 78"}, email: "joe@example.com", phone: "+33612345678"},
+		{name: "bad email", policy: unique, input: dto.RegisterMemberInput{Name: "Joe", Email: "joe@"}, err: shared.ErrInvalidInput},
+		{name: "bad phone", policy: unique, input: dto.RegisterMemberInput{Name: "Joe", Phone: "call me"}, err: shared.ErrInvalidInput},
+		{name: "national number without region", policy: member.Policy{}, input: dto.RegisterMemberInput{Name: "Joe", Phone: "06 12 34 56 78"}, phone: "0612345678"},
+		{name: "duplicate email", policy: unique, input: dto.RegisterMemberInput{Name: "Joe", Email: "ANN@example.com"}, err: shared.ErrDuplicateEmail},
+		{name: "email of a merged record", policy: unique, input: dto.RegisterMemberInput{Name: "Joe", Email: "ann2@example.com"}, email: "ann2@example.com"},
+		{name: "shared email allowed", policy: member.Policy{}, input: dto.RegisterMemberInput{Name: "Joe", Email: "ann@example.com"}, email: "ann@example.com"},
+	}
+
+	for _, tt := range tests {
+		svc := usecase.NewMemberService(newRepo(), nil, stubIDGen{id: "m-9"}, stubClock{now: now}, tt.policy, nil)
+		m, err := svc.Register(context.Background(), tt.input)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%s: expected %v got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || m.Email != tt.email || m.Phone != tt.phone {
+			t.Fatalf("%s: expected %q %q got %+v %v", tt.name, tt.email, tt.phone, m, err)
+		}
+	}
+
+	// Editing a member keeps a phone number stored before normalization as
+	// long as it is left alone.
+	svc := usecase.NewMemberService(newRepo(), nil, stubIDGen{}, stubClock{now: now}, unique, nil)
+	updated, err := svc.Update(context.Background(), dto.UpdateMemberInput{ID: "m-1", Name: "Ann Lee", Email: "ann@example.com", Phone: "555 0101"})
+	if err != nil || updated.Phone != "555 0101" {
+		t.Fatalf("expected the stored phone to be kept, got %+v %v", updated, err)
+	}
+
+	// The same goes for a malformed or duplicated email stored before
+	// these checks existed.
+	for _, m := range []dto.UpdateMemberInput{
+		{ID: "m-3", Name: "Bo Lee", Email: "bo@", Status: "inactive"},
+		{ID: "m-4", Name: "Ann Twin", Email: "ann@example.com", Status: "inactive"},
+	} {
+		updated, err := svc.Update(context.Background(), m)
+		if err != nil || updated.Name != m.Name || updated.Email != m.Email || updated.Status != member.StatusInactive {
+			t.Fatalf("expected %s to be edited with its stored email, got %+v %v", m.ID, updated, err)
+		}
+	}
+	if _, err := svc.Update(context.Background(), dto.UpdateMemberInput{ID: "m-3", Name: "Bo", Email: "bo at example.com"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected a changed email to be checked, got %v", err)
+	}
+}
+
 func TestCopyServiceRejectsDuplicateBarcode(t *testing.T) {
 	t.Parallel()
 
@@ -110,7 +246,7 @@ func TestCopyServiceRejectsDuplicateBarcode(t *testing.T) {
 		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "BC-1", Status: copy.StatusAvailable},
 	}}
 
-	svc := usecase.NewCopyService(repo, stubIDGen{id: "c-2"})
+	svc := usecase.NewCopyService(repo, nil, stubIDGen{id: "c-2"}, stubClock{}, copy.Policy{}, nil)
 
 	_, err := svc.Create(context.Background(), dto.CreateCopyInput{BookID: "b-1", Barcode: "BC-1"})
 	if !errors.Is(err, shared.ErrDuplicateBarcode) {
@@ -125,14 +261,80 @@ func TestCopyServiceUpdate(t *testing.T) {
 		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "BC-1", Status: copy.StatusAvailable},
 	}}
 
-	svc := usecase.NewCopyService(repo, stubIDGen{id: "ignored"})
+	svc := usecase.NewCopyService(repo, nil, stubIDGen{id: "ignored"}, stubClock{}, copy.Policy{}, nil)
 
-	updated, err := svc.Update(context.Background(), dto.UpdateCopyInput{ID: "c-1", Barcode: "BC-2", Status: "damaged", ConditionNote: "torn pages"})
+	updated, err := svc.Update(context.Background(), dto.UpdateCopyInput{
+		ID:            "c-1",
+		Barcode:       "BC-2",
+		Status:        "damaged",
+		ConditionNote: "torn pages",
+		Branch:        " East ",
+		Location:      "Stacks 2",
+		CallNumber:    "QA76.73 .G63",
+	})
 	if err != nil {
 		t.Fatalf("update copy: %v", err)
 	}
 
-	if updated.Status != copy.StatusDamaged || updated.Barcode != "BC-2" {
+	if updated.Status != copy.StatusDamaged || updated.Barcode != "BC-2" || updated.Branch != "EAST" || updated.CallNumber != "QA76.73 .G63" {
 		t.Fatalf("unexpected updated copy: %+v", updated)
 	}
 }
+
+func TestBookServiceSearchTracksUpdates(t *testing.T) {
+	t.Parallel()
+
+	repo := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Refactoring", Authors: []string{"Martin Fowler"}, Status: book.StatusActive},
+	}}
+
+	svc := usecase.NewBookService(repo, stubIDGen{id: "b-2"}, stubClock{}, book.Policy{UniqueISBN: true}, nil)
+
+	found, err := svc.Search(context.Background(), "fowler", 10)
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected existing book to be found: %v %+v", err, found)
+	}
+
+	if _, err := svc.Create(context.Background(), dto.CreateBookInput{Title: "Patterns of Enterprise Application Architecture", Authors: []string{"Martin Fowler"}}); err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if _, err := svc.Update(context.Background(), dto.UpdateBookInput{ID: "b-1", Title: "Refactoring", Authors: []string{"Kent Beck"}}); err != nil {
+		t.Fatalf("update book: %v", err)
+	}
+
+	found, err = svc.Search(context.Background(), "fowler", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != "b-2" {
+		t.Fatalf("expected only the new book to match got %+v", found)
+	}
+}
+
+func TestBookServiceNormalizesAndEnforcesUniqueISBN(t *testing.T) {
+	t.Parallel()
+
+	repo := &bookRepo{books: map[string]book.Book{
+		"b-1": {ID: "b-1", Title: "Go", Authors: []string{"Alan Donovan"}, ISBN: "9780134190440", Status: book.StatusActive},
+	}}
+
+	svc := usecase.NewBookService(repo, stubIDGen{id: "b-2"}, stubClock{}, book.Policy{UniqueISBN: true}, nil)
+
+	created, err := svc.Create(context.Background(), dto.CreateBookInput{Title: "Numbers", Authors: []string{"A"}, ISBN: "0-306-40615-2"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if created.ISBN != "9780306406157" {
+		t.Fatalf("expected canonical isbn-13 got %s", created.ISBN)
+	}
+
+	_, err = svc.Update(context.Background(), dto.UpdateBookInput{ID: "b-2", Title: "Numbers", Authors: []string{"A"}, ISBN: "978-0-13-419044-0"})
+	if !errors.Is(err, shared.ErrDuplicateISBN) {
+		t.Fatalf("expected duplicate isbn error got %v", err)
+	}
+
+	relaxed := usecase.NewBookService(repo, stubIDGen{id: "b-3"}, stubClock{}, book.Policy{UniqueISBN: false}, nil)
+	if _, err := relaxed.Create(context.Background(), dto.CreateBookInput{Title: "Go again", Authors: []string{"A"}, ISBN: "9780134190440"}); err != nil {
+		t.Fatalf("expected duplicate isbn to be allowed got %v", err)
+	}
+}
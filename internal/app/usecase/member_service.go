@@ -2,22 +2,33 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
+// cardAttempts bounds the search for an unused card number when numbers in
+// the sequence were already handed out by hand.
+const cardAttempts = 1000
+
 type MemberService struct {
-	members ports.MemberRepository
-	idGen   ports.IDGenerator
-	clock   ports.Clock
+	members   ports.MemberRepository
+	sequences ports.SequenceRepository
+	idGen     ports.IDGenerator
+	clock     ports.Clock
+	policy    member.Policy
+	events    ports.EventPublisher
 }
 
-func NewMemberService(members ports.MemberRepository, idGen ports.IDGenerator, clock ports.Clock) MemberService {
-	return MemberService{members: members, idGen: idGen, clock: clock}
+func NewMemberService(members ports.MemberRepository, sequences ports.SequenceRepository, idGen ports.IDGenerator, clock ports.Clock, policy member.Policy, events ports.EventPublisher) MemberService {
+	return MemberService{members: members, sequences: sequences, idGen: idGen, clock: clock, policy: policy, events: events}
 }
 
 func (s MemberService) Register(ctx context.Context, input dto.RegisterMemberInput) (member.Member, error) {
@@ -32,17 +43,43 @@ func (s MemberService) Register(ctx context.Context, input dto.RegisterMemberInp
 		return member.Member{}, err
 	}
 
+	email, err := s.checkEmail(ctx, id, input.Email)
+	if err != nil {
+		return member.Member{}, err
+	}
+	phone, err := s.normalizePhone(input.Phone)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	card := strings.TrimSpace(input.CardNumber)
+	if card != "" {
+		if err := s.checkCard(ctx, id, card); err != nil {
+			return member.Member{}, err
+		}
+	} else if s.sequences != nil {
+		next, err := s.nextCard(ctx)
+		if err != nil {
+			return member.Member{}, err
+		}
+		card = next
+	}
+
+	now := s.clock.Now()
 	m := member.Member{
-		ID:       id,
-		Name:     input.Name,
-		Email:    input.Email,
-		Phone:    input.Phone,
-		JoinedAt: s.clock.Now(),
-		Status:   member.StatusActive,
+		ID:         id,
+		CardNumber: card,
+		Name:       input.Name,
+		Email:      email,
+		Phone:      phone,
+		Type:       strings.TrimSpace(input.Type),
+		JoinedAt:   now,
+		ExpiresAt:  s.policy.ExpiryFrom(now),
+		Status:     member.StatusActive,
 	}
 
 	if err := m.Validate(); err != nil {
-		return member.Member{}, err
+		return member.Member{}, shared.Invalid(err)
 	}
 
 	if err := s.members.Save(ctx, m); err != nil {
@@ -58,43 +95,282 @@ func (s MemberService) Update(ctx context.Context, input dto.UpdateMemberInput)
 		return member.Member{}, err
 	}
 
+	if card := strings.TrimSpace(input.CardNumber); card != "" && card != m.CardNumber {
+		if err := s.checkCard(ctx, m.ID, card); err != nil {
+			return member.Member{}, err
+		}
+		m.CardNumber = card
+	}
+	// An email or number stored before these checks existed is kept as it
+	// is until someone changes it.
+	if strings.TrimSpace(input.Email) != m.Email {
+		email, err := s.checkEmail(ctx, m.ID, input.Email)
+		if err != nil {
+			return member.Member{}, err
+		}
+		m.Email = email
+	}
+	if strings.TrimSpace(input.Phone) != m.Phone {
+		phone, err := s.normalizePhone(input.Phone)
+		if err != nil {
+			return member.Member{}, err
+		}
+		m.Phone = phone
+	}
+
 	m.Name = input.Name
-	m.Email = input.Email
-	m.Phone = input.Phone
+	m.Type = strings.TrimSpace(input.Type)
+
+	from := m.Status
+	if status := member.Status(strings.ToLower(strings.TrimSpace(input.Status))); status != "" {
+		if m, err = changeStatus(m, status, input.BlockReason, input.By, s.clock.Now()); err != nil {
+			return member.Member{}, err
+		}
+	}
 
 	if err := m.Validate(); err != nil {
-		return member.Member{}, err
+		return member.Member{}, shared.Invalid(err)
 	}
 
 	if err := s.members.Save(ctx, m); err != nil {
 		return member.Member{}, err
 	}
 
-	return m, nil
+	return m, s.publishStatus(ctx, from, m)
 }
 
 func (s MemberService) GetByID(ctx context.Context, id string) (member.Member, error) {
 	return s.members.GetByID(ctx, id)
 }
 
+func (s MemberService) GetByCardNumber(ctx context.Context, card string) (member.Member, error) {
+	return s.members.GetByCardNumber(ctx, card)
+}
+
+// RenewMembership extends a membership by one term. A member the expiry
+// job deactivated becomes active again.
+func (s MemberService) RenewMembership(ctx context.Context, memberID string) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, memberID)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	now := s.clock.Now()
+	renewed, err := member.Renew(m, now, s.policy)
+	if err != nil {
+		return member.Member{}, shared.Invalid(err)
+	}
+	if err := s.members.Save(ctx, renewed); err != nil {
+		return member.Member{}, err
+	}
+
+	if renewed.Status != m.Status {
+		return renewed, publish(ctx, s.events, event.MemberStatusChanged{MemberID: m.ID, From: m.Status, To: renewed.Status, At: now})
+	}
+	return renewed, nil
+}
+
+// ExpireMemberships marks active members whose membership has lapsed as
+// inactive and returns how many were changed. It is meant to run daily.
+func (s MemberService) ExpireMemberships(ctx context.Context) (int, error) {
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	n := 0
+	for _, m := range members {
+		if m.Status != member.StatusActive || !m.IsExpired(now) {
+			continue
+		}
+		if _, err := s.SetStatus(ctx, m.ID, member.StatusInactive); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// SetStatus moves the member between active and inactive. Blocking needs
+// a reason, so blocked members go through BlockService instead.
 func (s MemberService) SetStatus(ctx context.Context, memberID string, status member.Status) (member.Member, error) {
 	m, err := s.members.GetByID(ctx, memberID)
 	if err != nil {
 		return member.Member{}, err
 	}
 
-	m.Status = status
-	if err := m.Validate(); err != nil {
+	from := m.Status
+	if m, err = changeStatus(m, status, "", "", s.clock.Now()); err != nil {
 		return member.Member{}, err
 	}
+	if err := m.Validate(); err != nil {
+		return member.Member{}, shared.Invalid(err)
+	}
 
 	if err := s.members.Save(ctx, m); err != nil {
 		return member.Member{}, err
 	}
 
+	return m, s.publishStatus(ctx, from, m)
+}
+
+// changeStatus moves m to status at now on behalf of by. Blocking records
+// a block and needs a reason, as BlockService.Block does; leaving blocked
+// lifts the blocks in force. A merged record cannot be reactivated.
+func changeStatus(m member.Member, status member.Status, reason, by string, now time.Time) (member.Member, error) {
+	if m.MergedInto != "" && status == member.StatusActive {
+		return member.Member{}, shared.Invalid(errors.New("member was merged into " + m.MergedInto))
+	}
+
+	switch {
+	case status == m.Status:
+		return m, nil
+	case status == member.StatusBlocked:
+		blocked, err := member.AddBlock(m, member.Block{Reason: reason, CreatedBy: by, CreatedAt: now})
+		if err != nil {
+			return member.Member{}, shared.Invalid(err)
+		}
+		return blocked, nil
+	case m.Status == member.StatusBlocked:
+		m = member.Unblock(m, now, by)
+	}
+	m.Status = status
 	return m, nil
 }
 
+func (s MemberService) publishStatus(ctx context.Context, from member.Status, m member.Member) error {
+	if m.Status == from {
+		return nil
+	}
+	return publish(ctx, s.events, event.MemberStatusChanged{MemberID: m.ID, From: from, To: m.Status, At: s.clock.Now()})
+}
+
 func (s MemberService) List(ctx context.Context) ([]member.Member, error) {
 	return s.members.List(ctx)
 }
+
+// checkCard rejects a card number already carried by another member when
+// card numbers must be unique.
+func (s MemberService) checkCard(ctx context.Context, memberID, card string) error {
+	if !s.policy.UniqueCard {
+		return nil
+	}
+
+	other, err := s.members.GetByCardNumber(ctx, card)
+	if errors.Is(err, shared.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != memberID {
+		return shared.ErrDuplicateCard
+	}
+	return nil
+}
+
+// checkEmail validates an address and, when addresses must be unique,
+// rejects one used by another member. Records merged into another member
+// do not count.
+func (s MemberService) checkEmail(ctx context.Context, memberID, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+
+	email, err := member.NormalizeEmail(raw)
+	if err != nil {
+		return "", shared.Invalid(err)
+	}
+	if !s.policy.UniqueEmail {
+		return email, nil
+	}
+
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range members {
+		if other.ID != memberID && other.MergedInto == "" && strings.EqualFold(other.Email, email) {
+			return "", shared.ErrDuplicateEmail
+		}
+	}
+	return email, nil
+}
+
+func (s MemberService) normalizePhone(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+
+	phone, err := member.NormalizePhone(raw, s.policy.PhoneCountryCode)
+	if err != nil {
+		return "", shared.Invalid(err)
+	}
+	return phone, nil
+}
+
+func (s MemberService) nextCard(ctx context.Context) (string, error) {
+	for range cardAttempts {
+		n, err := s.sequences.Next(ctx, s.policy.SequenceName(), s.policy.CardStart)
+		if err != nil {
+			return "", err
+		}
+
+		card := s.policy.Card(n)
+		if _, err := s.members.GetByCardNumber(ctx, card); errors.Is(err, shared.ErrNotFound) {
+			return card, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("no free card number in sequence " + s.policy.SequenceName())
+}
+
+func (s MemberService) SetPIN(ctx context.Context, memberID, pin string) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, memberID)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	if err := member.ValidatePIN(pin); err != nil {
+		return member.Member{}, shared.Invalid(err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return member.Member{}, err
+	}
+
+	hash, err := member.HashPIN(pin, salt)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	m.PINHash = hash
+	if err := s.members.Save(ctx, m); err != nil {
+		return member.Member{}, err
+	}
+
+	return m, nil
+}
+
+// Authenticate checks a PIN for the member with this ID or card number.
+func (s MemberService) Authenticate(ctx context.Context, memberID, pin string) (member.Member, error) {
+	m, err := s.members.GetByID(ctx, memberID)
+	if errors.Is(err, shared.ErrNotFound) {
+		m, err = s.members.GetByCardNumber(ctx, memberID)
+	}
+	if errors.Is(err, shared.ErrNotFound) {
+		return member.Member{}, shared.ErrInvalidCredentials
+	}
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	if !m.HasPIN() || !m.CheckPIN(pin) {
+		return member.Member{}, shared.ErrInvalidCredentials
+	}
+
+	return m, nil
+}
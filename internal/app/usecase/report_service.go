@@ -0,0 +1,518 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+const (
+	monthKeyLayout   = "2006-01"
+	uncategorised    = "(uncategorised)"
+	unassignedMember = "(unassigned)"
+	daysPerYear      = 365.0
+)
+
+type ReportService struct {
+	loans   ports.LoanRepository
+	copies  ports.CopyRepository
+	books   ports.BookRepository
+	members ports.MemberRepository
+	clock   ports.Clock
+}
+
+func NewReportService(
+	loans ports.LoanRepository,
+	copies ports.CopyRepository,
+	books ports.BookRepository,
+	members ports.MemberRepository,
+	clock ports.Clock,
+) ReportService {
+	return ReportService{loans: loans, copies: copies, books: books, members: members, clock: clock}
+}
+
+// TopBorrowed ranks titles by the number of loans issued in the range.
+func (s ReportService) TopBorrowed(ctx context.Context, r dto.ReportRange, limit int) ([]dto.TitleCount, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, l := range data.loans {
+		if c, ok := data.copies[l.CopyID]; ok {
+			counts[c.BookID]++
+		}
+	}
+
+	out := make([]dto.TitleCount, 0, len(counts))
+	for id, n := range counts {
+		out = append(out, dto.TitleCount{BookID: id, Title: data.books[id].Title, Loans: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Loans != out[j].Loans {
+			return out[i].Loans > out[j].Loans
+		}
+		return out[i].Title < out[j].Title
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+
+	return out, nil
+}
+
+func (s ReportService) ByCategory(ctx context.Context, r dto.ReportRange) ([]dto.GroupCount, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, l := range data.loans {
+		c, ok := data.copies[l.CopyID]
+		if !ok {
+			continue
+		}
+		category := data.books[c.BookID].Category
+		if category == "" {
+			category = uncategorised
+		}
+		counts[category]++
+	}
+
+	return byCount(counts), nil
+}
+
+// ByMonth counts loans per calendar month in the range's time zone, so the
+// month keys line up with the bounds the caller asked for. Months inside the
+// range with no loans are reported as zero.
+func (s ReportService) ByMonth(ctx context.Context, r dto.ReportRange) ([]dto.GroupCount, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := data.location()
+	counts := map[string]int{}
+	for _, l := range data.loans {
+		counts[l.IssuedAt.In(loc).Format(monthKeyLayout)]++
+	}
+
+	from, to := data.span()
+	if !from.IsZero() {
+		from = from.In(loc)
+		month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, loc)
+		for month.Before(to) {
+			counts[month.Format(monthKeyLayout)] += 0
+			month = month.AddDate(0, 1, 0)
+		}
+	}
+
+	out := make([]dto.GroupCount, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, dto.GroupCount{Key: k, Loans: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (s ReportService) ByMemberType(ctx context.Context, r dto.ReportRange) ([]dto.GroupCount, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]string{}
+	counts := map[string]int{}
+	for _, m := range members {
+		t := m.Type
+		if t == "" {
+			t = unassignedMember
+		}
+		types[m.ID] = t
+		counts[t] += 0
+	}
+	for _, l := range data.loans {
+		t, ok := types[l.MemberID]
+		if !ok {
+			t = unassignedMember
+		}
+		counts[t]++
+	}
+
+	return byCount(counts), nil
+}
+
+// Turnover reports, for every copy, the loans issued in the range and that
+// count annualised over the range.
+func (s ReportService) Turnover(ctx context.Context, r dto.ReportRange) ([]dto.CopyTurnover, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, l := range data.loans {
+		counts[l.CopyID]++
+	}
+
+	years := 0.0
+	if from, to := data.span(); !from.IsZero() && to.After(from) {
+		years = to.Sub(from).Hours() / 24 / daysPerYear
+	}
+
+	out := make([]dto.CopyTurnover, 0, len(data.copies))
+	for _, c := range data.copies {
+		t := dto.CopyTurnover{
+			CopyID:  c.ID,
+			Barcode: c.Barcode,
+			BookID:  c.BookID,
+			Title:   data.books[c.BookID].Title,
+			Loans:   counts[c.ID],
+		}
+		if years > 0 {
+			t.PerYear = float64(t.Loans) / years
+		}
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Loans != out[j].Loans {
+			return out[i].Loans > out[j].Loans
+		}
+		return out[i].Barcode < out[j].Barcode
+	})
+
+	return out, nil
+}
+
+// NeverBorrowed lists weeding candidates: copies of active titles with no
+// loans issued in the range. Lost copies are left out.
+func (s ReportService) NeverBorrowed(ctx context.Context, r dto.ReportRange) ([]dto.CopyRef, error) {
+	data, err := s.load(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	borrowed := map[string]bool{}
+	for _, l := range data.loans {
+		borrowed[l.CopyID] = true
+	}
+
+	out := make([]dto.CopyRef, 0)
+	for _, c := range data.copies {
+		b := data.books[c.BookID]
+		if borrowed[c.ID] || c.Status == copy.StatusLost || b.Status == book.StatusArchived {
+			continue
+		}
+		out = append(out, dto.CopyRef{CopyID: c.ID, Barcode: c.Barcode, BookID: c.BookID, Title: b.Title, Status: string(c.Status)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Title != out[j].Title {
+			return out[i].Title < out[j].Title
+		}
+		return out[i].Barcode < out[j].Barcode
+	})
+
+	return out, nil
+}
+
+// ExpiringMembers lists active members whose membership ends within days,
+// soonest first.
+func (s ReportService) ExpiringMembers(ctx context.Context, days int) ([]dto.ExpiringMember, error) {
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := map[string]int{}
+	for _, l := range loans {
+		if l.Status == loan.StatusActive {
+			active[l.MemberID]++
+		}
+	}
+
+	now := s.clock.Now()
+	horizon := now.AddDate(0, 0, days)
+	out := make([]dto.ExpiringMember, 0)
+	for _, m := range members {
+		if m.Status != member.StatusActive || m.ExpiresAt.IsZero() || !m.ExpiresAt.Before(horizon) {
+			continue
+		}
+		out = append(out, dto.ExpiringMember{
+			Member:      m,
+			DaysLeft:    int(math.Floor(m.ExpiresAt.Sub(now).Hours() / 24)),
+			ActiveLoans: active[m.ID],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Member.ExpiresAt.Equal(out[j].Member.ExpiresAt) {
+			return out[i].Member.ExpiresAt.Before(out[j].Member.ExpiresAt)
+		}
+		return out[i].Member.ID < out[j].Member.ID
+	})
+
+	return out, nil
+}
+
+// Table runs a report and flattens it for display or CSV export. limit only
+// applies to ranked reports.
+func (s ReportService) Table(ctx context.Context, kind dto.ReportKind, r dto.ReportRange, limit int) (dto.ReportTable, error) {
+	t := dto.ReportTable{Kind: kind}
+
+	switch kind {
+	case dto.ReportTopBorrowed:
+		items, err := s.TopBorrowed(ctx, r, limit)
+		if err != nil {
+			return t, err
+		}
+		t.Columns = []string{"Rank", "Book ID", "Title", "Loans"}
+		for i, it := range items {
+			t.Rows = append(t.Rows, []string{strconv.Itoa(i + 1), it.BookID, it.Title, strconv.Itoa(it.Loans)})
+		}
+	case dto.ReportByCategory, dto.ReportByMonth, dto.ReportByMemberType:
+		var (
+			items []dto.GroupCount
+			err   error
+		)
+		switch kind {
+		case dto.ReportByCategory:
+			items, err = s.ByCategory(ctx, r)
+			t.Columns = []string{"Category", "Loans"}
+		case dto.ReportByMonth:
+			items, err = s.ByMonth(ctx, r)
+			t.Columns = []string{"Month", "Loans"}
+		default:
+			items, err = s.ByMemberType(ctx, r)
+			t.Columns = []string{"Member Type", "Loans"}
+		}
+		if err != nil {
+			return t, err
+		}
+		for _, it := range items {
+			t.Rows = append(t.Rows, []string{it.Key, strconv.Itoa(it.Loans)})
+		}
+	case dto.ReportTurnover:
+		items, err := s.Turnover(ctx, r)
+		if err != nil {
+			return t, err
+		}
+		t.Columns = []string{"Copy ID", "Barcode", "Title", "Loans", "Per Year"}
+		for _, it := range items {
+			t.Rows = append(t.Rows, []string{it.CopyID, it.Barcode, it.Title, strconv.Itoa(it.Loans), strconv.FormatFloat(it.PerYear, 'f', 2, 64)})
+		}
+	case dto.ReportNeverBorrowed:
+		items, err := s.NeverBorrowed(ctx, r)
+		if err != nil {
+			return t, err
+		}
+		t.Columns = []string{"Copy ID", "Barcode", "Title", "Status"}
+		for _, it := range items {
+			t.Rows = append(t.Rows, []string{it.CopyID, it.Barcode, it.Title, it.Status})
+		}
+	default:
+		return t, shared.Invalid(fmt.Errorf("unknown report %q", kind))
+	}
+
+	return t, nil
+}
+
+// Dashboard summarises the collection and circulation, with daily issue and
+// return counts for the last days days in local time. A non-empty branch
+// limits copies and loans to that home branch.
+func (s ReportService) Dashboard(ctx context.Context, days int, branch string) (dto.Dashboard, error) {
+	var d dto.Dashboard
+
+	books, err := s.books.List(ctx)
+	if err != nil {
+		return d, err
+	}
+	for _, b := range books {
+		if b.Status == book.StatusActive {
+			d.Titles++
+		}
+	}
+
+	copies, err := s.copies.List(ctx)
+	if err != nil {
+		return d, err
+	}
+	owned := make(map[string]bool, len(copies))
+	for _, c := range copies {
+		if branch != "" && c.Branch != branch {
+			continue
+		}
+		owned[c.ID] = true
+		d.Copies++
+		if c.Status == copy.StatusAvailable {
+			d.AvailableCopies++
+		}
+	}
+
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return d, err
+	}
+	d.Members = len(members)
+	for _, m := range members {
+		if m.Status == member.StatusActive {
+			d.ActiveMembers++
+		}
+	}
+
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return d, err
+	}
+
+	if days < 1 {
+		days = 1
+	}
+	now := s.clock.Now()
+	today := localDay(now)
+	d.Issued = make([]int, days)
+	d.Returned = make([]int, days)
+	dayIndex := func(t time.Time) int {
+		i := days - 1 - (today - localDay(t))
+		if i < 0 || i >= days {
+			return -1
+		}
+		return i
+	}
+
+	for _, l := range loans {
+		if branch != "" && !owned[l.CopyID] {
+			continue
+		}
+		if l.Status == loan.StatusActive {
+			d.ActiveLoans++
+		}
+		if l.IsOverdue(now) {
+			d.Overdue++
+		}
+		if i := dayIndex(l.IssuedAt); i >= 0 {
+			d.Issued[i]++
+		}
+		if l.ReturnedAt != nil {
+			if i := dayIndex(*l.ReturnedAt); i >= 0 {
+				d.Returned[i]++
+			}
+		}
+	}
+	d.LoansToday = d.Issued[days-1]
+	d.ReturnsToday = d.Returned[days-1]
+
+	return d, nil
+}
+
+type reportData struct {
+	rng    dto.ReportRange
+	now    time.Time
+	loans  []loan.Loan
+	copies map[string]copy.Copy
+	books  map[string]book.Book
+}
+
+func (s ReportService) load(ctx context.Context, r dto.ReportRange) (reportData, error) {
+	data := reportData{rng: r, now: s.clock.Now(), copies: map[string]copy.Copy{}, books: map[string]book.Book{}}
+
+	copies, err := s.copies.List(ctx)
+	if err != nil {
+		return data, err
+	}
+	for _, c := range copies {
+		if r.Branch == "" || c.Branch == r.Branch {
+			data.copies[c.ID] = c
+		}
+	}
+
+	loans, err := s.loans.List(ctx)
+	if err != nil {
+		return data, err
+	}
+	for _, l := range loans {
+		if _, ok := data.copies[l.CopyID]; (ok || r.Branch == "") && r.Contains(l.IssuedAt) {
+			data.loans = append(data.loans, l)
+		}
+	}
+
+	books, err := s.books.List(ctx)
+	if err != nil {
+		return data, err
+	}
+	for _, b := range books {
+		data.books[b.ID] = b
+	}
+
+	return data, nil
+}
+
+// span resolves open range bounds: an open start becomes the first loan in
+// the range and an open end becomes now.
+func (d reportData) span() (time.Time, time.Time) {
+	from, to := d.rng.From, d.rng.To
+	if to.IsZero() || to.After(d.now) {
+		to = d.now
+	}
+	if from.IsZero() {
+		for _, l := range d.loans {
+			if from.IsZero() || l.IssuedAt.Before(from) {
+				from = l.IssuedAt
+			}
+		}
+	}
+	return from, to
+}
+
+// location is the time zone the range was given in, falling back to the
+// local zone when the range is open on both ends.
+func (d reportData) location() *time.Location {
+	switch {
+	case !d.rng.From.IsZero():
+		return d.rng.From.Location()
+	case !d.rng.To.IsZero():
+		return d.rng.To.Location()
+	default:
+		return time.Local
+	}
+}
+
+// localDay numbers calendar days in the local time zone.
+func localDay(t time.Time) int {
+	t = t.Local()
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+func byCount(counts map[string]int) []dto.GroupCount {
+	out := make([]dto.GroupCount, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, dto.GroupCount{Key: k, Loans: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Loans != out[j].Loans {
+			return out[i].Loans > out[j].Loans
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
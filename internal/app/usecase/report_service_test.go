@@ -0,0 +1,264 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func newReportFixture() usecase.ReportService {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 10, 0, 0, 0, time.UTC) }
+
+	books := &bookRepo{books: map[string]book.Book{
+		"b-go":   {ID: "b-go", Title: "Go", Category: "Programming", Status: book.StatusActive},
+		"b-rust": {ID: "b-rust", Title: "Rust", Category: "Programming", Status: book.StatusActive},
+		"b-poem": {ID: "b-poem", Title: "Poems", Status: book.StatusActive},
+		"b-old":  {ID: "b-old", Title: "Old", Category: "History", Status: book.StatusArchived},
+	}}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-go-1":   {ID: "c-go-1", BookID: "b-go", Barcode: "GO-1", Status: copy.StatusAvailable},
+		"c-go-2":   {ID: "c-go-2", BookID: "b-go", Barcode: "GO-2", Status: copy.StatusAvailable},
+		"c-rust-1": {ID: "c-rust-1", BookID: "b-rust", Barcode: "RU-1", Status: copy.StatusAvailable, Branch: "EAST"},
+		"c-poem-1": {ID: "c-poem-1", BookID: "b-poem", Barcode: "PO-1", Status: copy.StatusAvailable, Branch: "EAST"},
+		"c-poem-2": {ID: "c-poem-2", BookID: "b-poem", Barcode: "PO-2", Status: copy.StatusLost},
+		"c-old-1":  {ID: "c-old-1", BookID: "b-old", Barcode: "OL-1", Status: copy.StatusAvailable},
+	}}
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Type: "student"},
+		"m-2": {ID: "m-2", Type: "staff"},
+		"m-3": {ID: "m-3"},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", CopyID: "c-go-1", MemberID: "m-1", IssuedAt: day(1, 5)},
+		"l-2": {ID: "l-2", CopyID: "c-go-2", MemberID: "m-1", IssuedAt: day(1, 20)},
+		"l-3": {ID: "l-3", CopyID: "c-go-1", MemberID: "m-2", IssuedAt: day(3, 2)},
+		"l-4": {ID: "l-4", CopyID: "c-rust-1", MemberID: "m-3", IssuedAt: day(3, 15)},
+		"l-5": {ID: "l-5", CopyID: "c-poem-1", MemberID: "m-2", IssuedAt: day(5, 1)},
+	}}
+
+	return usecase.NewReportService(loans, copies, books, members, stubClock{now: day(6, 1)})
+}
+
+func TestReportServiceGroupings(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newReportFixture()
+	q1 := dto.ReportRange{From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	top, err := svc.TopBorrowed(ctx, dto.ReportRange{}, 2)
+	if err != nil {
+		t.Fatalf("top borrowed: %v", err)
+	}
+	want := []dto.TitleCount{{BookID: "b-go", Title: "Go", Loans: 3}, {BookID: "b-poem", Title: "Poems", Loans: 1}}
+	if !reflect.DeepEqual(top, want) {
+		t.Fatalf("expected %+v got %+v", want, top)
+	}
+
+	tests := []struct {
+		name string
+		run  func() ([]dto.GroupCount, error)
+		want []dto.GroupCount
+	}{
+		{
+			name: "category",
+			run:  func() ([]dto.GroupCount, error) { return svc.ByCategory(ctx, dto.ReportRange{}) },
+			want: []dto.GroupCount{{Key: "Programming", Loans: 4}, {Key: "(uncategorised)", Loans: 1}},
+		},
+		{
+			name: "month fills gaps inside the range",
+			run:  func() ([]dto.GroupCount, error) { return svc.ByMonth(ctx, q1) },
+			want: []dto.GroupCount{{Key: "2026-01", Loans: 2}, {Key: "2026-02", Loans: 0}, {Key: "2026-03", Loans: 2}},
+		},
+		{
+			name: "member type",
+			run:  func() ([]dto.GroupCount, error) { return svc.ByMemberType(ctx, q1) },
+			want: []dto.GroupCount{{Key: "student", Loans: 2}, {Key: "(unassigned)", Loans: 1}, {Key: "staff", Loans: 1}},
+		},
+		{
+			name: "category at one branch",
+			run:  func() ([]dto.GroupCount, error) { return svc.ByCategory(ctx, dto.ReportRange{Branch: "EAST"}) },
+			want: []dto.GroupCount{{Key: "(uncategorised)", Loans: 1}, {Key: "Programming", Loans: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.run()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: expected %+v got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestReportServiceTurnoverAndWeeding(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newReportFixture()
+	rng := dto.ReportRange{From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 7, 2, 12, 0, 0, 0, time.UTC)}
+
+	turnover, err := svc.Turnover(ctx, rng)
+	if err != nil {
+		t.Fatalf("turnover: %v", err)
+	}
+	if turnover[0].CopyID != "c-go-1" || turnover[0].Loans != 2 {
+		t.Fatalf("expected c-go-1 first, got %+v", turnover[0])
+	}
+	// The range end is capped at now (2026-06-01), so the span is 151.4 days.
+	if got := turnover[0].PerYear; got < 4.8 || got > 4.9 {
+		t.Fatalf("expected about 4.8 loans per year, got %.2f", got)
+	}
+
+	never, err := svc.NeverBorrowed(ctx, dto.ReportRange{From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("never borrowed: %v", err)
+	}
+	var ids []string
+	for _, c := range never {
+		ids = append(ids, c.CopyID)
+	}
+	if strings.Join(ids, ",") != "c-go-2" {
+		t.Fatalf("expected only c-go-2 (lost and archived copies excluded), got %v", ids)
+	}
+}
+
+func TestReportServiceTable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newReportFixture()
+
+	for _, kind := range dto.ReportKinds {
+		table, err := svc.Table(ctx, kind, dto.ReportRange{}, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		for _, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				t.Fatalf("%s: row %v does not match columns %v", kind, row, table.Columns)
+			}
+		}
+	}
+
+	if _, err := svc.Table(ctx, "popularity", dto.ReportRange{}, 0); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected invalid input for unknown report, got %v", err)
+	}
+}
+
+func TestReportServiceDashboard(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	returned := now.Add(-time.Hour)
+	yesterday := now.AddDate(0, 0, -1)
+
+	svc := usecase.NewReportService(
+		&loanRepo{loans: map[string]loan.Loan{
+			"l-1": {ID: "l-1", CopyID: "c-1", IssuedAt: now.Add(-2 * time.Hour), DueAt: now.AddDate(0, 0, 14), Status: loan.StatusActive},
+			"l-2": {ID: "l-2", CopyID: "c-2", IssuedAt: yesterday, DueAt: yesterday.AddDate(0, 0, 14), ReturnedAt: &returned, Status: loan.StatusReturned},
+			"l-3": {ID: "l-3", CopyID: "c-3", IssuedAt: now.AddDate(0, 0, -40), DueAt: now.AddDate(0, 0, -26), Status: loan.StatusActive},
+		}},
+		&copyRepo{copies: map[string]copy.Copy{
+			"c-1": {ID: "c-1", Status: copy.StatusLoaned},
+			"c-2": {ID: "c-2", Status: copy.StatusAvailable},
+			"c-3": {ID: "c-3", Status: copy.StatusLoaned},
+			"c-4": {ID: "c-4", Status: copy.StatusDamaged},
+		}},
+		&bookRepo{books: map[string]book.Book{
+			"b-1": {ID: "b-1", Status: book.StatusActive},
+			"b-2": {ID: "b-2", Status: book.StatusArchived},
+		}},
+		&memberRepo{members: map[string]member.Member{
+			"m-1": {ID: "m-1", Status: member.StatusActive},
+			"m-2": {ID: "m-2", Status: member.StatusBlocked},
+		}},
+		stubClock{now: now},
+	)
+
+	d, err := svc.Dashboard(context.Background(), 7, "")
+	if err != nil {
+		t.Fatalf("dashboard: %v", err)
+	}
+
+	want := dto.Dashboard{
+		Titles: 1, Copies: 4, AvailableCopies: 1, Members: 2, ActiveMembers: 1,
+		ActiveLoans: 2, LoansToday: 1, ReturnsToday: 1, Overdue: 1,
+		Issued:   []int{0, 0, 0, 0, 0, 1, 1},
+		Returned: []int{0, 0, 0, 0, 0, 0, 1},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("expected %+v got %+v", want, d)
+	}
+}
+
+func TestReportServiceExpiringMembers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Status: member.StatusActive, ExpiresAt: now.AddDate(0, 0, 10)},
+		"m-2": {ID: "m-2", Status: member.StatusActive, ExpiresAt: now.Add(-time.Hour)},
+		"m-3": {ID: "m-3", Status: member.StatusActive, ExpiresAt: now.AddDate(0, 2, 0)},
+		"m-4": {ID: "m-4", Status: member.StatusActive},
+		"m-5": {ID: "m-5", Status: member.StatusInactive, ExpiresAt: now.AddDate(0, 0, 3)},
+	}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		"l-1": {ID: "l-1", MemberID: "m-1", Status: loan.StatusActive},
+		"l-2": {ID: "l-2", MemberID: "m-1", Status: loan.StatusReturned},
+	}}
+	svc := usecase.NewReportService(loans, &copyRepo{}, &bookRepo{}, members, stubClock{now: now})
+
+	got, err := svc.ExpiringMembers(context.Background(), 30)
+	if err != nil {
+		t.Fatalf("expiring members: %v", err)
+	}
+
+	var summary []string
+	for _, it := range got {
+		summary = append(summary, fmt.Sprintf("%s %d %d", it.Member.ID, it.DaysLeft, it.ActiveLoans))
+	}
+	if want := []string{"m-2 -1 0", "m-1 10 1"}; !reflect.DeepEqual(summary, want) {
+		t.Fatalf("expected %v got %v", want, summary)
+	}
+}
+
+func TestReportMonthsUseRangeZone(t *testing.T) {
+	t.Parallel()
+
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	books := &bookRepo{books: map[string]book.Book{"b-go": {ID: "b-go", Title: "Go", Status: book.StatusActive}}}
+	copies := &copyRepo{copies: map[string]copy.Copy{"c-go-1": {ID: "c-go-1", BookID: "b-go", Barcode: "GO-1", Status: copy.StatusAvailable}}}
+	members := &memberRepo{members: map[string]member.Member{"m-1": {ID: "m-1"}}}
+	loans := &loanRepo{loans: map[string]loan.Loan{
+		// 23:00 UTC on 31 January is already 1 February in the range's zone.
+		"l-1": {ID: "l-1", CopyID: "c-go-1", MemberID: "m-1", IssuedAt: time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)},
+	}}
+	svc := usecase.NewReportService(loans, copies, books, members, stubClock{now: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)})
+
+	got, err := svc.ByMonth(context.Background(), dto.ReportRange{
+		From: time.Date(2026, 2, 1, 0, 0, 0, 0, zone),
+		To:   time.Date(2026, 3, 1, 0, 0, 0, 0, zone),
+	})
+	if err != nil {
+		t.Fatalf("by month: %v", err)
+	}
+	want := []dto.GroupCount{{Key: "2026-02", Loans: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v got %+v", want, got)
+	}
+}
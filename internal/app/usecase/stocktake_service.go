@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/stocktake"
+)
+
+type StocktakeService struct {
+	stocktakes ports.StocktakeRepository
+	copies     CopyService
+	idGen      ports.IDGenerator
+	clock      ports.Clock
+}
+
+func NewStocktakeService(stocktakes ports.StocktakeRepository, copies CopyService, idGen ports.IDGenerator, clock ports.Clock) StocktakeService {
+	return StocktakeService{stocktakes: stocktakes, copies: copies, idGen: idGen, clock: clock}
+}
+
+// Start opens a session. With a branch, only copies at that branch are
+// reconciled against the scans.
+func (s StocktakeService) Start(ctx context.Context, name, branch string) (stocktake.Session, error) {
+	now := s.clock.Now()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Stocktake " + now.Format("2006-01-02")
+	}
+
+	st := stocktake.Session{ID: s.idGen.NewID(), Name: name, Branch: strings.TrimSpace(branch), StartedAt: now, Status: stocktake.StatusOpen}
+	if err := st.Validate(); err != nil {
+		return stocktake.Session{}, shared.Invalid(err)
+	}
+
+	if err := s.stocktakes.Save(ctx, st); err != nil {
+		return stocktake.Session{}, err
+	}
+
+	return st, nil
+}
+
+func (s StocktakeService) Get(ctx context.Context, id string) (stocktake.Session, error) {
+	return s.stocktakes.GetByID(ctx, id)
+}
+
+// List returns sessions newest first.
+func (s StocktakeService) List(ctx context.Context) ([]stocktake.Session, error) {
+	items, err := s.stocktakes.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].StartedAt.After(items[j].StartedAt) })
+	return items, nil
+}
+
+// Scan records a barcode seen on shelf. Unknown barcodes are recorded too so
+// they show up in the reconciliation.
+func (s StocktakeService) Scan(ctx context.Context, id, shelf, barcode string) (dto.ScanResult, error) {
+	st, err := s.stocktakes.GetByID(ctx, id)
+	if err != nil {
+		return dto.ScanResult{}, err
+	}
+
+	_, repeat := st.Seen()[strings.TrimSpace(barcode)]
+
+	st, err = stocktake.AddScan(st, barcode, shelf, s.clock.Now())
+	if err != nil {
+		return dto.ScanResult{}, err
+	}
+
+	if err := s.stocktakes.Save(ctx, st); err != nil {
+		return dto.ScanResult{}, err
+	}
+
+	last := st.Scans[len(st.Scans)-1]
+	result := dto.ScanResult{Barcode: last.Barcode, Shelf: last.Shelf, Repeat: repeat}
+
+	c, err := s.copies.GetByBarcode(ctx, last.Barcode)
+	if err != nil && !errors.Is(err, shared.ErrNotFound) {
+		return dto.ScanResult{}, err
+	}
+	if err == nil {
+		result.Known = true
+		result.CopyID = c.ID
+		result.Status = c.Status
+	}
+
+	return result, nil
+}
+
+func (s StocktakeService) Close(ctx context.Context, id string) (stocktake.Session, error) {
+	st, err := s.stocktakes.GetByID(ctx, id)
+	if err != nil {
+		return stocktake.Session{}, err
+	}
+
+	st, err = stocktake.Close(st, s.clock.Now())
+	if err != nil {
+		return stocktake.Session{}, err
+	}
+
+	if err := s.stocktakes.Save(ctx, st); err != nil {
+		return stocktake.Session{}, err
+	}
+
+	return st, nil
+}
+
+func (s StocktakeService) Reconcile(ctx context.Context, id string) (dto.Reconciliation, error) {
+	st, err := s.stocktakes.GetByID(ctx, id)
+	if err != nil {
+		return dto.Reconciliation{}, err
+	}
+
+	copies, err := s.copies.List(ctx)
+	if err != nil {
+		return dto.Reconciliation{}, err
+	}
+
+	seen := st.Seen()
+	known := make(map[string]bool, len(copies))
+	out := dto.Reconciliation{SessionID: st.ID, Scanned: len(seen)}
+
+	for _, c := range copies {
+		// A copy without a barcode can never be scanned, and copies at
+		// other branches are not on the shelves being checked.
+		if c.Barcode == "" {
+			continue
+		}
+		known[c.Barcode] = true
+		if !st.Covers(c.At()) {
+			continue
+		}
+		shelf, ok := seen[c.Barcode]
+		item := dto.StocktakeItem{CopyID: c.ID, BookID: c.BookID, Barcode: c.Barcode, Shelf: shelf}
+
+		switch {
+		case ok:
+			out.Matched++
+			if c.Status == copy.StatusLoaned {
+				out.OnLoan = append(out.OnLoan, item)
+			}
+		case c.Status == copy.StatusAvailable:
+			out.Missing = append(out.Missing, item)
+		}
+	}
+
+	for barcode, shelf := range seen {
+		if !known[barcode] {
+			out.Unknown = append(out.Unknown, dto.StocktakeItem{Barcode: barcode, Shelf: shelf})
+		}
+	}
+
+	for _, items := range [][]dto.StocktakeItem{out.Missing, out.OnLoan, out.Unknown} {
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].Shelf != items[j].Shelf {
+				return items[i].Shelf < items[j].Shelf
+			}
+			return items[i].Barcode < items[j].Barcode
+		})
+	}
+
+	return out, nil
+}
+
+// MarkMissingLost marks every copy the reconciliation reports as missing
+// lost, through CopyService.Update so the status change is published. The
+// session must be closed first so an unfinished scan marks nothing.
+func (s StocktakeService) MarkMissingLost(ctx context.Context, id string) ([]copy.Copy, error) {
+	st, err := s.stocktakes.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.IsOpen() {
+		return nil, shared.Invalid(errors.New("close the stocktake before marking missing copies lost"))
+	}
+
+	rec, err := s.Reconcile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]copy.Copy, 0, len(rec.Missing))
+	for _, item := range rec.Missing {
+		c, err := s.copies.GetByID(ctx, item.CopyID)
+		if err != nil {
+			return out, err
+		}
+
+		updated, err := s.copies.Update(ctx, dto.UpdateCopyInput{
+			ID:            c.ID,
+			Barcode:       c.Barcode,
+			Status:        string(copy.StatusLost),
+			ConditionNote: c.ConditionNote,
+			Branch:        c.Branch,
+			Location:      c.Location,
+			CallNumber:    c.CallNumber,
+		})
+		if err != nil {
+			return out, err
+		}
+		out = append(out, updated)
+	}
+
+	return out, nil
+}
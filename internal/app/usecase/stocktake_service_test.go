@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/stocktake"
+)
+
+func TestStocktakeServiceReconcile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Barcode: "BC-1", Status: copy.StatusAvailable, ConditionNote: "worn", Branch: "MAIN"},
+		"c-2": {ID: "c-2", BookID: "b-1", Barcode: "BC-2", Status: copy.StatusAvailable, Branch: "MAIN"},
+		"c-3": {ID: "c-3", BookID: "b-2", Barcode: "BC-3", Status: copy.StatusLoaned, Branch: "MAIN"},
+		"c-4": {ID: "c-4", BookID: "b-2", Barcode: "BC-4", Status: copy.StatusLoaned, Branch: "MAIN"},
+		"c-5": {ID: "c-5", BookID: "b-3", Barcode: "BC-5", Status: copy.StatusDamaged, Branch: "MAIN"},
+		"c-6": {ID: "c-6", BookID: "b-3", Barcode: "BC-6", Status: copy.StatusAvailable, Branch: "EAST"},
+		"c-7": {ID: "c-7", BookID: "b-3", Barcode: "BC-7", Status: copy.StatusAvailable, Branch: "MAIN", CurrentBranch: "EAST"},
+		"c-8": {ID: "c-8", BookID: "b-3", Status: copy.StatusAvailable, Branch: "MAIN"},
+	}}
+	events := &recordingPublisher{}
+	clock := stubClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	copySvc := usecase.NewCopyService(copies, nil, &seqIDGen{}, clock, copy.Policy{}, events)
+	svc := usecase.NewStocktakeService(&stocktakeRepo{sessions: map[string]stocktake.Session{}}, copySvc, &seqIDGen{}, clock)
+
+	st, err := svc.Start(ctx, "", " MAIN ")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if st.Name != "Stocktake 2026-03-01" || st.Branch != "MAIN" {
+		t.Fatalf("unexpected default name %q or branch %q", st.Name, st.Branch)
+	}
+
+	scans := []struct {
+		shelf, barcode string
+		want           dto.ScanResult
+	}{
+		{"A1", "BC-2", dto.ScanResult{Barcode: "BC-2", Shelf: "A1", CopyID: "c-2", Status: copy.StatusAvailable, Known: true}},
+		{"A1", "BC-3", dto.ScanResult{Barcode: "BC-3", Shelf: "A1", CopyID: "c-3", Status: copy.StatusLoaned, Known: true}},
+		{"A2", " XX-9 ", dto.ScanResult{Barcode: "XX-9", Shelf: "A2"}},
+		{"A2", "BC-2", dto.ScanResult{Barcode: "BC-2", Shelf: "A2", CopyID: "c-2", Status: copy.StatusAvailable, Known: true, Repeat: true}},
+	}
+	for _, sc := range scans {
+		got, err := svc.Scan(ctx, st.ID, sc.shelf, sc.barcode)
+		if err != nil {
+			t.Fatalf("scan %q: %v", sc.barcode, err)
+		}
+		if got != sc.want {
+			t.Fatalf("scan %q: expected %+v got %+v", sc.barcode, sc.want, got)
+		}
+	}
+
+	rec, err := svc.Reconcile(ctx, st.ID)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	want := dto.Reconciliation{
+		SessionID: st.ID,
+		Scanned:   3,
+		Matched:   2,
+		Missing:   []dto.StocktakeItem{{CopyID: "c-1", BookID: "b-1", Barcode: "BC-1"}},
+		OnLoan:    []dto.StocktakeItem{{CopyID: "c-3", BookID: "b-2", Barcode: "BC-3", Shelf: "A1"}},
+		Unknown:   []dto.StocktakeItem{{Barcode: "XX-9", Shelf: "A2"}},
+	}
+	if !reflect.DeepEqual(rec, want) {
+		t.Fatalf("expected %+v got %+v", want, rec)
+	}
+
+	if _, err := svc.MarkMissingLost(ctx, st.ID); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected an open stocktake to be refused, got %v", err)
+	}
+	if c := copies.copies["c-1"]; c.Status != copy.StatusAvailable {
+		t.Fatalf("expected c-1 untouched while the stocktake is open, got %+v", c)
+	}
+	if _, err := svc.Close(ctx, st.ID); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	marked, err := svc.MarkMissingLost(ctx, st.ID)
+	if err != nil {
+		t.Fatalf("mark lost: %v", err)
+	}
+	if len(marked) != 1 || marked[0].ID != "c-1" {
+		t.Fatalf("unexpected marked copies %+v", marked)
+	}
+	if c := copies.copies["c-1"]; c.Status != copy.StatusLost || c.Barcode != "BC-1" || c.ConditionNote != "worn" {
+		t.Fatalf("expected c-1 lost with barcode and note kept, got %+v", c)
+	}
+	if !reflect.DeepEqual(events.types(), []event.Type{event.TypeCopyStatusChanged}) {
+		t.Fatalf("expected one status change event, got %v", events.types())
+	}
+
+	if _, err := svc.Scan(ctx, st.ID, "A3", "BC-4"); !errors.Is(err, shared.ErrStocktakeClosed) {
+		t.Fatalf("expected closed error, got %v", err)
+	}
+}
+
+type stocktakeRepo struct {
+	sessions map[string]stocktake.Session
+}
+
+func (r *stocktakeRepo) Save(_ context.Context, s stocktake.Session) error {
+	r.sessions[s.ID] = s
+	return nil
+}
+
+func (r *stocktakeRepo) GetByID(_ context.Context, id string) (stocktake.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return stocktake.Session{}, shared.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *stocktakeRepo) List(_ context.Context) ([]stocktake.Session, error) {
+	out := make([]stocktake.Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		out = append(out, s)
+	}
+	return out, nil
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
+)
+
+type TransferService struct {
+	transfers ports.TransferRepository
+	branches  ports.BranchRepository
+	copies    ports.CopyRepository
+	loans     LoanService
+	idGen     ports.IDGenerator
+	clock     ports.Clock
+	events    ports.EventPublisher
+}
+
+func NewTransferService(
+	transfers ports.TransferRepository,
+	branches ports.BranchRepository,
+	copies ports.CopyRepository,
+	loans LoanService,
+	idGen ports.IDGenerator,
+	clock ports.Clock,
+	events ports.EventPublisher,
+) TransferService {
+	return TransferService{
+		transfers: transfers,
+		branches:  branches,
+		copies:    copies,
+		loans:     loans,
+		idGen:     idGen,
+		clock:     clock,
+		events:    events,
+	}
+}
+
+func (s TransferService) Request(ctx context.Context, input dto.RequestTransferInput) (transfer.Transfer, error) {
+	to, err := s.branchCode(ctx, input.To)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	c, err := s.copies.GetByID(ctx, input.CopyID)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	if open, err := s.openFor(ctx, c.ID); err != nil {
+		return transfer.Transfer{}, err
+	} else if open {
+		return transfer.Transfer{}, shared.ErrTransferOpen
+	}
+
+	t, err := transfer.Request(s.idGen.NewID(), c, to, s.clock.Now())
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	if err := s.transfers.Save(ctx, t); err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	return t, nil
+}
+
+// Ship marks a requested transfer as sent; the copy is in transit until it
+// is received.
+func (s TransferService) Ship(ctx context.Context, id string) (transfer.Transfer, error) {
+	t, err := s.transfers.GetByID(ctx, id)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	c, err := s.copies.GetByID(ctx, t.CopyID)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+	if !c.IsAvailable() {
+		return transfer.Transfer{}, shared.ErrCopyNotAvailable
+	}
+
+	now := s.clock.Now()
+	t, err = transfer.Ship(t, now)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	if err := s.transfers.Save(ctx, t); err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	return t, s.moveCopy(ctx, c, copy.StatusInTransit, c.CurrentBranch, now)
+}
+
+// Receive closes a transfer at its destination and puts the copy back on
+// the shelf there. The copy must still be in transit; one marked lost or
+// damaged on the way is left for staff to sort out.
+func (s TransferService) Receive(ctx context.Context, id string) (transfer.Transfer, error) {
+	t, err := s.transfers.GetByID(ctx, id)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	now := s.clock.Now()
+	t, err = transfer.Receive(t, now)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	c, err := s.copies.GetByID(ctx, t.CopyID)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+	if c.Status != copy.StatusInTransit {
+		return transfer.Transfer{}, fmt.Errorf("%w: copy %s is %s, not in transit", shared.ErrTransferState, c.ID, c.Status)
+	}
+
+	if err := s.transfers.Save(ctx, t); err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	current := t.To
+	if current == c.Branch {
+		current = ""
+	}
+	return t, s.moveCopy(ctx, c, copy.StatusAvailable, current, now)
+}
+
+func (s TransferService) Cancel(ctx context.Context, id string) (transfer.Transfer, error) {
+	t, err := s.transfers.GetByID(ctx, id)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	t, err = transfer.Cancel(t, s.clock.Now())
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	if err := s.transfers.Save(ctx, t); err != nil {
+		return transfer.Transfer{}, err
+	}
+
+	return t, nil
+}
+
+// Return checks a loan in at branch. A copy returned away from its home
+// branch goes straight into transit back home, in the same copy update as
+// the return; the returned transfer is zero when no transit was needed. An
+// empty branch means the home branch.
+func (s TransferService) Return(ctx context.Context, loanID, branch string) (loan.Loan, transfer.Transfer, error) {
+	at := ""
+	if strings.TrimSpace(branch) != "" {
+		code, err := s.branchCode(ctx, branch)
+		if err != nil {
+			return loan.Loan{}, transfer.Transfer{}, err
+		}
+		at = code
+	}
+
+	var t transfer.Transfer
+	returned, err := s.loans.checkIn(ctx, loanID, func(c copy.Copy, now time.Time) copy.Copy {
+		c.Status = copy.StatusAvailable
+		if at == "" || c.Branch == "" || at == c.Branch {
+			c.CurrentBranch = ""
+			return c
+		}
+
+		t = transfer.Transfer{
+			ID:          s.idGen.NewID(),
+			CopyID:      c.ID,
+			From:        at,
+			To:          c.Branch,
+			Status:      transfer.StatusInTransit,
+			Return:      true,
+			RequestedAt: now,
+			ShippedAt:   &now,
+		}
+		c.Status = copy.StatusInTransit
+		c.CurrentBranch = at
+		return c
+	})
+	if err != nil {
+		return loan.Loan{}, transfer.Transfer{}, err
+	}
+
+	if t.ID == "" {
+		return returned, transfer.Transfer{}, nil
+	}
+	if err := s.transfers.Save(ctx, t); err != nil {
+		return returned, transfer.Transfer{}, err
+	}
+	return returned, t, nil
+}
+
+// List returns transfers newest first. With open set, received and
+// cancelled transfers are left out.
+func (s TransferService) List(ctx context.Context, open bool) ([]transfer.Transfer, error) {
+	items, err := s.transfers.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := items[:0]
+	for _, t := range items {
+		if !open || t.IsOpen() {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].RequestedAt.Equal(out[j].RequestedAt) {
+			return out[i].RequestedAt.After(out[j].RequestedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+
+	return out, nil
+}
+
+func (s TransferService) branchCode(ctx context.Context, code string) (string, error) {
+	b, err := s.branches.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if errors.Is(err, shared.ErrNotFound) {
+		return "", shared.Invalid(fmt.Errorf("unknown branch %q", code))
+	}
+	if err != nil {
+		return "", err
+	}
+	return b.Code, nil
+}
+
+func (s TransferService) openFor(ctx context.Context, copyID string) (bool, error) {
+	items, err := s.transfers.List(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range items {
+		if t.CopyID == copyID && t.IsOpen() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s TransferService) moveCopy(ctx context.Context, c copy.Copy, status copy.Status, current string, now time.Time) error {
+	from := c.Status
+	c.Status = status
+	c.CurrentBranch = current
+	c.UpdatedAt = now
+	if err := s.copies.Save(ctx, c); err != nil {
+		return err
+	}
+
+	if from == status {
+		return nil
+	}
+	return publish(ctx, s.events, event.CopyStatusChanged{CopyID: c.ID, BookID: c.BookID, From: from, To: status, At: now})
+}
@@ -0,0 +1,248 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/branch"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
+)
+
+type transferFixture struct {
+	svc    usecase.TransferService
+	copies *copyRepo
+	loans  usecase.LoanService
+	events *recordingPublisher
+}
+
+func newTransferFixture(t *testing.T) transferFixture {
+	t.Helper()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := stubClock{now: now}
+	idGen := &seqIDGen{}
+	events := &recordingPublisher{}
+	copies := &copyRepo{copies: map[string]copy.Copy{
+		"c-1": {ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable, Branch: "MAIN"},
+		"c-2": {ID: "c-2", BookID: "b-1", Status: copy.StatusAvailable, Branch: "MAIN"},
+	}}
+	members := &memberRepo{members: map[string]member.Member{
+		"m-1": {ID: "m-1", Name: "Joe", JoinedAt: now, Status: member.StatusActive},
+	}}
+	loans := usecase.NewLoanService(
+		&loanRepo{loans: map[string]loan.Loan{}},
+		copies,
+		members,
+		idGen,
+		clock,
+		loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+		events,
+	)
+
+	branches := &branchRepo{branches: map[string]branch.Branch{}}
+	branchSvc := usecase.NewBranchService(branches, clock)
+	for _, in := range []dto.AddBranchInput{{Code: "main", Name: "Main Library"}, {Code: "East", Name: "East Side"}} {
+		if _, err := branchSvc.Add(context.Background(), in); err != nil {
+			t.Fatalf("add branch %s: %v", in.Code, err)
+		}
+	}
+
+	return transferFixture{
+		svc:    usecase.NewTransferService(&transferRepo{transfers: map[string]transfer.Transfer{}}, branches, copies, loans, idGen, clock, events),
+		copies: copies,
+		loans:  loans,
+		events: events,
+	}
+}
+
+func TestTransferServiceWorkflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	f := newTransferFixture(t)
+
+	if _, err := f.svc.Request(ctx, dto.RequestTransferInput{CopyID: "c-1", To: "north"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Fatalf("expected invalid input for an unknown branch, got %v", err)
+	}
+	if _, err := f.svc.Request(ctx, dto.RequestTransferInput{CopyID: "c-1", To: "main"}); !errors.Is(err, shared.ErrAlreadyAtBranch) {
+		t.Fatalf("expected already at branch, got %v", err)
+	}
+
+	tr, err := f.svc.Request(ctx, dto.RequestTransferInput{CopyID: "c-1", To: "east"})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if tr.From != "MAIN" || tr.To != "EAST" || tr.Status != transfer.StatusRequested {
+		t.Fatalf("unexpected transfer %+v", tr)
+	}
+	if _, err := f.svc.Request(ctx, dto.RequestTransferInput{CopyID: "c-1", To: "east"}); !errors.Is(err, shared.ErrTransferOpen) {
+		t.Fatalf("expected open transfer error, got %v", err)
+	}
+	if _, err := f.svc.Receive(ctx, tr.ID); !errors.Is(err, shared.ErrTransferState) {
+		t.Fatalf("expected receive before ship to fail, got %v", err)
+	}
+
+	if _, err := f.svc.Ship(ctx, tr.ID); err != nil {
+		t.Fatalf("ship: %v", err)
+	}
+	if c, _ := f.copies.GetByID(ctx, "c-1"); c.Status != copy.StatusInTransit {
+		t.Fatalf("expected copy in transit got %s", c.Status)
+	}
+	if _, err := f.svc.Cancel(ctx, tr.ID); !errors.Is(err, shared.ErrTransferState) {
+		t.Fatalf("expected cancel after ship to fail, got %v", err)
+	}
+
+	tr, err = f.svc.Receive(ctx, tr.ID)
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	c, _ := f.copies.GetByID(ctx, "c-1")
+	if tr.Status != transfer.StatusReceived || c.Status != copy.StatusAvailable || c.Branch != "MAIN" || c.At() != "EAST" {
+		t.Fatalf("unexpected state after receive: %+v %+v", tr, c)
+	}
+
+	want := []event.Type{event.TypeCopyStatusChanged, event.TypeCopyStatusChanged}
+	if got := f.events.types(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v got %v", want, got)
+	}
+
+	open, err := f.svc.List(ctx, true)
+	if err != nil || len(open) != 0 {
+		t.Fatalf("expected no open transfers got %v %v", open, err)
+	}
+}
+
+func TestTransferServiceReturnAtOtherBranch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	f := newTransferFixture(t)
+
+	tests := []struct {
+		name       string
+		copyID     string
+		branch     string
+		wantStatus copy.Status
+		wantAt     string
+		transit    bool
+	}{
+		{name: "home branch", copyID: "c-1", branch: "main", wantStatus: copy.StatusAvailable, wantAt: "MAIN"},
+		{name: "no branch", copyID: "c-2", wantStatus: copy.StatusAvailable, wantAt: "MAIN"},
+		{name: "other branch", copyID: "c-1", branch: "east", wantStatus: copy.StatusInTransit, wantAt: "EAST", transit: true},
+	}
+
+	for _, tt := range tests {
+		l, err := f.loans.Issue(ctx, dto.IssueLoanInput{CopyID: tt.copyID, MemberID: "m-1"})
+		if err != nil {
+			t.Fatalf("%s: issue: %v", tt.name, err)
+		}
+
+		seen := len(f.events.events)
+		returned, tr, err := f.svc.Return(ctx, l.ID, tt.branch)
+		if err != nil {
+			t.Fatalf("%s: return: %v", tt.name, err)
+		}
+		published := f.events.events[seen:]
+		if len(published) != 2 {
+			t.Fatalf("%s: expected a return and one copy status change got %v", tt.name, published)
+		}
+		if changed, ok := published[1].(event.CopyStatusChanged); !ok || changed.From != copy.StatusLoaned || changed.To != tt.wantStatus {
+			t.Fatalf("%s: unexpected copy status change %+v", tt.name, published[1])
+		}
+		if returned.Status != loan.StatusReturned {
+			t.Fatalf("%s: expected returned loan got %s", tt.name, returned.Status)
+		}
+
+		c, _ := f.copies.GetByID(ctx, tt.copyID)
+		if c.Status != tt.wantStatus || c.At() != tt.wantAt {
+			t.Fatalf("%s: expected copy %s at %s got %s at %s", tt.name, tt.wantStatus, tt.wantAt, c.Status, c.At())
+		}
+		if (tr.ID != "") != tt.transit {
+			t.Fatalf("%s: unexpected transfer %+v", tt.name, tr)
+		}
+		if tt.transit && (!tr.Return || tr.From != "EAST" || tr.To != "MAIN" || tr.Status != transfer.StatusInTransit) {
+			t.Fatalf("%s: unexpected return transfer %+v", tt.name, tr)
+		}
+	}
+
+	open, _ := f.svc.List(ctx, true)
+	if len(open) != 1 {
+		t.Fatalf("expected one open transfer got %v", open)
+	}
+
+	lost, _ := f.copies.GetByID(ctx, "c-1")
+	lost.Status = copy.StatusLost
+	_ = f.copies.Save(ctx, lost)
+	if _, err := f.svc.Receive(ctx, open[0].ID); !errors.Is(err, shared.ErrTransferState) {
+		t.Fatalf("expected a copy no longer in transit to be refused, got %v", err)
+	}
+	lost.Status = copy.StatusInTransit
+	_ = f.copies.Save(ctx, lost)
+
+	if _, err := f.svc.Receive(ctx, open[0].ID); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if c, _ := f.copies.GetByID(ctx, "c-1"); c.Status != copy.StatusAvailable || c.CurrentBranch != "" {
+		t.Fatalf("expected copy back home got %+v", c)
+	}
+}
+
+type branchRepo struct {
+	branches map[string]branch.Branch
+}
+
+func (r *branchRepo) Save(_ context.Context, b branch.Branch) error {
+	r.branches[b.Code] = b
+	return nil
+}
+
+func (r *branchRepo) GetByCode(_ context.Context, code string) (branch.Branch, error) {
+	b, ok := r.branches[code]
+	if !ok {
+		return branch.Branch{}, shared.ErrNotFound
+	}
+	return b, nil
+}
+
+func (r *branchRepo) List(_ context.Context) ([]branch.Branch, error) {
+	out := make([]branch.Branch, 0, len(r.branches))
+	for _, b := range r.branches {
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+type transferRepo struct {
+	transfers map[string]transfer.Transfer
+}
+
+func (r *transferRepo) Save(_ context.Context, t transfer.Transfer) error {
+	r.transfers[t.ID] = t
+	return nil
+}
+
+func (r *transferRepo) GetByID(_ context.Context, id string) (transfer.Transfer, error) {
+	t, ok := r.transfers[id]
+	if !ok {
+		return transfer.Transfer{}, shared.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *transferRepo) List(_ context.Context) ([]transfer.Transfer, error) {
+	out := make([]transfer.Transfer, 0, len(r.transfers))
+	for _, t := range r.transfers {
+		out = append(out, t)
+	}
+	return out, nil
+}
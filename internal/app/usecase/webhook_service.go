@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
+)
+
+type WebhookService struct {
+	webhooks ports.WebhookRepository
+	sender   ports.WebhookSender
+	idGen    ports.IDGenerator
+	clock    ports.Clock
+}
+
+func NewWebhookService(webhooks ports.WebhookRepository, sender ports.WebhookSender, idGen ports.IDGenerator, clock ports.Clock) WebhookService {
+	return WebhookService{webhooks: webhooks, sender: sender, idGen: idGen, clock: clock}
+}
+
+func (s WebhookService) Register(ctx context.Context, input dto.RegisterWebhookInput) (webhook.Endpoint, error) {
+	e := webhook.Endpoint{
+		ID:        s.idGen.NewID(),
+		URL:       strings.TrimSpace(input.URL),
+		Secret:    input.Secret,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if len(input.Events) == 0 {
+		e.Events = append(e.Events, webhook.Supported...)
+	}
+	for _, t := range input.Events {
+		e.Events = append(e.Events, event.Type(strings.TrimSpace(t)))
+	}
+
+	if err := e.Validate(); err != nil {
+		return webhook.Endpoint{}, shared.Invalid(err)
+	}
+
+	if err := s.webhooks.SaveEndpoint(ctx, e); err != nil {
+		return webhook.Endpoint{}, err
+	}
+
+	return e, nil
+}
+
+func (s WebhookService) Remove(ctx context.Context, id string) error {
+	return s.webhooks.DeleteEndpoint(ctx, id)
+}
+
+func (s WebhookService) List(ctx context.Context) ([]webhook.Endpoint, error) {
+	endpoints, err := s.webhooks.ListEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+	return endpoints, nil
+}
+
+// Deliveries returns the delivery log, newest first.
+func (s WebhookService) Deliveries(ctx context.Context, limit int) ([]webhook.Delivery, error) {
+	deliveries, err := s.webhooks.ListDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// Enqueue records a pending delivery of the event for every endpoint that
+// subscribed to its type. Nothing is sent until DeliverDue runs.
+func (s WebhookService) Enqueue(ctx context.Context, eventID string, e event.Event) error {
+	endpoints, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, ep := range endpoints {
+		if !ep.Wants(e.Type()) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = webhookPayload(eventID, e); err != nil {
+				return err
+			}
+		}
+
+		now := s.clock.Now()
+		d := webhook.Delivery{
+			ID:            s.idGen.NewID(),
+			EndpointID:    ep.ID,
+			EventID:       eventID,
+			EventType:     e.Type(),
+			Payload:       payload,
+			Status:        webhook.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := s.webhooks.SaveDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverDue makes one attempt for every pending delivery whose retry time
+// has come and returns how many were attempted.
+func (s WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhooks.ListDeliveries(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	due := make([]webhook.Delivery, 0)
+	for _, d := range deliveries {
+		if d.Due(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	for i, d := range due {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if err := s.attempt(ctx, d); err != nil {
+			return i, err
+		}
+	}
+
+	return len(due), nil
+}
+
+func (s WebhookService) Retry(ctx context.Context, deliveryID string) (webhook.Delivery, error) {
+	d, err := s.webhooks.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	d, err = webhook.Retry(d, s.clock.Now())
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	if err := s.webhooks.SaveDelivery(ctx, d); err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	return d, nil
+}
+
+func (s WebhookService) attempt(ctx context.Context, d webhook.Delivery) error {
+	ep, err := s.webhooks.GetEndpoint(ctx, d.EndpointID)
+	if err != nil {
+		if !errors.Is(err, shared.ErrNotFound) {
+			return err
+		}
+		d.Status = webhook.DeliveryFailed
+		d.LastError = "endpoint removed"
+		return s.webhooks.SaveDelivery(ctx, d)
+	}
+
+	sentAt := s.clock.Now()
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", "LMS-bit-webhooks")
+	header.Set("X-LMS-Event", string(d.EventType))
+	header.Set("X-LMS-Event-ID", d.EventID)
+	header.Set("X-LMS-Delivery", d.ID)
+	header.Set("X-LMS-Timestamp", strconv.FormatInt(sentAt.Unix(), 10))
+	header.Set("X-LMS-Signature", webhook.Sign(ep.Secret, sentAt.Unix(), d.Payload))
+
+	code, err := s.sender.Send(ctx, ep.URL, header, d.Payload)
+	now := s.clock.Now()
+	switch {
+	case err != nil:
+		d = webhook.RecordFailure(d, 0, err.Error(), now)
+	case code < 200 || code > 299:
+		d = webhook.RecordFailure(d, code, fmt.Sprintf("unexpected status %d", code), now)
+	default:
+		d = webhook.RecordSuccess(d, code, now)
+	}
+
+	return s.webhooks.SaveDelivery(ctx, d)
+}
+
+type webhookBody struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type webhookLoan struct {
+	LoanID       string     `json:"loan_id"`
+	CopyID       string     `json:"copy_id"`
+	MemberID     string     `json:"member_id"`
+	IssuedAt     time.Time  `json:"issued_at"`
+	DueAt        time.Time  `json:"due_at"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty"`
+	RenewalCount int        `json:"renewal_count"`
+}
+
+type webhookMemberStatus struct {
+	MemberID string `json:"member_id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+type webhookMerge struct {
+	From  string `json:"from"`
+	Into  string `json:"into"`
+	Loans int    `json:"loans"`
+	Holds int    `json:"holds"`
+}
+
+type webhookOverride struct {
+	LoanID   string    `json:"loan_id"`
+	MemberID string    `json:"member_id"`
+	Rules    []string  `json:"rules"`
+	Reason   string    `json:"reason"`
+	By       string    `json:"by"`
+	At       time.Time `json:"at"`
+}
+
+func webhookPayload(eventID string, e event.Event) ([]byte, error) {
+	body := webhookBody{ID: eventID, Type: string(e.Type()), OccurredAt: e.OccurredAt()}
+
+	switch ev := e.(type) {
+	case event.LoanIssued:
+		body.Data = newWebhookLoan(ev.Loan)
+	case event.LoanRenewed:
+		body.Data = newWebhookLoan(ev.Loan)
+	case event.LoanReturned:
+		body.Data = newWebhookLoan(ev.Loan)
+	case event.LoanOverridden:
+		body.Data = newWebhookOverride(ev)
+	case event.MemberStatusChanged:
+		body.Data = webhookMemberStatus{MemberID: ev.MemberID, From: string(ev.From), To: string(ev.To)}
+	case event.MemberMerged:
+		body.Data = webhookMerge{From: ev.From, Into: ev.Into, Loans: ev.Loans, Holds: ev.Holds}
+	default:
+		return nil, fmt.Errorf("event %s cannot be sent to webhooks", e.Type())
+	}
+
+	return json.Marshal(body)
+}
+
+func newWebhookLoan(l loan.Loan) webhookLoan {
+	return webhookLoan{
+		LoanID:       l.ID,
+		CopyID:       l.CopyID,
+		MemberID:     l.MemberID,
+		IssuedAt:     l.IssuedAt,
+		DueAt:        l.DueAt,
+		ReturnedAt:   l.ReturnedAt,
+		RenewalCount: l.RenewalCount,
+	}
+}
+
+func newWebhookOverride(e event.LoanOverridden) webhookOverride {
+	rules := make([]string, 0, len(e.Override.Rules))
+	for _, r := range e.Override.Rules {
+		rules = append(rules, string(r))
+	}
+
+	return webhookOverride{
+		LoanID:   e.LoanID,
+		MemberID: e.MemberID,
+		Rules:    rules,
+		Reason:   e.Override.Reason,
+		By:       e.Override.By,
+		At:       e.Override.At,
+	}
+}
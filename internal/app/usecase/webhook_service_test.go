@@ -0,0 +1,396 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
+	webhookhttp "github.com/mibienpanjoe/LMS-bit/internal/infra/webhook"
+)
+
+const testSecret = "0123456789abcdef"
+
+func TestWebhookServiceRegisterValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input dto.RegisterWebhookInput
+	}{
+		{name: "relative url", input: dto.RegisterWebhookInput{URL: "/hook", Secret: testSecret}},
+		{name: "unsupported scheme", input: dto.RegisterWebhookInput{URL: "ftp://campus/hook", Secret: testSecret}},
+		{name: "short secret", input: dto.RegisterWebhookInput{URL: "https://campus/hook", Secret: "short"}},
+		{name: "unknown event", input: dto.RegisterWebhookInput{URL: "https://campus/hook", Secret: testSecret, Events: []string{"book.archived"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := usecase.NewWebhookService(newWebhookRepo(), nil, &seqIDGen{}, stubClock{now: time.Now()})
+			if _, err := svc.Register(context.Background(), tt.input); !errors.Is(err, shared.ErrInvalidInput) {
+				t.Fatalf("expected invalid input, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceDeliversSignedPayloads(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recv := newReceiver(t)
+	clock := &manualClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	repo := newWebhookRepo()
+	svc := usecase.NewWebhookService(repo, webhookhttp.NewClient(time.Second), &seqIDGen{}, clock)
+
+	all, err := svc.Register(ctx, dto.RegisterWebhookInput{URL: recv.URL + "/all", Secret: testSecret})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if len(all.Events) != len(webhook.Supported) {
+		t.Fatalf("expected every supported event by default, got %v", all.Events)
+	}
+	if _, err := svc.Register(ctx, dto.RegisterWebhookInput{
+		URL:    recv.URL + "/returns",
+		Secret: testSecret,
+		Events: []string{"loan.returned"},
+	}); err != nil {
+		t.Fatalf("register filtered: %v", err)
+	}
+
+	issued := event.LoanIssued{
+		Loan: loan.Loan{ID: "l-1", CopyID: "c-1", MemberID: "m-1", IssuedAt: clock.now, DueAt: clock.now.AddDate(0, 0, 14), Status: loan.StatusActive},
+		At:   clock.now,
+	}
+	if err := svc.Enqueue(ctx, "ev-1", issued); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := svc.Enqueue(ctx, "ev-2", event.BookArchived{BookID: "b-1", At: clock.now}); err != nil {
+		t.Fatalf("enqueue unsupported: %v", err)
+	}
+
+	n, err := svc.DeliverDue(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one attempt, got %d (%v)", n, err)
+	}
+
+	got := recv.requests()
+	if len(got) != 1 || got[0].path != "/all" {
+		t.Fatalf("expected a single request to /all, got %+v", got)
+	}
+	req := got[0]
+	if req.header.Get("X-LMS-Event") != "loan.issued" || req.header.Get("X-LMS-Event-ID") != "ev-1" {
+		t.Fatalf("unexpected event headers %v", req.header)
+	}
+	ts, _ := strconv.ParseInt(req.header.Get("X-LMS-Timestamp"), 10, 64)
+	if !webhook.Verify(testSecret, ts, req.body, req.header.Get("X-LMS-Signature")) {
+		t.Fatalf("signature did not verify")
+	}
+
+	var body struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			LoanID   string `json:"loan_id"`
+			MemberID string `json:"member_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if body.ID != "ev-1" || body.Type != "loan.issued" || body.Data.LoanID != "l-1" || body.Data.MemberID != "m-1" {
+		t.Fatalf("unexpected payload %s", req.body)
+	}
+
+	log, _ := svc.Deliveries(ctx, 0)
+	if len(log) != 1 || log[0].Status != webhook.DeliveryDelivered || log[0].LastCode != http.StatusOK {
+		t.Fatalf("unexpected delivery log %+v", log)
+	}
+}
+
+func TestWebhookServiceRetriesWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recv := newReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	clock := &manualClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	svc := usecase.NewWebhookService(newWebhookRepo(), webhookhttp.NewClient(time.Second), &seqIDGen{}, clock)
+
+	if _, err := svc.Register(ctx, dto.RegisterWebhookInput{URL: recv.URL, Secret: testSecret}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	changed := event.MemberStatusChanged{MemberID: "m-1", From: "active", To: "blocked", At: clock.now}
+	if err := svc.Enqueue(ctx, "ev-1", changed); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	steps := []struct {
+		advance  time.Duration
+		attempts int
+		status   webhook.DeliveryStatus
+	}{
+		{advance: 0, attempts: 1, status: webhook.DeliveryPending},
+		{advance: webhook.Backoff[0] - time.Second, attempts: 1, status: webhook.DeliveryPending},
+		{advance: time.Second, attempts: 2, status: webhook.DeliveryPending},
+		{advance: webhook.Backoff[1], attempts: 3, status: webhook.DeliveryDelivered},
+	}
+	for i, step := range steps {
+		clock.now = clock.now.Add(step.advance)
+		if _, err := svc.DeliverDue(ctx); err != nil {
+			t.Fatalf("step %d: deliver: %v", i, err)
+		}
+		log, _ := svc.Deliveries(ctx, 1)
+		if log[0].Attempts != step.attempts || log[0].Status != step.status {
+			t.Fatalf("step %d: expected %d attempts and %s, got %+v", i, step.attempts, step.status, log[0])
+		}
+	}
+
+	if got := len(recv.requests()); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestWebhookServiceGivesUpAndRetriesOnDemand(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := &manualClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	svc := usecase.NewWebhookService(newWebhookRepo(), failingSender{}, &seqIDGen{}, clock)
+
+	if _, err := svc.Register(ctx, dto.RegisterWebhookInput{URL: "http://127.0.0.1:1/hook", Secret: testSecret}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := svc.Enqueue(ctx, "ev-1", event.LoanReturned{Loan: loan.Loan{ID: "l-1"}, At: clock.now}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	for i := 0; i < webhook.MaxAttempts(); i++ {
+		if _, err := svc.DeliverDue(ctx); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		clock.now = clock.now.Add(7 * time.Hour)
+	}
+
+	log, _ := svc.Deliveries(ctx, 1)
+	d := log[0]
+	if d.Status != webhook.DeliveryFailed || d.Attempts != webhook.MaxAttempts() || d.LastError != "connection refused" {
+		t.Fatalf("expected failed delivery, got %+v", d)
+	}
+	if n, _ := svc.DeliverDue(ctx); n != 0 {
+		t.Fatalf("failed deliveries must not be attempted, got %d", n)
+	}
+
+	retried, err := svc.Retry(ctx, d.ID)
+	if err != nil || retried.Status != webhook.DeliveryPending || retried.Attempts != 0 {
+		t.Fatalf("unexpected retry result %+v (%v)", retried, err)
+	}
+	if _, err := svc.Retry(ctx, d.ID); !errors.Is(err, shared.ErrDeliveryNotFailed) {
+		t.Fatalf("expected %v got %v", shared.ErrDeliveryNotFailed, err)
+	}
+}
+
+func TestWebhookServiceQueuesEverySupportedEvent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	samples := map[event.Type]event.Event{
+		event.TypeLoanIssued:          event.LoanIssued{Loan: loan.Loan{ID: "l-1"}, At: now},
+		event.TypeLoanRenewed:         event.LoanRenewed{Loan: loan.Loan{ID: "l-1"}, At: now},
+		event.TypeLoanReturned:        event.LoanReturned{Loan: loan.Loan{ID: "l-1"}, At: now},
+		event.TypeLoanOverridden:      event.LoanOverridden{LoanID: "l-1", Override: loan.Override{Rules: []loan.Rule{loan.RuleLoanLimit}}, At: now},
+		event.TypeMemberStatusChanged: event.MemberStatusChanged{MemberID: "m-1", From: "active", To: "inactive", At: now},
+		event.TypeMemberMerged:        event.MemberMerged{From: "m-2", Into: "m-1", Loans: 1, At: now},
+	}
+
+	for _, typ := range webhook.Supported {
+		t.Run(string(typ), func(t *testing.T) {
+			t.Parallel()
+
+			e, ok := samples[typ]
+			if !ok {
+				t.Fatalf("no sample event for supported type %s", typ)
+			}
+
+			ctx := context.Background()
+			svc := usecase.NewWebhookService(newWebhookRepo(), nil, &seqIDGen{}, stubClock{now: now})
+			if _, err := svc.Register(ctx, dto.RegisterWebhookInput{URL: "https://campus/hook", Secret: testSecret}); err != nil {
+				t.Fatalf("register: %v", err)
+			}
+			if err := svc.Enqueue(ctx, "ev-1", e); err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+			if log, _ := svc.Deliveries(ctx, 0); len(log) != 1 || log[0].EventType != typ {
+				t.Fatalf("expected one %s delivery, got %+v", typ, log)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceQueuesOverrides(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo := newWebhookRepo()
+	svc := usecase.NewWebhookService(repo, nil, &seqIDGen{}, stubClock{now: now})
+
+	if _, err := svc.Register(ctx, dto.RegisterWebhookInput{URL: "https://campus/hook", Secret: testSecret}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	overridden := event.LoanOverridden{
+		LoanID:   "l-1",
+		MemberID: "m-1",
+		Override: loan.Override{Rules: []loan.Rule{loan.RuleLoanLimit}, Reason: "exam week", By: "ana", At: now},
+		At:       now,
+	}
+	if err := svc.Enqueue(ctx, "ev-1", overridden); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	log, _ := svc.Deliveries(ctx, 0)
+	if len(log) != 1 {
+		t.Fatalf("expected one delivery, got %+v", log)
+	}
+	var body struct {
+		Type string `json:"type"`
+		Data struct {
+			LoanID string   `json:"loan_id"`
+			Rules  []string `json:"rules"`
+			Reason string   `json:"reason"`
+			By     string   `json:"by"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(log[0].Payload, &body); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if body.Type != "loan.overridden" || body.Data.LoanID != "l-1" || len(body.Data.Rules) != 1 ||
+		body.Data.Rules[0] != "loan_limit" || body.Data.Reason != "exam week" || body.Data.By != "ana" {
+		t.Fatalf("unexpected payload %s", log[0].Payload)
+	}
+}
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+type failingSender struct{}
+
+func (failingSender) Send(context.Context, string, http.Header, []byte) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+type receivedRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	got      []receivedRequest
+}
+
+// newReceiver answers with the given statuses in order and 200 afterwards.
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		r.got = append(r.got, receivedRequest{path: req.URL.Path, header: req.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) requests() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.got...)
+}
+
+type webhookRepo struct {
+	endpoints  map[string]webhook.Endpoint
+	deliveries map[string]webhook.Delivery
+}
+
+func newWebhookRepo() *webhookRepo {
+	return &webhookRepo{endpoints: map[string]webhook.Endpoint{}, deliveries: map[string]webhook.Delivery{}}
+}
+
+func (r *webhookRepo) SaveEndpoint(_ context.Context, e webhook.Endpoint) error {
+	r.endpoints[e.ID] = e
+	return nil
+}
+
+func (r *webhookRepo) GetEndpoint(_ context.Context, id string) (webhook.Endpoint, error) {
+	e, ok := r.endpoints[id]
+	if !ok {
+		return webhook.Endpoint{}, shared.ErrNotFound
+	}
+	return e, nil
+}
+
+func (r *webhookRepo) DeleteEndpoint(_ context.Context, id string) error {
+	delete(r.endpoints, id)
+	return nil
+}
+
+func (r *webhookRepo) ListEndpoints(context.Context) ([]webhook.Endpoint, error) {
+	out := make([]webhook.Endpoint, 0, len(r.endpoints))
+	for _, e := range r.endpoints {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *webhookRepo) SaveDelivery(_ context.Context, d webhook.Delivery) error {
+	r.deliveries[d.ID] = d
+	return nil
+}
+
+func (r *webhookRepo) GetDelivery(_ context.Context, id string) (webhook.Delivery, error) {
+	d, ok := r.deliveries[id]
+	if !ok {
+		return webhook.Delivery{}, shared.ErrNotFound
+	}
+	return d, nil
+}
+
+func (r *webhookRepo) ListDeliveries(context.Context) ([]webhook.Delivery, error) {
+	out := make([]webhook.Delivery, 0, len(r.deliveries))
+	for _, d := range r.deliveries {
+		out = append(out, d)
+	}
+	return out, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -12,6 +13,32 @@ type Config struct {
 	LoanDays        int
 	MaxLoansPerUser int
 	MaxLoanRenewals int
+	LoanPeriods     string
+	MinLoanLength   string
+	MaxLoanLength   string
+	UniqueISBN      bool
+	HTTPAddr        string
+	OPACAddr        string
+	OPACSecret      string
+	Branch          string
+	AutoBarcode     bool
+	BarcodePrefix   string
+	BarcodeDigits   int
+	BarcodeStart    int
+	LabelLayout     string
+	DocumentFormat  string
+	MembershipDays  int
+	ExpiringDays    int
+	CardPrefix      string
+	CardDigits      int
+	CardStart       int
+	UniqueCard      bool
+	UniqueEmail     bool
+	PhoneCountry    string
+	Operator        string
+	Supervisors     []string
+	BlockOverdue    int
+	BlockAfterDays  int
 }
 
 func Load() Config {
@@ -22,6 +49,32 @@ func Load() Config {
 		LoanDays:        getEnvInt("LMS_LOAN_DAYS", 14),
 		MaxLoansPerUser: getEnvInt("LMS_MAX_LOANS_PER_MEMBER", 3),
 		MaxLoanRenewals: getEnvInt("LMS_MAX_LOAN_RENEWALS", 1),
+		LoanPeriods:     getEnv("LMS_LOAN_PERIODS", "reserve=2h,week=7d,semester=120d"),
+		MinLoanLength:   getEnv("LMS_LOAN_MIN", "1h"),
+		MaxLoanLength:   getEnv("LMS_LOAN_MAX", "180d"),
+		UniqueISBN:      getEnvBool("LMS_ISBN_UNIQUE", true),
+		HTTPAddr:        getEnv("LMS_HTTP_ADDR", "127.0.0.1:8080"),
+		OPACAddr:        getEnv("LMS_OPAC_ADDR", "127.0.0.1:8081"),
+		OPACSecret:      getEnv("LMS_OPAC_SECRET", ""),
+		Branch:          getEnv("LMS_BRANCH", ""),
+		AutoBarcode:     getEnvBool("LMS_BARCODE_AUTO", true),
+		BarcodePrefix:   getEnv("LMS_BARCODE_PREFIX", "C"),
+		BarcodeDigits:   getEnvInt("LMS_BARCODE_DIGITS", 6),
+		BarcodeStart:    getEnvInt("LMS_BARCODE_START", 1),
+		LabelLayout:     getEnv("LMS_LABEL_LAYOUT", "avery-5160"),
+		DocumentFormat:  getEnv("LMS_DOCUMENT_FORMAT", "pdf"),
+		MembershipDays:  getEnvInt("LMS_MEMBERSHIP_DAYS", 365),
+		ExpiringDays:    getEnvInt("LMS_MEMBERSHIP_NOTICE_DAYS", 30),
+		CardPrefix:      getEnv("LMS_CARD_PREFIX", "P"),
+		CardDigits:      getEnvInt("LMS_CARD_DIGITS", 8),
+		CardStart:       getEnvInt("LMS_CARD_START", 1),
+		UniqueCard:      getEnvBool("LMS_CARD_UNIQUE", true),
+		UniqueEmail:     getEnvBool("LMS_MEMBER_EMAIL_UNIQUE", true),
+		PhoneCountry:    getEnv("LMS_PHONE_COUNTRY_CODE", ""),
+		Operator:        getEnv("LMS_OPERATOR", getEnv("USER", "staff")),
+		Supervisors:     getEnvList("LMS_SUPERVISORS"),
+		BlockOverdue:    getEnvInt("LMS_BLOCK_OVERDUE_ITEMS", 0),
+		BlockAfterDays:  getEnvInt("LMS_BLOCK_OVERDUE_DAYS", 30),
 	}
 }
 
@@ -47,3 +100,29 @@ func getEnvInt(key string, fallback int) int {
 
 	return n
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+
+	return b
+}
+
+// getEnvList splits a comma-separated variable, dropping blank entries.
+func getEnvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"time"
 )
 
+// isbnPattern only checks the shape of a stored ISBN. Checksums are enforced
+// where ISBNs are entered, so records saved before that still load.
 var isbnPattern = regexp.MustCompile(`^(?:\d{10}|\d{13})$`)
 
 type Status string
@@ -24,6 +27,7 @@ type Book struct {
 	Publisher string
 	Year      int
 	Status    Status
+	UpdatedAt time.Time
 }
 
 func (b Book) Validate() error {
@@ -40,7 +44,7 @@ func (b Book) Validate() error {
 	}
 
 	if b.ISBN != "" && !isbnPattern.MatchString(strings.TrimSpace(b.ISBN)) {
-		return errors.New("isbn must be 10 or 13 digits")
+		return ErrISBNLength
 	}
 
 	if b.Status == "" {
@@ -50,6 +54,10 @@ func (b Book) Validate() error {
 	return nil
 }
 
+type Policy struct {
+	UniqueISBN bool
+}
+
 func (b Book) CanCirculate() bool {
 	return b.Status == StatusActive
 }
@@ -0,0 +1,134 @@
+package book
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrISBNLength   = errors.New("isbn must be 10 or 13 digits")
+	ErrISBNChecksum = errors.New("isbn checksum is invalid")
+	ErrISBNPrefix   = errors.New("isbn-13 has no isbn-10 equivalent")
+)
+
+// NormalizeISBN accepts an ISBN-10 or ISBN-13, with or without hyphens and
+// spaces, and returns its canonical ISBN-13 form.
+func NormalizeISBN(raw string) (string, error) {
+	s := compactISBN(raw)
+
+	switch len(s) {
+	case 10:
+		if !validISBN10(s) {
+			return "", ErrISBNChecksum
+		}
+		return isbn10To13(s), nil
+	case 13:
+		if !validISBN13(s) {
+			return "", ErrISBNChecksum
+		}
+		return s, nil
+	default:
+		return "", ErrISBNLength
+	}
+}
+
+func ISBN10To13(raw string) (string, error) {
+	s := compactISBN(raw)
+	if len(s) != 10 {
+		return "", ErrISBNLength
+	}
+	if !validISBN10(s) {
+		return "", ErrISBNChecksum
+	}
+
+	return isbn10To13(s), nil
+}
+
+func ISBN13To10(raw string) (string, error) {
+	s := compactISBN(raw)
+	if len(s) != 13 {
+		return "", ErrISBNLength
+	}
+	if !validISBN13(s) {
+		return "", ErrISBNChecksum
+	}
+	if !strings.HasPrefix(s, "978") {
+		return "", ErrISBNPrefix
+	}
+
+	body := s[3:12]
+	return body + isbn10CheckDigit(body), nil
+}
+
+func compactISBN(raw string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(raw) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isbn10To13(s string) string {
+	body := "978" + s[:9]
+	return body + isbn13CheckDigit(body)
+}
+
+func validISBN10(s string) bool {
+	for i, r := range s {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		if r == 'X' && i == 9 {
+			continue
+		}
+		return false
+	}
+
+	return isbn10CheckDigit(s[:9]) == s[9:]
+}
+
+func validISBN13(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return false
+	}
+
+	return isbn13CheckDigit(s[:12]) == s[12:]
+}
+
+func isbn10CheckDigit(body string) string {
+	sum := 0
+	for i, r := range body {
+		sum += int(r-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return string(rune('0' + check))
+}
+
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i, r := range body {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += int(r-'0') * w
+	}
+
+	return string(rune('0' + (10-sum%10)%10))
+}
@@ -0,0 +1,56 @@
+package book_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "9780134190440", want: "9780134190440"},
+		{in: "978-0-13-419044-0", want: "9780134190440"},
+		{in: "0-306-40615-2", want: "9780306406157"},
+		{in: "080442957x", want: "9780804429573"},
+		{in: "979-10-90636-07-1", want: "9791090636071"},
+		{in: "9780134190441", err: book.ErrISBNChecksum},
+		{in: "0306406153", err: book.ErrISBNChecksum},
+		{in: "03064X6152", err: book.ErrISBNChecksum},
+		{in: "12345", err: book.ErrISBNLength},
+	}
+
+	for _, tc := range tests {
+		got, err := book.NormalizeISBN(tc.in)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%q: expected error %v got %v", tc.in, tc.err, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%q: expected %q got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestISBNConversion(t *testing.T) {
+	t.Parallel()
+
+	isbn13, err := book.ISBN10To13("0-8044-2957-X")
+	if err != nil || isbn13 != "9780804429573" {
+		t.Fatalf("unexpected isbn-13 %q %v", isbn13, err)
+	}
+
+	isbn10, err := book.ISBN13To10(isbn13)
+	if err != nil || isbn10 != "080442957X" {
+		t.Fatalf("unexpected isbn-10 %q %v", isbn10, err)
+	}
+
+	if _, err := book.ISBN13To10("9791090636071"); !errors.Is(err, book.ErrISBNPrefix) {
+		t.Fatalf("expected prefix error got %v", err)
+	}
+}
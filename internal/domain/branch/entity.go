@@ -0,0 +1,30 @@
+package branch
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Branch is a library location. Copies refer to it by Code.
+type Branch struct {
+	Code      string
+	Name      string
+	CreatedAt time.Time
+}
+
+func (b Branch) Validate() error {
+	if strings.TrimSpace(b.Code) == "" {
+		return errors.New("branch code is required")
+	}
+
+	if strings.ContainsAny(b.Code, " \t") {
+		return errors.New("branch code cannot contain spaces")
+	}
+
+	if strings.TrimSpace(b.Name) == "" {
+		return errors.New("branch name is required")
+	}
+
+	return nil
+}
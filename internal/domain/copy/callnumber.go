@@ -0,0 +1,117 @@
+package copy
+
+import (
+	"cmp"
+	"regexp"
+	"strings"
+)
+
+var (
+	deweyPattern = regexp.MustCompile(`^(\d{3})(?:\.(\d+))?(.*)$`)
+	lcPattern    = regexp.MustCompile(`^([A-Z]{1,3}) ?(\d{1,4})(?:\.(\d+))?(.*)$`)
+	cutterToken  = regexp.MustCompile(`^[A-Z]\d+$`)
+)
+
+// Schemes sort in this order; a library normally shelves one or the other.
+const (
+	schemeDewey = iota
+	schemeLC
+	schemeOther
+)
+
+type callNumber struct {
+	scheme  int
+	class   string
+	number  string
+	decimal string
+	rest    []string
+}
+
+func parseCallNumber(s string) callNumber {
+	s = strings.ToUpper(strings.Join(strings.Fields(s), " "))
+
+	if m := deweyPattern.FindStringSubmatch(s); m != nil {
+		return callNumber{scheme: schemeDewey, number: m[1], decimal: m[2], rest: callTokens(m[3])}
+	}
+	if m := lcPattern.FindStringSubmatch(s); m != nil {
+		return callNumber{scheme: schemeLC, class: m[1], number: m[2], decimal: m[3], rest: callTokens(m[4])}
+	}
+	return callNumber{scheme: schemeOther, rest: callTokens(s)}
+}
+
+func callTokens(s string) []string {
+	return strings.Fields(strings.NewReplacer(".", " ", ",", " ").Replace(s))
+}
+
+// CompareCallNumbers orders Dewey and Library of Congress call numbers the
+// way they stand on the shelf: class numbers and cutters compare as
+// decimals, so QA76.73 files before QA76.9 and .G63 before .G7, while years
+// and volumes compare as integers. Anything else falls back to token order.
+func CompareCallNumbers(a, b string) int {
+	x, y := parseCallNumber(a), parseCallNumber(b)
+	if x.scheme != y.scheme {
+		return cmp.Compare(x.scheme, y.scheme)
+	}
+
+	if c := strings.Compare(x.class, y.class); c != 0 {
+		return c
+	}
+	if c := compareDigits(x.number, y.number); c != 0 {
+		return c
+	}
+	if c := compareDecimal(x.decimal, y.decimal); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(x.rest) && i < len(y.rest); i++ {
+		if c := compareToken(x.rest[i], y.rest[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(x.rest), len(y.rest))
+}
+
+// compareToken puts bare numbers (years, volumes) before cutters and
+// cutters before other text.
+func compareToken(a, b string) int {
+	ka, kb := tokenKind(a), tokenKind(b)
+	if ka != kb {
+		return cmp.Compare(ka, kb)
+	}
+
+	switch ka {
+	case 0:
+		return compareDigits(a, b)
+	case 1:
+		if a[0] != b[0] {
+			return cmp.Compare(a[0], b[0])
+		}
+		return compareDecimal(a[1:], b[1:])
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func tokenKind(t string) int {
+	switch {
+	case strings.Trim(t, "0123456789") == "":
+		return 0
+	case cutterToken.MatchString(t):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func compareDigits(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func compareDecimal(a, b string) int {
+	a, b = strings.TrimRight(a, "0"), strings.TrimRight(b, "0")
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,67 @@
+package copy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+func TestCompareCallNumbersShelfOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: "dewey",
+			want: []string{
+				"005.1 M37",
+				"005.13 K47",
+				"005.133 D66 2015",
+				"005.133 D66 2016",
+				"005.133 D66 2016 v.2",
+				"005.133 D66 2016 v.10",
+				"005.2 A12",
+				"510 B3",
+				"510.1",
+			},
+		},
+		{
+			name: "library of congress",
+			want: []string{
+				"P 25 .A1",
+				"PS3545.I345 Z46 1990",
+				"QA9.58 .B7",
+				"QA76 .A12",
+				"QA76.73 .G63 2016",
+				"QA76.73.G7",
+				"QA76.9 .D3",
+				"QA760 .C4",
+				"QB1 .A1",
+			},
+		},
+		{
+			name: "schemes and local shelving",
+			want: []string{"823.914 R69", "PR6068.O93", "FIC ROWLING", "REF ATLAS"},
+		},
+	}
+
+	for _, tc := range tests {
+		got := slices.Clone(tc.want)
+		slices.Reverse(got)
+		slices.SortStableFunc(got, copy.CompareCallNumbers)
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: expected %q got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCompareCallNumbersIgnoresCaseAndSpacing(t *testing.T) {
+	t.Parallel()
+
+	if c := copy.CompareCallNumbers("qa76.73 .g63  2016", "QA76.73.G63 2016"); c != 0 {
+		t.Fatalf("expected equal call numbers, got %d", c)
+	}
+}
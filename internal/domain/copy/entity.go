@@ -2,7 +2,9 @@ package copy
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 )
 
 type Status string
@@ -12,6 +14,7 @@ const (
 	StatusLoaned    Status = "loaned"
 	StatusDamaged   Status = "damaged"
 	StatusLost      Status = "lost"
+	StatusInTransit Status = "in_transit"
 )
 
 type Copy struct {
@@ -20,6 +23,11 @@ type Copy struct {
 	Barcode       string
 	Status        Status
 	ConditionNote string
+	Branch        string
+	CurrentBranch string
+	Location      string
+	CallNumber    string
+	UpdatedAt     time.Time
 }
 
 func (c Copy) Validate() error {
@@ -36,7 +44,7 @@ func (c Copy) Validate() error {
 	}
 
 	switch c.Status {
-	case StatusAvailable, StatusLoaned, StatusDamaged, StatusLost:
+	case StatusAvailable, StatusLoaned, StatusDamaged, StatusLost, StatusInTransit:
 		// valid
 	default:
 		return errors.New("copy status is invalid")
@@ -48,3 +56,31 @@ func (c Copy) Validate() error {
 func (c Copy) IsAvailable() bool {
 	return c.Status == StatusAvailable
 }
+
+// At returns the branch the copy is currently at. Branch is the home branch
+// that owns it; CurrentBranch is only set while it is somewhere else.
+func (c Copy) At() string {
+	if c.CurrentBranch != "" {
+		return c.CurrentBranch
+	}
+	return c.Branch
+}
+
+// Policy controls barcodes handed out to copies created without one.
+// Barcodes are Prefix followed by a sequence number zero-padded to Digits.
+type Policy struct {
+	AutoBarcode   bool
+	BarcodePrefix string
+	BarcodeDigits int
+	BarcodeStart  int64
+}
+
+func (p Policy) Barcode(n int64) string {
+	return fmt.Sprintf("%s%0*d", p.BarcodePrefix, p.BarcodeDigits, n)
+}
+
+// SequenceName is the counter the barcodes are drawn from; each prefix has
+// its own.
+func (p Policy) SequenceName() string {
+	return "barcode:" + p.BarcodePrefix
+}
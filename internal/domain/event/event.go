@@ -0,0 +1,163 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+type Type string
+
+const (
+	TypeLoanIssued          Type = "loan.issued"
+	TypeLoanRenewed         Type = "loan.renewed"
+	TypeLoanReturned        Type = "loan.returned"
+	TypeLoanOverridden      Type = "loan.overridden"
+	TypeMemberStatusChanged Type = "member.status_changed"
+	TypeMemberMerged        Type = "member.merged"
+	TypeBookArchived        Type = "book.archived"
+	TypeCopyStatusChanged   Type = "copy.status_changed"
+)
+
+var Types = []Type{
+	TypeLoanIssued,
+	TypeLoanRenewed,
+	TypeLoanReturned,
+	TypeLoanOverridden,
+	TypeMemberStatusChanged,
+	TypeMemberMerged,
+	TypeBookArchived,
+	TypeCopyStatusChanged,
+}
+
+type Event interface {
+	Type() Type
+	OccurredAt() time.Time
+}
+
+type LoanIssued struct {
+	Loan loan.Loan
+	At   time.Time
+}
+
+type LoanRenewed struct {
+	Loan loan.Loan
+	At   time.Time
+}
+
+type LoanReturned struct {
+	Loan loan.Loan
+	At   time.Time
+}
+
+// LoanOverridden records a supervisor letting an issue or renewal of the
+// loan break the rules named in Override.
+type LoanOverridden struct {
+	LoanID   string
+	MemberID string
+	Override loan.Override
+	At       time.Time
+}
+
+type MemberStatusChanged struct {
+	MemberID string
+	From     member.Status
+	To       member.Status
+	At       time.Time
+}
+
+// MemberMerged records that From was folded into Into, with the number of
+// loans and holds moved across.
+type MemberMerged struct {
+	From  string
+	Into  string
+	Loans int
+	Holds int
+	At    time.Time
+}
+
+type BookArchived struct {
+	BookID string
+	Title  string
+	At     time.Time
+}
+
+type CopyStatusChanged struct {
+	CopyID string
+	BookID string
+	From   copy.Status
+	To     copy.Status
+	At     time.Time
+}
+
+func (e LoanIssued) Type() Type          { return TypeLoanIssued }
+func (e LoanRenewed) Type() Type         { return TypeLoanRenewed }
+func (e LoanReturned) Type() Type        { return TypeLoanReturned }
+func (e LoanOverridden) Type() Type      { return TypeLoanOverridden }
+func (e MemberStatusChanged) Type() Type { return TypeMemberStatusChanged }
+func (e MemberMerged) Type() Type        { return TypeMemberMerged }
+func (e BookArchived) Type() Type        { return TypeBookArchived }
+func (e CopyStatusChanged) Type() Type   { return TypeCopyStatusChanged }
+
+func (e LoanIssued) OccurredAt() time.Time          { return e.At }
+func (e LoanRenewed) OccurredAt() time.Time         { return e.At }
+func (e LoanReturned) OccurredAt() time.Time        { return e.At }
+func (e LoanOverridden) OccurredAt() time.Time      { return e.At }
+func (e MemberStatusChanged) OccurredAt() time.Time { return e.At }
+func (e MemberMerged) OccurredAt() time.Time        { return e.At }
+func (e BookArchived) OccurredAt() time.Time        { return e.At }
+func (e CopyStatusChanged) OccurredAt() time.Time   { return e.At }
+
+// Envelope is the stored form of an event in the outbox. Seq is assigned
+// by the outbox and increases with every append.
+type Envelope struct {
+	Seq        int64
+	ID         string
+	Type       Type
+	OccurredAt time.Time
+	Payload    json.RawMessage
+}
+
+func Wrap(id string, e Event) (Envelope, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("encode %s event: %w", e.Type(), err)
+	}
+
+	return Envelope{ID: id, Type: e.Type(), OccurredAt: e.OccurredAt(), Payload: payload}, nil
+}
+
+func (env Envelope) Decode() (Event, error) {
+	switch env.Type {
+	case TypeLoanIssued:
+		return decode[LoanIssued](env)
+	case TypeLoanRenewed:
+		return decode[LoanRenewed](env)
+	case TypeLoanReturned:
+		return decode[LoanReturned](env)
+	case TypeLoanOverridden:
+		return decode[LoanOverridden](env)
+	case TypeMemberStatusChanged:
+		return decode[MemberStatusChanged](env)
+	case TypeMemberMerged:
+		return decode[MemberMerged](env)
+	case TypeBookArchived:
+		return decode[BookArchived](env)
+	case TypeCopyStatusChanged:
+		return decode[CopyStatusChanged](env)
+	default:
+		return nil, fmt.Errorf("unknown event type %q", env.Type)
+	}
+}
+
+func decode[T Event](env Envelope) (Event, error) {
+	var e T
+	if err := json.Unmarshal(env.Payload, &e); err != nil {
+		return nil, fmt.Errorf("decode %s event %d: %w", env.Type, env.Seq, err)
+	}
+	return e, nil
+}
@@ -0,0 +1,57 @@
+package hold
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusWaiting   Status = "waiting"
+	StatusFulfilled Status = "fulfilled"
+	StatusCancelled Status = "cancelled"
+)
+
+// Hold is a member's place in the queue for a book. MergedFrom keeps the
+// member who placed it when that record was merged into another.
+type Hold struct {
+	ID         string
+	BookID     string
+	MemberID   string
+	PlacedAt   time.Time
+	ClosedAt   *time.Time
+	Status     Status
+	MergedFrom string
+}
+
+func (h Hold) Validate() error {
+	if strings.TrimSpace(h.ID) == "" {
+		return errors.New("hold id is required")
+	}
+
+	if strings.TrimSpace(h.BookID) == "" {
+		return errors.New("book id is required")
+	}
+
+	if strings.TrimSpace(h.MemberID) == "" {
+		return errors.New("member id is required")
+	}
+
+	if h.PlacedAt.IsZero() {
+		return errors.New("hold placed date is required")
+	}
+
+	switch h.Status {
+	case StatusWaiting, StatusFulfilled, StatusCancelled:
+	default:
+		return errors.New("hold status is invalid")
+	}
+
+	return nil
+}
+
+func (h Hold) IsWaiting() bool {
+	return h.Status == StatusWaiting
+}
@@ -0,0 +1,37 @@
+package hold
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func CanPlace(b book.Book, m member.Member, memberHolds []Hold, now time.Time) error {
+	if err := m.CanBorrow(now); err != nil {
+		return err
+	}
+
+	if b.Status != book.StatusActive {
+		return shared.ErrHoldNotAllowed
+	}
+
+	for _, h := range memberHolds {
+		if h.BookID == b.ID && h.IsWaiting() {
+			return shared.ErrDuplicateHold
+		}
+	}
+
+	return nil
+}
+
+func Cancel(h Hold, now time.Time) (Hold, error) {
+	if !h.IsWaiting() {
+		return Hold{}, shared.ErrHoldClosed
+	}
+
+	h.Status = StatusCancelled
+	h.ClosedAt = &now
+	return h, nil
+}
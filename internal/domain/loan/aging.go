@@ -0,0 +1,37 @@
+package loan
+
+import (
+	"math"
+	"time"
+)
+
+type Aging string
+
+const (
+	AgingNone      Aging = ""
+	Aging1To7      Aging = "1-7"
+	Aging8To30     Aging = "8-30"
+	Aging31AndOver Aging = "31+"
+)
+
+// DaysOverdue counts started days past the due date, so a loan one hour late
+// is one day overdue. Returned and current loans are zero.
+func (l Loan) DaysOverdue(now time.Time) int {
+	if !l.IsOverdue(now) {
+		return 0
+	}
+	return int(math.Ceil(now.Sub(l.DueAt).Hours() / 24))
+}
+
+func AgingFor(daysOverdue int) Aging {
+	switch {
+	case daysOverdue <= 0:
+		return AgingNone
+	case daysOverdue <= 7:
+		return Aging1To7
+	case daysOverdue <= 30:
+		return Aging8To30
+	default:
+		return Aging31AndOver
+	}
+}
@@ -0,0 +1,101 @@
+package loan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// Rule names a check made before a copy is issued or a loan renewed.
+type Rule string
+
+const (
+	RuleCopyAvailable Rule = "copy_available"
+	RuleMemberStatus  Rule = "member_status"
+	RuleMemberBlock   Rule = "member_block"
+	RuleMembership    Rule = "membership"
+	RuleLoanLimit     Rule = "loan_limit"
+	RuleLoanOverdue   Rule = "loan_overdue"
+	RuleRenewalLimit  Rule = "renewal_limit"
+)
+
+// Violation is a rule an issue or renewal would break. Err is the error
+// returned for it and Detail explains it to staff.
+type Violation struct {
+	Rule   Rule
+	Err    error
+	Detail string
+}
+
+// Violations lists every rule issuing c to m would break, in the order
+// CanIssue checks them. It is empty when the loan may go ahead.
+func Violations(c copy.Copy, m member.Member, activeLoans int, now time.Time, p Policy) ([]Violation, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var out []Violation
+	add := func(rule Rule, err error, detail string) {
+		out = append(out, Violation{Rule: rule, Err: err, Detail: detail})
+	}
+
+	if !c.IsAvailable() {
+		name := c.Barcode
+		if name == "" {
+			name = c.ID
+		}
+		add(RuleCopyAvailable, shared.ErrCopyNotAvailable, fmt.Sprintf("copy %s is %s", name, strings.ReplaceAll(string(c.Status), "_", " ")))
+	}
+
+	switch {
+	case m.MergedInto != "":
+		add(RuleMemberStatus, shared.ErrMemberNotEligible, "member was merged into "+m.MergedInto)
+	case m.IsBlocked(now):
+		blocked := m.CanBorrow(now)
+		active := m.ActiveBlocks(now)
+		if len(active) == 0 {
+			add(RuleMemberBlock, blocked, "member is blocked; no reason recorded")
+		}
+		for _, b := range active {
+			add(RuleMemberBlock, blocked, describeBlock(b))
+		}
+	case m.Status == member.StatusInactive && m.IsExpired(now):
+		// Lapsed members are made inactive by the expiry job; the
+		// membership rule below says why.
+	case m.Status != member.StatusActive && m.Status != member.StatusBlocked:
+		add(RuleMemberStatus, shared.ErrMemberNotEligible, "member is "+string(m.Status))
+	}
+
+	if m.IsExpired(now) {
+		add(RuleMembership, shared.ErrMembershipExpired, "membership expired on "+m.ExpiresAt.Format("2006-01-02"))
+	}
+
+	if activeLoans >= p.MaxLoansPerMember {
+		add(RuleLoanLimit, shared.ErrLoanLimitReached, fmt.Sprintf("%d active loans, limit %d", activeLoans, p.MaxLoansPerMember))
+	}
+
+	return out, nil
+}
+
+// describeBlock reads as "blocked: lost card (by amy on 2026-03-01, until
+// 2026-03-08; note: called twice)".
+func describeBlock(b member.Block) string {
+	var about []string
+	if b.CreatedBy != "" {
+		about = append(about, "by "+b.CreatedBy)
+	}
+	about = append(about, "on "+b.CreatedAt.Format("2006-01-02"))
+
+	detail := "blocked: " + b.Reason + " (" + strings.Join(about, " ")
+	if !b.ExpiresAt.IsZero() {
+		detail += ", until " + b.ExpiresAt.Format("2006-01-02")
+	}
+	if b.Note != "" {
+		detail += "; note: " + b.Note
+	}
+	return detail + ")"
+}
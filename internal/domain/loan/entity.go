@@ -13,6 +13,8 @@ const (
 	StatusReturned Status = "returned"
 )
 
+// Loan lends one copy to one member. MergedFrom keeps the member it was
+// lent to when that record was merged into another.
 type Loan struct {
 	ID           string
 	CopyID       string
@@ -22,6 +24,9 @@ type Loan struct {
 	ReturnedAt   *time.Time
 	RenewalCount int
 	Status       Status
+	Period       string
+	Overrides    []Override
+	MergedFrom   string
 }
 
 func (l Loan) Validate() error {
@@ -53,6 +58,12 @@ func (l Loan) Validate() error {
 		return errors.New("returned date is required when status is returned")
 	}
 
+	for _, o := range l.Overrides {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,106 @@
+package loan
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// Override records a supervisor letting an issue or renewal through
+// despite the rules it broke.
+type Override struct {
+	Rules  []Rule
+	Reason string
+	By     string
+	At     time.Time
+}
+
+func (o Override) Validate() error {
+	if len(o.Rules) == 0 {
+		return errors.New("override must name the rules it broke")
+	}
+
+	if strings.TrimSpace(o.Reason) == "" {
+		return errors.New("override reason is required")
+	}
+
+	if strings.TrimSpace(o.By) == "" {
+		return errors.New("override needs the supervisor who gave it")
+	}
+
+	if o.At.IsZero() {
+		return errors.New("override date is required")
+	}
+
+	return nil
+}
+
+// Overridable reports whether a supervisor may override r. Copy
+// availability and the member's standing always apply.
+func (r Rule) Overridable() bool {
+	return r == RuleLoanLimit || r == RuleRenewalLimit || r == RuleLoanOverdue
+}
+
+// MayOverride reports whether user is one of the supervisors.
+func (p Policy) MayOverride(user string) bool {
+	user = strings.TrimSpace(user)
+	return user != "" && slices.Contains(p.Supervisors, user)
+}
+
+// Overrule returns o with the rules it breaks filled in. It fails with the
+// error of the first violation no override lifts, with
+// ErrOverrideNotAllowed when o.By is not a supervisor, or when o gives no
+// reason.
+func Overrule(violations []Violation, o Override, p Policy) (Override, error) {
+	o.Rules = nil
+	for _, v := range violations {
+		if !v.Rule.Overridable() {
+			return Override{}, v.Err
+		}
+		o.Rules = append(o.Rules, v.Rule)
+	}
+
+	if !p.MayOverride(o.By) {
+		return Override{}, shared.ErrOverrideNotAllowed
+	}
+
+	o.By = strings.TrimSpace(o.By)
+	o.Reason = strings.TrimSpace(o.Reason)
+	if err := o.Validate(); err != nil {
+		return Override{}, shared.Invalid(err)
+	}
+
+	return o, nil
+}
+
+// RenewOverride renews l despite the rules o overrides. A loan that breaks
+// none is renewed as usual and o is not recorded.
+func RenewOverride(l Loan, o Override, now time.Time, p Policy) (Loan, error) {
+	violations, err := RenewalViolations(l, now, p)
+	if err != nil {
+		return Loan{}, err
+	}
+
+	if len(violations) == 0 {
+		return extend(l, l.DueAt, p), nil
+	}
+
+	o, err = Overrule(violations, o, p)
+	if err != nil {
+		return Loan{}, err
+	}
+
+	// An overdue loan is renewed from now; extending its old due date
+	// could leave it overdue again.
+	from := l.DueAt
+	if l.IsOverdue(now) {
+		from = now
+	}
+
+	l = extend(l, from, p)
+	l.Overrides = append(append([]Override(nil), l.Overrides...), o)
+	return l, nil
+}
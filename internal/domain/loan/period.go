@@ -0,0 +1,167 @@
+package loan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// shortLoan is the length under which a loan is short: due at a time of
+// day rather than on a date, and late by the hour.
+const shortLoan = 24 * time.Hour
+
+// Period is a named loan length, such as a two-hour reserve desk loan.
+type Period struct {
+	Name   string
+	Length time.Duration
+}
+
+// Terms are what the desk may ask for at issue: a named period or a due
+// date. The zero value lends for the policy's LoanDays.
+type Terms struct {
+	Period string
+	DueAt  time.Time
+}
+
+// ParseTerms reads what the desk typed for a loan's due: a date and time
+// such as "2026-03-01 16:00", a date, which means the end of that day, or
+// the name of a period. Blank is the default loan.
+func ParseTerms(s string, loc *time.Location) Terms {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, loc); err == nil {
+		return Terms{DueAt: t}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return Terms{DueAt: t.AddDate(0, 0, 1).Add(-time.Minute)}
+	}
+	return Terms{Period: s}
+}
+
+// ParseLength reads a loan length as a Go duration such as "2h" or "90m",
+// or as whole days such as "7d".
+func ParseLength(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("loan length %q must be a positive number of days", s)
+		}
+		return time.Duration(n) * shortLoan, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("loan length %q must be a positive duration such as 2h or 7d", s)
+	}
+	return d, nil
+}
+
+// ParsePeriods reads a list such as "reserve=2h,week=7d,semester=120d".
+func ParsePeriods(s string) ([]Period, error) {
+	var out []Period
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		name, length, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("loan period %q must look like name=length", strings.TrimSpace(part))
+		}
+		d, err := ParseLength(length)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Period{Name: strings.ToLower(strings.TrimSpace(name)), Length: d})
+	}
+	return out, nil
+}
+
+// Period finds a configured period by name, ignoring case.
+func (p Policy) Period(name string) (Period, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, period := range p.Periods {
+		if period.Name == name {
+			return period, true
+		}
+	}
+	return Period{}, false
+}
+
+// DueAt works out when a loan issued at issuedAt on terms t falls due. A
+// period or due date must give a loan between MinLength and MaxLength.
+func (p Policy) DueAt(issuedAt time.Time, t Terms) (time.Time, error) {
+	period := strings.TrimSpace(t.Period)
+	switch {
+	case period != "" && !t.DueAt.IsZero():
+		return time.Time{}, shared.Invalid(errors.New("give a loan period or a due date, not both"))
+	case period != "":
+		found, ok := p.Period(period)
+		if !ok {
+			return time.Time{}, shared.Invalid(fmt.Errorf("unknown loan period %q (known: %s)", period, p.periodNames()))
+		}
+		return p.bounded(issuedAt, issuedAt.Add(found.Length))
+	case !t.DueAt.IsZero():
+		return p.bounded(issuedAt, t.DueAt)
+	default:
+		return issuedAt.AddDate(0, 0, p.LoanDays), nil
+	}
+}
+
+func (p Policy) bounded(issuedAt, due time.Time) (time.Time, error) {
+	length := due.Sub(issuedAt)
+	switch {
+	case length <= 0:
+		return time.Time{}, shared.Invalid(errors.New("due date must be after the issue time"))
+	case p.MinLength > 0 && length < p.MinLength:
+		return time.Time{}, shared.Invalid(fmt.Errorf("loan must last at least %s", FormatLength(p.MinLength)))
+	case p.MaxLength > 0 && length > p.MaxLength:
+		return time.Time{}, shared.Invalid(fmt.Errorf("loan may last at most %s", FormatLength(p.MaxLength)))
+	}
+	return due, nil
+}
+
+func (p Policy) periodNames() string {
+	if len(p.Periods) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(p.Periods))
+	for _, period := range p.Periods {
+		names = append(names, period.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// FormatLength writes whole days as "7d" and anything else as a duration.
+func FormatLength(d time.Duration) string {
+	if d >= shortLoan && d%shortLoan == 0 {
+		return strconv.Itoa(int(d/shortLoan)) + "d"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// IsShort reports whether l was lent for less than a day.
+func (l Loan) IsShort() bool {
+	return l.DueAt.Sub(l.IssuedAt) < shortLoan
+}
+
+// HoursOverdue counts started hours past the due time. Returned and
+// current loans are zero.
+func (l Loan) HoursOverdue(now time.Time) int {
+	if !l.IsOverdue(now) {
+		return 0
+	}
+	return int(math.Ceil(now.Sub(l.DueAt).Hours()))
+}
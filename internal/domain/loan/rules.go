@@ -2,6 +2,7 @@ package loan
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
@@ -9,10 +10,18 @@ import (
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
+// Policy sets the loan rules. Supervisors lists the staff who may
+// override the loan limit, renewal limit and overdue rules. Periods are the
+// named loan lengths the desk may pick instead of LoanDays; they and due
+// dates picked by hand must lie between MinLength and MaxLength, where set.
 type Policy struct {
 	LoanDays          int
 	MaxLoansPerMember int
 	MaxRenewals       int
+	Supervisors       []string
+	Periods           []Period
+	MinLength         time.Duration
+	MaxLength         time.Duration
 }
 
 func (p Policy) Validate() error {
@@ -28,43 +37,62 @@ func (p Policy) Validate() error {
 		return errors.New("max renewals cannot be negative")
 	}
 
-	return nil
-}
-
-func CanIssue(c copy.Copy, m member.Member, activeLoans int, p Policy) error {
-	if err := p.Validate(); err != nil {
-		return err
+	if p.MinLength < 0 || p.MaxLength < 0 || (p.MaxLength > 0 && p.MinLength > p.MaxLength) {
+		return errors.New("loan length bounds must be positive, minimum first")
 	}
 
-	if !c.IsAvailable() {
-		return shared.ErrCopyNotAvailable
+	for _, period := range p.Periods {
+		if period.Name == "" || period.Length <= 0 {
+			return errors.New("loan periods need a name and a positive length")
+		}
 	}
 
-	if !m.CanBorrow() {
-		return shared.ErrMemberNotEligible
+	return nil
+}
+
+// CanIssue returns the error for the first rule issuing c to m would
+// break, or nil. Violations lists all of them.
+func CanIssue(c copy.Copy, m member.Member, activeLoans int, now time.Time, p Policy) error {
+	violations, err := Violations(c, m, activeLoans, now, p)
+	if err != nil {
+		return err
 	}
 
-	if activeLoans >= p.MaxLoansPerMember {
-		return shared.ErrLoanLimitReached
+	if len(violations) > 0 {
+		return violations[0].Err
 	}
 
 	return nil
 }
 
 func New(id, copyID, memberID string, issuedAt time.Time, p Policy) (Loan, error) {
+	return NewWithTerms(id, copyID, memberID, issuedAt, Terms{}, p)
+}
+
+// NewWithTerms lends for the period or until the due date in t, or for
+// LoanDays when t is empty.
+func NewWithTerms(id, copyID, memberID string, issuedAt time.Time, t Terms, p Policy) (Loan, error) {
 	if err := p.Validate(); err != nil {
 		return Loan{}, err
 	}
 
+	due, err := p.DueAt(issuedAt, t)
+	if err != nil {
+		return Loan{}, err
+	}
+
 	l := Loan{
 		ID:           id,
 		CopyID:       copyID,
 		MemberID:     memberID,
 		IssuedAt:     issuedAt,
-		DueAt:        issuedAt.AddDate(0, 0, p.LoanDays),
+		DueAt:        due,
 		RenewalCount: 0,
 		Status:       StatusActive,
 	}
+	if period, ok := p.Period(t.Period); ok {
+		l.Period = period.Name
+	}
 
 	if err := l.Validate(); err != nil {
 		return Loan{}, err
@@ -74,26 +102,51 @@ func New(id, copyID, memberID string, issuedAt time.Time, p Policy) (Loan, error
 }
 
 func Renew(l Loan, now time.Time, p Policy) (Loan, error) {
-	if err := p.Validate(); err != nil {
+	violations, err := RenewalViolations(l, now, p)
+	if err != nil {
 		return Loan{}, err
 	}
 
+	if len(violations) > 0 {
+		return Loan{}, violations[0].Err
+	}
+
+	return extend(l, l.DueAt, p), nil
+}
+
+// RenewalViolations lists every rule renewing l at now would break. A
+// returned loan cannot be renewed at all and fails instead.
+func RenewalViolations(l Loan, now time.Time, p Policy) ([]Violation, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
 	if l.Status != StatusActive || l.ReturnedAt != nil {
-		return Loan{}, shared.ErrLoanAlreadyClosed
+		return nil, shared.ErrLoanAlreadyClosed
 	}
 
+	var out []Violation
 	if l.IsOverdue(now) {
-		return Loan{}, shared.ErrLoanAlreadyOverdue
+		out = append(out, Violation{Rule: RuleLoanOverdue, Err: shared.ErrLoanAlreadyOverdue, Detail: "loan was due on " + l.DueAt.Format("2006-01-02")})
 	}
 
 	if l.RenewalCount >= p.MaxRenewals {
-		return Loan{}, shared.ErrRenewalLimit
+		out = append(out, Violation{Rule: RuleRenewalLimit, Err: shared.ErrRenewalLimit, Detail: fmt.Sprintf("renewed %d times, limit %d", l.RenewalCount, p.MaxRenewals)})
 	}
 
-	l.RenewalCount++
-	l.DueAt = l.DueAt.AddDate(0, 0, p.LoanDays)
+	return out, nil
+}
 
-	return l, nil
+// extend renews l, starting at from, for another of its period, or for
+// LoanDays when it has none or its period is no longer offered.
+func extend(l Loan, from time.Time, p Policy) Loan {
+	l.RenewalCount++
+	if period, ok := p.Period(l.Period); ok && l.Period != "" {
+		l.DueAt = from.Add(period.Length)
+	} else {
+		l.DueAt = from.AddDate(0, 0, p.LoanDays)
+	}
+	return l
 }
 
 func Return(l Loan, returnedAt time.Time) (Loan, error) {
@@ -50,6 +50,15 @@ func TestCanIssue(t *testing.T) {
 			active: 0,
 			want:   shared.ErrMemberNotEligible,
 		},
+		{
+			name: "membership expired",
+			copy: copy.Copy{ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable},
+			member: member.Member{
+				ID: "m-1", Name: "A", JoinedAt: time.Now(), ExpiresAt: time.Now().Add(-time.Hour), Status: member.StatusActive,
+			},
+			active: 0,
+			want:   shared.ErrMembershipExpired,
+		},
 		{
 			name: "loan limit reached",
 			copy: copy.Copy{ID: "c-1", BookID: "b-1", Status: copy.StatusAvailable},
@@ -66,7 +75,7 @@ func TestCanIssue(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := loan.CanIssue(tc.copy, tc.member, tc.active, p)
+			err := loan.CanIssue(tc.copy, tc.member, tc.active, time.Now(), p)
 			if !errors.Is(err, tc.want) {
 				t.Fatalf("expected %v got %v", tc.want, err)
 			}
@@ -74,6 +83,48 @@ func TestCanIssue(t *testing.T) {
 	}
 }
 
+func TestViolationsListsEveryFailedRule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	p := loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1}
+	m := member.Member{
+		ID: "m-1", Name: "A", JoinedAt: now.AddDate(-1, 0, 0), ExpiresAt: now.AddDate(0, 0, -2), Status: member.StatusBlocked,
+		Blocks: []member.Block{
+			{Reason: "lost card", Note: "called twice", CreatedBy: "amy", CreatedAt: now.AddDate(0, 0, -9), ExpiresAt: now.AddDate(0, 0, 5)},
+			{Reason: "old block", CreatedAt: now.AddDate(0, 0, -30), ExpiresAt: now.AddDate(0, 0, -1)},
+		},
+	}
+
+	got, err := loan.Violations(copy.Copy{ID: "c-1", Barcode: "C000012", Status: copy.StatusInTransit}, m, 3, now, p)
+	if err != nil {
+		t.Fatalf("violations: %v", err)
+	}
+
+	want := []struct {
+		rule   loan.Rule
+		err    error
+		detail string
+	}{
+		{loan.RuleCopyAvailable, shared.ErrCopyNotAvailable, "copy C000012 is in transit"},
+		{loan.RuleMemberBlock, shared.ErrMemberNotEligible, "blocked: lost card (by amy on 2026-03-01, until 2026-03-15; note: called twice)"},
+		{loan.RuleMembership, shared.ErrMembershipExpired, "membership expired on 2026-03-08"},
+		{loan.RuleLoanLimit, shared.ErrLoanLimitReached, "3 active loans, limit 3"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d violations got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Rule != w.rule || !errors.Is(got[i].Err, w.err) || got[i].Detail != w.detail {
+			t.Fatalf("violation %d: expected %s %q got %+v", i, w.rule, w.detail, got[i])
+		}
+	}
+
+	if err := loan.CanIssue(copy.Copy{ID: "c-1", Status: copy.StatusInTransit}, m, 3, now, p); !errors.Is(err, shared.ErrCopyNotAvailable) {
+		t.Fatalf("expected CanIssue to report the first violation, got %v", err)
+	}
+}
+
 func TestRenew(t *testing.T) {
 	t.Parallel()
 
@@ -137,6 +188,164 @@ func TestRenew(t *testing.T) {
 	}
 }
 
+func TestRenewOverride(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	p := loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1, Supervisors: []string{"sam"}}
+	late := loan.Loan{
+		ID: "l-1", CopyID: "c-1", MemberID: "m-1",
+		IssuedAt: now.AddDate(0, 0, -20), DueAt: now.AddDate(0, 0, -1), Status: loan.StatusActive, RenewalCount: 1,
+	}
+
+	tests := []struct {
+		name string
+		in   loan.Loan
+		o    loan.Override
+		want error
+	}{
+		{name: "not a supervisor", in: late, o: loan.Override{By: "amy", Reason: "exam week", At: now}, want: shared.ErrOverrideNotAllowed},
+		{name: "no reason", in: late, o: loan.Override{By: "sam", Reason: " ", At: now}, want: shared.ErrInvalidInput},
+		{name: "returned loan", in: loan.Loan{ID: "l-1", Status: loan.StatusReturned}, o: loan.Override{By: "sam", Reason: "exam week", At: now}, want: shared.ErrLoanAlreadyClosed},
+		{name: "overridden", in: late, o: loan.Override{By: "sam", Reason: "exam week", At: now}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := loan.RenewOverride(tc.in, tc.o, now, p)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v got %v", tc.want, err)
+			}
+			if tc.want != nil {
+				return
+			}
+			if out.RenewalCount != 2 || len(out.Overrides) != 1 {
+				t.Fatalf("expected a renewal with one override got %+v", out)
+			}
+			if got := out.Overrides[0].Rules; len(got) != 2 || got[0] != loan.RuleLoanOverdue || got[1] != loan.RuleRenewalLimit {
+				t.Fatalf("expected the overdue and renewal limit rules got %v", got)
+			}
+		})
+	}
+
+	longOverdue := late
+	longOverdue.DueAt = now.AddDate(0, 0, -30)
+	out, err := loan.RenewOverride(longOverdue, loan.Override{By: "sam", Reason: "exam week", At: now}, now, p)
+	if err != nil {
+		t.Fatalf("renew long overdue loan: %v", err)
+	}
+	if want := now.AddDate(0, 0, 14); !out.DueAt.Equal(want) || out.IsOverdue(now) {
+		t.Fatalf("expected a loan two periods late to be due %s got %s", want, out.DueAt)
+	}
+
+	blocked := []loan.Violation{{Rule: loan.RuleMemberBlock, Err: shared.ErrMemberNotEligible}, {Rule: loan.RuleLoanLimit, Err: shared.ErrLoanLimitReached}}
+	if _, err := loan.Overrule(blocked, loan.Override{By: "sam", Reason: "exam week", At: now}, p); !errors.Is(err, shared.ErrMemberNotEligible) {
+		t.Fatalf("expected a block to survive the override got %v", err)
+	}
+}
+
+func TestDueAt(t *testing.T) {
+	t.Parallel()
+
+	issued := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	p := loan.Policy{
+		LoanDays:          14,
+		MaxLoansPerMember: 3,
+		Periods:           []loan.Period{{Name: "reserve", Length: 2 * time.Hour}, {Name: "semester", Length: 120 * 24 * time.Hour}},
+		MinLength:         time.Hour,
+		MaxLength:         60 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name  string
+		terms loan.Terms
+		want  time.Time
+		err   string
+	}{
+		{name: "default", want: issued.AddDate(0, 0, 14)},
+		{name: "period", terms: loan.Terms{Period: "Reserve"}, want: issued.Add(2 * time.Hour)},
+		{name: "due date", terms: loan.Terms{DueAt: issued.Add(90 * time.Minute)}, want: issued.Add(90 * time.Minute)},
+		{name: "both", terms: loan.Terms{Period: "reserve", DueAt: issued.Add(3 * time.Hour)}, err: "give a loan period or a due date, not both"},
+		{name: "unknown period", terms: loan.Terms{Period: "week"}, err: `unknown loan period "week" (known: reserve, semester)`},
+		{name: "period too long", terms: loan.Terms{Period: "semester"}, err: "loan may last at most 60d"},
+		{name: "too short", terms: loan.Terms{DueAt: issued.Add(30 * time.Minute)}, err: "loan must last at least 1h"},
+		{name: "in the past", terms: loan.Terms{DueAt: issued.Add(-time.Hour)}, err: "due date must be after the issue time"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := p.DueAt(issued, tc.terms)
+			if tc.err != "" {
+				if !errors.Is(err, shared.ErrInvalidInput) || err.Error() != tc.err {
+					t.Fatalf("expected invalid input %q got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tc.want) {
+				t.Fatalf("expected %v got %v, %v", tc.want, got, err)
+			}
+		})
+	}
+}
+
+func TestShortLoans(t *testing.T) {
+	t.Parallel()
+
+	issued := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	p := loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1, Periods: []loan.Period{{Name: "reserve", Length: 2 * time.Hour}}}
+
+	l, err := loan.NewWithTerms("l-1", "c-1", "m-1", issued, loan.Terms{Period: "reserve"}, p)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if l.Period != "reserve" || !l.IsShort() || !l.DueAt.Equal(issued.Add(2*time.Hour)) {
+		t.Fatalf("expected a two hour reserve loan got %+v", l)
+	}
+
+	if got := l.HoursOverdue(issued.Add(2*time.Hour + 10*time.Minute)); got != 1 {
+		t.Fatalf("expected one started hour overdue got %d", got)
+	}
+	if got := l.HoursOverdue(issued.Add(5 * time.Hour)); got != 3 {
+		t.Fatalf("expected three hours overdue got %d", got)
+	}
+
+	renewed, err := loan.Renew(l, issued.Add(time.Hour), p)
+	if err != nil || !renewed.DueAt.Equal(issued.Add(4*time.Hour)) {
+		t.Fatalf("expected a renewal by another period got %v, %v", renewed.DueAt, err)
+	}
+
+	terms := []struct {
+		in   string
+		want loan.Terms
+	}{
+		{in: "", want: loan.Terms{}},
+		{in: "reserve", want: loan.Terms{Period: "reserve"}},
+		{in: "2026-03-02 16:30", want: loan.Terms{DueAt: time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC)}},
+		{in: "2026-03-20", want: loan.Terms{DueAt: time.Date(2026, 3, 20, 23, 59, 0, 0, time.UTC)}},
+	}
+	for _, tc := range terms {
+		if got := loan.ParseTerms(tc.in, time.UTC); got != tc.want {
+			t.Fatalf("ParseTerms(%q): expected %+v got %+v", tc.in, tc.want, got)
+		}
+	}
+
+	periods, err := loan.ParsePeriods("Reserve=2h, week=7d,,semester=120d")
+	if err != nil || len(periods) != 3 || periods[0] != (loan.Period{Name: "reserve", Length: 2 * time.Hour}) || periods[1].Length != 7*24*time.Hour {
+		t.Fatalf("unexpected periods %+v, %v", periods, err)
+	}
+	for _, bad := range []string{"reserve", "reserve=0h", "week=x d", "week=-2d"} {
+		if _, err := loan.ParsePeriods(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
 func TestReturn(t *testing.T) {
 	t.Parallel()
 
@@ -163,3 +372,38 @@ func TestReturn(t *testing.T) {
 		t.Fatalf("expected returned date to be set")
 	}
 }
+
+func TestDaysOverdueAndAging(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	l := loan.Loan{DueAt: due, Status: loan.StatusActive}
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		days  int
+		aging loan.Aging
+	}{
+		{name: "not yet due", now: due.Add(-time.Hour), days: 0, aging: loan.AgingNone},
+		{name: "one hour late", now: due.Add(time.Hour), days: 1, aging: loan.Aging1To7},
+		{name: "seven days", now: due.AddDate(0, 0, 7), days: 7, aging: loan.Aging1To7},
+		{name: "eight days", now: due.AddDate(0, 0, 7).Add(time.Minute), days: 8, aging: loan.Aging8To30},
+		{name: "thirty days", now: due.AddDate(0, 0, 30), days: 30, aging: loan.Aging8To30},
+		{name: "thirty one days", now: due.AddDate(0, 0, 31), days: 31, aging: loan.Aging31AndOver},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			days := l.DaysOverdue(tt.now)
+			if days != tt.days {
+				t.Fatalf("expected %d days got %d", tt.days, days)
+			}
+			if got := loan.AgingFor(days); got != tt.aging {
+				t.Fatalf("expected bucket %q got %q", tt.aging, got)
+			}
+		})
+	}
+}
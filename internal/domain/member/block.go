@@ -0,0 +1,188 @@
+package member
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BlockRule names the automatic rule that placed a block. Blocks placed by
+// staff have none.
+type BlockRule string
+
+const BlockRuleOverdue BlockRule = "overdue"
+
+// Block is one reason a member may not borrow. A zero ExpiresAt keeps the
+// block until it is lifted. Lifted blocks are kept as a record, with who
+// lifted them and when.
+type Block struct {
+	Reason    string
+	Note      string
+	CreatedBy string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Rule      BlockRule
+	LiftedAt  time.Time
+	LiftedBy  string
+}
+
+func (b Block) Validate() error {
+	if strings.TrimSpace(b.Reason) == "" {
+		return errors.New("block reason is required")
+	}
+
+	if b.CreatedAt.IsZero() {
+		return errors.New("block date is required")
+	}
+
+	if !b.ExpiresAt.IsZero() && !b.ExpiresAt.After(b.CreatedAt) {
+		return errors.New("block expiry must be after the block date")
+	}
+
+	if !b.LiftedAt.IsZero() && b.LiftedAt.Before(b.CreatedAt) {
+		return errors.New("block cannot be lifted before it was placed")
+	}
+
+	return nil
+}
+
+func (b Block) IsActive(now time.Time) bool {
+	return b.LiftedAt.IsZero() && (b.ExpiresAt.IsZero() || now.Before(b.ExpiresAt))
+}
+
+// BlockPolicy sets the automatic blocking rule: a member with at least
+// OverdueItems loans more than OverdueDays days overdue is blocked until
+// enough of them come back. A zero OverdueItems turns the rule off.
+type BlockPolicy struct {
+	OverdueItems int
+	OverdueDays  int
+}
+
+// OverdueReason reports whether loans overdue by the given numbers of days
+// trip the rule, and the reason to record when they do.
+func (p BlockPolicy) OverdueReason(daysOverdue []int) (string, bool) {
+	if p.OverdueItems <= 0 {
+		return "", false
+	}
+
+	n := 0
+	for _, d := range daysOverdue {
+		if d > p.OverdueDays {
+			n++
+		}
+	}
+	if n < p.OverdueItems {
+		return "", false
+	}
+
+	items := "items"
+	if n == 1 {
+		items = "item"
+	}
+	return fmt.Sprintf("%d %s overdue more than %d days", n, items, p.OverdueDays), true
+}
+
+// ActiveBlocks returns the blocks still in force at now.
+func (m Member) ActiveBlocks(now time.Time) []Block {
+	var out []Block
+	for _, b := range m.Blocks {
+		if b.IsActive(now) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// BlockReason joins the reasons of the blocks in force at now. Members
+// blocked before blocks were recorded have none.
+func (m Member) BlockReason(now time.Time) string {
+	active := m.ActiveBlocks(now)
+	if len(active) == 0 {
+		return "no reason recorded"
+	}
+
+	reasons := make([]string, 0, len(active))
+	for _, b := range active {
+		reasons = append(reasons, b.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// IsBlocked reports whether a blocked member is still held back at now.
+// Once every recorded block has expired the member may borrow again, even
+// before the status is brought up to date.
+func (m Member) IsBlocked(now time.Time) bool {
+	return m.Status == StatusBlocked && (len(m.Blocks) == 0 || len(m.ActiveBlocks(now)) > 0)
+}
+
+// AddBlock records b and blocks the member.
+func AddBlock(m Member, b Block) (Member, error) {
+	if m.MergedInto != "" {
+		return Member{}, fmt.Errorf("member was merged into %s", m.MergedInto)
+	}
+	b.Reason = strings.TrimSpace(b.Reason)
+	b.Note = strings.TrimSpace(b.Note)
+	b.CreatedBy = strings.TrimSpace(b.CreatedBy)
+	if err := b.Validate(); err != nil {
+		return Member{}, err
+	}
+
+	m.Blocks = append(append([]Block(nil), m.Blocks...), b)
+	m.Status = StatusBlocked
+	return m, nil
+}
+
+// Unblock lifts every block in force, recording by as the one who lifted
+// them. The member becomes active again, or inactive when the membership
+// has lapsed meanwhile.
+func Unblock(m Member, now time.Time, by string) Member {
+	m.Blocks = liftWhere(m.Blocks, now, by, func(Block) bool { return true })
+	if m.Status == StatusBlocked {
+		m.Status = statusAfterBlock(m, now)
+	}
+	return m
+}
+
+// LiftBlocks lifts the blocks in force that lift selects, and reports
+// whether the member changed. A member left without blocks in force,
+// because they were lifted or have expired, is unblocked; a member blocked
+// without any recorded block stays blocked.
+func LiftBlocks(m Member, now time.Time, by string, lift func(Block) bool) (Member, bool) {
+	if len(m.Blocks) == 0 {
+		return m, false
+	}
+
+	before := len(m.ActiveBlocks(now))
+	m.Blocks = liftWhere(m.Blocks, now, by, lift)
+	changed := len(m.ActiveBlocks(now)) != before
+
+	if m.Status == StatusBlocked && len(m.ActiveBlocks(now)) == 0 {
+		m.Status = statusAfterBlock(m, now)
+		changed = true
+	}
+	return m, changed
+}
+
+func liftWhere(blocks []Block, now time.Time, by string, lift func(Block) bool) []Block {
+	if len(blocks) == 0 {
+		return blocks
+	}
+
+	out := make([]Block, 0, len(blocks))
+	for _, b := range blocks {
+		if b.IsActive(now) && lift(b) {
+			b.LiftedAt = now
+			b.LiftedBy = strings.TrimSpace(by)
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func statusAfterBlock(m Member, now time.Time) Status {
+	if m.IsExpired(now) {
+		return StatusInactive
+	}
+	return StatusActive
+}
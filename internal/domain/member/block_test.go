@@ -0,0 +1,135 @@
+package member_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func TestBlocksAndBorrowing(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	base := member.Member{ID: "m-1", Name: "Ann", JoinedAt: now.AddDate(-1, 0, 0), Status: member.StatusActive}
+
+	if _, err := member.AddBlock(base, member.Block{Reason: " ", CreatedAt: now}); err == nil {
+		t.Fatalf("expected a block without a reason to be rejected")
+	}
+	if _, err := member.AddBlock(base, member.Block{Reason: "lost card", CreatedAt: now, ExpiresAt: now}); err == nil {
+		t.Fatalf("expected a block ending when it starts to be rejected")
+	}
+
+	blocked, err := member.AddBlock(base, member.Block{Reason: "lost card", Note: "called 1 Mar", CreatedBy: "amy", CreatedAt: now, ExpiresAt: now.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+	if blocked.Status != member.StatusBlocked || len(blocked.Blocks) != 1 {
+		t.Fatalf("expected a blocked member with one block, got %+v", blocked)
+	}
+
+	err = blocked.CanBorrow(now)
+	if !errors.Is(err, shared.ErrMemberNotEligible) || !strings.Contains(err.Error(), "lost card") {
+		t.Fatalf("expected not eligible with the reason, got %v", err)
+	}
+	if err := blocked.CanBorrow(now.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("expected an expired block to stop applying, got %v", err)
+	}
+
+	legacy := base
+	legacy.Status = member.StatusBlocked
+	if err := legacy.CanBorrow(now); !errors.Is(err, shared.ErrMemberNotEligible) || !strings.Contains(err.Error(), "no reason recorded") {
+		t.Fatalf("expected a blocked member without records to stay blocked, got %v", err)
+	}
+	if _, lifted := member.LiftBlocks(legacy, now, "amy", func(member.Block) bool { return true }); lifted {
+		t.Fatalf("expected nothing to lift without recorded blocks")
+	}
+	if got := member.Unblock(legacy, now, "amy"); got.Status != member.StatusActive {
+		t.Fatalf("expected unblock to reactivate, got %s", got.Status)
+	}
+}
+
+func TestCanBorrowReportsExpiryBeforeStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	lapsed := member.Member{ID: "m-1", Name: "Ann", JoinedAt: now.AddDate(-1, 0, 0), ExpiresAt: now.AddDate(0, 0, -1), Status: member.StatusInactive}
+
+	tests := []struct {
+		name   string
+		status member.Status
+		expiry time.Time
+		want   error
+	}{
+		{name: "deactivated by the expiry job", status: member.StatusInactive, expiry: lapsed.ExpiresAt, want: shared.ErrMembershipExpired},
+		{name: "expired but still active", status: member.StatusActive, expiry: lapsed.ExpiresAt, want: shared.ErrMembershipExpired},
+		{name: "inactive and current", status: member.StatusInactive, expiry: now.AddDate(1, 0, 0), want: shared.ErrMemberNotEligible},
+	}
+
+	for _, tt := range tests {
+		m := lapsed
+		m.Status, m.ExpiresAt = tt.status, tt.expiry
+		if err := m.CanBorrow(now); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: expected %v got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestLiftBlocks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := member.Member{ID: "m-1", Name: "Ann", JoinedAt: now.AddDate(-1, 0, 0), ExpiresAt: now.AddDate(0, 1, 0), Status: member.StatusBlocked, Blocks: []member.Block{
+		{Reason: "damaged book", CreatedAt: now.AddDate(0, 0, -3)},
+		{Reason: "2 items overdue more than 30 days", CreatedAt: now.AddDate(0, 0, -2), Rule: member.BlockRuleOverdue},
+	}}
+	auto := func(b member.Block) bool { return b.Rule == member.BlockRuleOverdue }
+
+	lifted, ok := member.LiftBlocks(m, now, "system", auto)
+	if !ok || lifted.Status != member.StatusBlocked || len(lifted.Blocks) != 2 || lifted.BlockReason(now) != "damaged book" {
+		t.Fatalf("expected only the overdue block lifted, got %+v", lifted)
+	}
+	if b := lifted.Blocks[1]; !b.LiftedAt.Equal(now) || b.LiftedBy != "system" || !m.Blocks[1].LiftedAt.IsZero() {
+		t.Fatalf("expected the lift recorded on a copy of the blocks, got %+v", lifted.Blocks)
+	}
+	if _, ok := member.LiftBlocks(lifted, now, "system", auto); ok {
+		t.Fatalf("expected a lifted block to stay lifted")
+	}
+
+	lifted.Blocks[0].ExpiresAt = now.AddDate(0, 0, -1)
+	lifted, ok = member.LiftBlocks(lifted, now, "system", auto)
+	if !ok || lifted.Status != member.StatusActive || len(lifted.Blocks) != 2 || !lifted.Blocks[0].LiftedAt.IsZero() {
+		t.Fatalf("expected the expired block kept and the member active, got %+v", lifted)
+	}
+
+	m.ExpiresAt = now.AddDate(0, 0, -1)
+	if got := member.Unblock(m, now, "amy"); got.Status != member.StatusInactive {
+		t.Fatalf("expected a lapsed member to become inactive, got %s", got.Status)
+	}
+}
+
+func TestBlockPolicyOverdueReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy member.BlockPolicy
+		days   []int
+		want   string
+	}{
+		{name: "rule off", policy: member.BlockPolicy{OverdueDays: 30}, days: []int{40, 50}},
+		{name: "too few", policy: member.BlockPolicy{OverdueItems: 2, OverdueDays: 30}, days: []int{40, 30, 5}},
+		{name: "tripped", policy: member.BlockPolicy{OverdueItems: 2, OverdueDays: 30}, days: []int{40, 31, 5}, want: "2 items overdue more than 30 days"},
+		{name: "single item", policy: member.BlockPolicy{OverdueItems: 1, OverdueDays: 0}, days: []int{1}, want: "1 item overdue more than 0 days"},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.policy.OverdueReason(tt.days)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Fatalf("%s: expected %q got %q %v", tt.name, tt.want, got, ok)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package member
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+var (
+	ErrEmailInvalid = errors.New("email address is invalid")
+	ErrPhoneInvalid = errors.New("phone number may only contain digits, spaces, +, -, . and parentheses")
+	ErrPhoneLength  = errors.New("phone number must have 7 to 15 digits")
+)
+
+// E.164 numbers carry at most 15 digits, country code included.
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// NormalizeEmail checks that raw is a bare address such as
+// ann@example.com and returns it trimmed, with the domain lowercased.
+func NormalizeEmail(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return "", ErrEmailInvalid
+	}
+
+	local, domain, _ := strings.Cut(s, "@")
+	if strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", ErrEmailInvalid
+	}
+	return local + "@" + strings.ToLower(domain), nil
+}
+
+// NormalizePhone returns raw in E.164 form, such as +33612345678. Numbers
+// written with + or 00 are international; any other number is national
+// and gets countryCode in place of its leading trunk 0. Without a
+// countryCode a national number is kept as its digits alone.
+func NormalizePhone(raw, countryCode string) (string, error) {
+	s := strings.TrimSpace(raw)
+	digits, err := phoneDigitsOf(s)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case strings.HasPrefix(s, "+"):
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	default:
+		cc := strings.TrimPrefix(strings.TrimSpace(countryCode), "+")
+		if cc == "" {
+			if len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits {
+				return "", ErrPhoneLength
+			}
+			return digits, nil
+		}
+		digits = cc + strings.TrimPrefix(digits, "0")
+	}
+
+	if len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits || digits[0] == '0' {
+		return "", ErrPhoneLength
+	}
+	return "+" + digits, nil
+}
+
+func phoneDigitsOf(s string) (string, error) {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0, r == ' ', r == '-', r == '.', r == '(', r == ')':
+		default:
+			return "", ErrPhoneInvalid
+		}
+	}
+	return b.String(), nil
+}
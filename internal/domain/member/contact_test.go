@@ -0,0 +1,103 @@
+package member_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw  string
+		want string
+		err  bool
+	}{
+		{raw: " Ann.Lee@Example.COM ", want: "Ann.Lee@example.com"},
+		{raw: "demo@local", want: "demo@local"},
+		{raw: "joe+library@gmail.com", want: "joe+library@gmail.com"},
+		{raw: "joe", err: true},
+		{raw: "joe@", err: true},
+		{raw: "joe@@example.com", err: true},
+		{raw: "joe @example.com", err: true},
+		{raw: "Joe <joe@example.com>", err: true},
+		{raw: "joe@example..com", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := member.NormalizeEmail(tt.raw)
+		if tt.err {
+			if !errors.Is(err, member.ErrEmailInvalid) {
+				t.Fatalf("%q: expected invalid email, got %q %v", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: expected %q got %q %v", tt.raw, tt.want, got, err)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw     string
+		country string
+		want    string
+		err     error
+	}{
+		{raw: "+33 6 12 34 56 78", want: "+33612345678"},
+		{raw: "0033 (6) 12-34-56-78", want: "+33612345678"},
+		{raw: "06 12 34 56 78", country: "33", want: "+33612345678"},
+		{raw: "(555) 010-1234", country: "+1", want: "+15550101234"},
+		{raw: "06 12 34 56 78", want: "0612345678"},
+		{raw: "555", err: member.ErrPhoneLength},
+		{raw: "+33 6 12 ext 4", err: member.ErrPhoneInvalid},
+		{raw: "6+12", country: "33", err: member.ErrPhoneInvalid},
+		{raw: "+123", err: member.ErrPhoneLength},
+		{raw: "+1234567890123456", err: member.ErrPhoneLength},
+	}
+
+	for _, tt := range tests {
+		got, err := member.NormalizePhone(tt.raw, tt.country)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%q: expected %v got %q %v", tt.raw, tt.err, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: expected %q got %q %v", tt.raw, tt.want, got, err)
+		}
+	}
+}
+
+func TestValidateKeepsStoredContactDetails(t *testing.T) {
+	t.Parallel()
+
+	base := member.Member{ID: "m-1", Name: "Ann", JoinedAt: time.Now(), Status: member.StatusActive}
+
+	tests := []struct {
+		name  string
+		email string
+		phone string
+	}{
+		{name: "none"},
+		{name: "e164", email: "ann@example.com", phone: "+33612345678"},
+		{name: "national", phone: "555 0101"},
+		{name: "free-form email", email: "ann at example"},
+		{name: "phone with extension", phone: "555-0101 ext 2"},
+	}
+
+	for _, tt := range tests {
+		m := base
+		m.Email, m.Phone = tt.email, tt.phone
+		if err := m.Validate(); err != nil {
+			t.Fatalf("%s: expected stored contact details to validate got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package member
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// DuplicateThreshold is the similarity from which two members are listed
+// as likely the same person.
+const DuplicateThreshold = 0.8
+
+// Field weights of Similarity. Only fields filled in on both records count.
+const (
+	nameWeight  = 0.4
+	emailWeight = 0.35
+	phoneWeight = 0.25
+)
+
+// phoneDigits is how many trailing digits of a phone number are compared,
+// so that country codes and trunk prefixes do not matter.
+const phoneDigits = 9
+
+// Duplicate is a pair of members that look like the same person. Keep is
+// the record a merge would keep: the active one, else the one that joined
+// first.
+type Duplicate struct {
+	Keep    Member
+	Merge   Member
+	Score   float64
+	Reasons []string
+}
+
+// nameKey lowercases a name, drops punctuation and sorts its words, so
+// "Smith, Joe" and "joe smith" compare equal.
+func nameKey(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// emailKey lowercases an address and drops a "+tag" from the local part;
+// Gmail addresses also lose the dots Gmail ignores.
+func emailKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok {
+		return s
+	}
+
+	local, _, _ = strings.Cut(local, "+")
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// phoneKey keeps the last digits of a phone number.
+func phoneKey(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	digits := b.String()
+	if len(digits) > phoneDigits {
+		digits = digits[len(digits)-phoneDigits:]
+	}
+	return digits
+}
+
+// Similarity scores how alike two members are from 0 to 1 and says which
+// fields matched.
+func Similarity(a, b Member) (float64, []string) {
+	var (
+		score, weight float64
+		reasons       []string
+	)
+
+	compare := func(field string, w float64, x, y string, fuzzy bool) {
+		if x == "" || y == "" {
+			return
+		}
+
+		s := 0.0
+		switch {
+		case x == y:
+			s = 1
+			reasons = append(reasons, "same "+field)
+		case fuzzy:
+			s = ratio(x, y)
+			if s >= DuplicateThreshold {
+				reasons = append(reasons, "similar "+field)
+			}
+		}
+		score += w * s
+		weight += w
+	}
+
+	compare("name", nameWeight, nameKey(a.Name), nameKey(b.Name), true)
+	compare("email", emailWeight, emailKey(a.Email), emailKey(b.Email), true)
+	compare("phone", phoneWeight, phoneKey(a.Phone), phoneKey(b.Phone), false)
+
+	if weight == 0 {
+		return 0, nil
+	}
+	return score / weight, reasons
+}
+
+// FindDuplicates pairs up members scoring at least threshold, best match
+// first. Members already merged into another are left out.
+func FindDuplicates(members []Member, threshold float64) []Duplicate {
+	var out []Duplicate
+	for i, a := range members {
+		if a.MergedInto != "" {
+			continue
+		}
+		for _, b := range members[i+1:] {
+			if b.MergedInto != "" {
+				continue
+			}
+
+			score, reasons := Similarity(a, b)
+			if score < threshold {
+				continue
+			}
+
+			keep, merge := a, b
+			if prefer(b, a) {
+				keep, merge = b, a
+			}
+			out = append(out, Duplicate{Keep: keep, Merge: merge, Score: score, Reasons: reasons})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Keep.ID != out[j].Keep.ID {
+			return out[i].Keep.ID < out[j].Keep.ID
+		}
+		return out[i].Merge.ID < out[j].Merge.ID
+	})
+	return out
+}
+
+// Merge folds from into into. The surviving record picks up the contact
+// details it lacks; from is made inactive and remembers where it went.
+func Merge(from, into Member) (Member, Member, error) {
+	if from.ID == into.ID {
+		return Member{}, Member{}, errors.New("cannot merge a member into itself")
+	}
+	for _, m := range []Member{from, into} {
+		if m.MergedInto != "" {
+			return Member{}, Member{}, fmt.Errorf("member %s was already merged into %s", m.ID, m.MergedInto)
+		}
+	}
+
+	if strings.TrimSpace(into.Email) == "" {
+		into.Email = from.Email
+	}
+	if strings.TrimSpace(into.Phone) == "" {
+		into.Phone = from.Phone
+	}
+	if strings.TrimSpace(into.Type) == "" {
+		into.Type = from.Type
+	}
+
+	from.Status = StatusInactive
+	from.MergedInto = into.ID
+	return from, into, nil
+}
+
+func prefer(a, b Member) bool {
+	if (a.Status == StatusActive) != (b.Status == StatusActive) {
+		return a.Status == StatusActive
+	}
+	if !a.JoinedAt.Equal(b.JoinedAt) {
+		return a.JoinedAt.Before(b.JoinedAt)
+	}
+	return a.ID < b.ID
+}
+
+// ratio is one minus the edit distance between a and b relative to the
+// longer of the two.
+func ratio(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(shared.Distance(a, b, longest))/float64(longest)
+}
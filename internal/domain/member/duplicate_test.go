@@ -0,0 +1,99 @@
+package member_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+func TestSimilarity(t *testing.T) {
+	t.Parallel()
+
+	joe := member.Member{ID: "m-1", Name: "Joe Smith", Email: "joe.smith@gmail.com", Phone: "+33 6 12 34 56 78"}
+
+	tests := []struct {
+		name    string
+		other   member.Member
+		dup     bool
+		reasons []string
+	}{
+		{
+			name:    "gmail dots, tag and reordered name",
+			other:   member.Member{Name: "SMITH, Joe", Email: "JoeSmith+library@googlemail.com"},
+			dup:     true,
+			reasons: []string{"same name", "same email"},
+		},
+		{
+			name:    "typo in email, phone with trunk prefix",
+			other:   member.Member{Name: "Joe Smith", Email: "joe.smiht@gmail.com", Phone: "06 12 34 56 78"},
+			dup:     true,
+			reasons: []string{"same name", "similar email", "same phone"},
+		},
+		{
+			name:    "misspelt name only",
+			other:   member.Member{Name: "Joe Smyth"},
+			dup:     true,
+			reasons: []string{"similar name"},
+		},
+		{
+			name:    "same name, different person",
+			other:   member.Member{Name: "Joe Smith", Email: "jsmith@work.example", Phone: "555 0100"},
+			dup:     false,
+			reasons: []string{"same name"},
+		},
+		{
+			name:    "shared family phone",
+			other:   member.Member{Name: "Ann Smith", Phone: "0612345678"},
+			dup:     false,
+			reasons: []string{"same phone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			score, reasons := member.Similarity(joe, tt.other)
+			if dup := score >= member.DuplicateThreshold; dup != tt.dup {
+				t.Fatalf("expected duplicate=%v, got score %.2f", tt.dup, score)
+			}
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Fatalf("expected reasons %v got %v", tt.reasons, reasons)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesAndMerge(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	members := []member.Member{
+		{ID: "m-1", Name: "Joe Smith", JoinedAt: day(5), Status: member.StatusActive},
+		{ID: "m-2", Name: "joe smith", Email: "joe@example.com", JoinedAt: day(1), Status: member.StatusActive},
+		{ID: "m-3", Name: "Ann Lee", JoinedAt: day(1), Status: member.StatusActive},
+		{ID: "m-4", Name: "Ann Lee", JoinedAt: day(1), Status: member.StatusInactive, MergedInto: "m-3"},
+	}
+
+	dups := member.FindDuplicates(members, member.DuplicateThreshold)
+	if len(dups) != 1 || dups[0].Keep.ID != "m-2" || dups[0].Merge.ID != "m-1" {
+		t.Fatalf("expected m-1 to merge into the older m-2, got %+v", dups)
+	}
+
+	merged, kept, err := member.Merge(members[1], members[0])
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if merged.Status != member.StatusInactive || merged.MergedInto != "m-1" || kept.Email != "joe@example.com" {
+		t.Fatalf("unexpected merge result %+v %+v", merged, kept)
+	}
+
+	if _, _, err := member.Merge(members[3], members[2]); err == nil {
+		t.Fatalf("expected an already merged member to be refused")
+	}
+	if _, _, err := member.Merge(members[0], members[0]); err == nil {
+		t.Fatalf("expected merging a member into itself to be refused")
+	}
+}
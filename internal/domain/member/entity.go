@@ -2,8 +2,11 @@ package member
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
 type Status string
@@ -14,15 +17,59 @@ const (
 	StatusBlocked  Status = "blocked"
 )
 
+// Member is a registered patron. CardNumber is the barcode on the library
+// card; a zero ExpiresAt means the membership does not lapse. MergedInto is
+// set on a duplicate record once it has been folded into another member.
+// Blocks says why a blocked member may not borrow.
 type Member struct {
-	ID       string
-	Name     string
-	Email    string
-	Phone    string
-	JoinedAt time.Time
-	Status   Status
+	ID         string
+	CardNumber string
+	Name       string
+	Email      string
+	Phone      string
+	Type       string
+	JoinedAt   time.Time
+	ExpiresAt  time.Time
+	Status     Status
+	PINHash    string
+	MergedInto string
+	Blocks     []Block
+}
+
+// Policy sets the membership term, how card numbers are generated and
+// which contact details must be unique. A zero TermDays gives memberships
+// without an expiry date. PhoneCountryCode is the calling code given
+// to phone numbers entered without one.
+type Policy struct {
+	TermDays         int
+	CardPrefix       string
+	CardDigits       int
+	CardStart        int64
+	UniqueCard       bool
+	UniqueEmail      bool
+	PhoneCountryCode string
+}
+
+// Card formats the n-th card number of the sequence.
+func (p Policy) Card(n int64) string {
+	return fmt.Sprintf("%s%0*d", p.CardPrefix, p.CardDigits, n)
+}
+
+func (p Policy) SequenceName() string {
+	return "card:" + p.CardPrefix
 }
 
+// ExpiryFrom is the end of a term starting at t, or zero without a term.
+func (p Policy) ExpiryFrom(t time.Time) time.Time {
+	if p.TermDays <= 0 {
+		return time.Time{}
+	}
+	return t.AddDate(0, 0, p.TermDays)
+}
+
+// Validate checks the fields every stored member must have. Email and phone
+// syntax is checked where they are entered, as records saved before those
+// checks keep their free-form contact details.
 func (m Member) Validate() error {
 	if strings.TrimSpace(m.ID) == "" {
 		return errors.New("member id is required")
@@ -40,9 +87,54 @@ func (m Member) Validate() error {
 		return errors.New("member status is required")
 	}
 
+	for _, b := range m.Blocks {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (m Member) CanBorrow() bool {
-	return m.Status == StatusActive
+func (m Member) IsExpired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
+}
+
+// CanBorrow reports why the member may not borrow or hold items at now,
+// or nil when they may. A block's reason is part of the error. Expiry is
+// checked before the status, as the expiry job leaves lapsed members
+// inactive and they should be told to renew.
+func (m Member) CanBorrow(now time.Time) error {
+	if m.IsBlocked(now) {
+		return fmt.Errorf("%w: blocked: %s", shared.ErrMemberNotEligible, m.BlockReason(now))
+	}
+	if m.IsExpired(now) {
+		return shared.ErrMembershipExpired
+	}
+	if m.Status != StatusActive && m.Status != StatusBlocked {
+		return shared.ErrMemberNotEligible
+	}
+	return nil
+}
+
+// Renew extends the membership by one term from its expiry date, or from
+// now when it has already lapsed, and reactivates a member the expiry job
+// made inactive. Blocked members stay blocked.
+func Renew(m Member, now time.Time, p Policy) (Member, error) {
+	if p.TermDays <= 0 {
+		return Member{}, errors.New("membership term is not set")
+	}
+	if m.MergedInto != "" {
+		return Member{}, fmt.Errorf("member was merged into %s", m.MergedInto)
+	}
+
+	from := m.ExpiresAt
+	if from.IsZero() || from.Before(now) {
+		from = now
+	}
+	m.ExpiresAt = p.ExpiryFrom(from)
+	if m.Status == StatusInactive {
+		m.Status = StatusActive
+	}
+	return m, nil
 }
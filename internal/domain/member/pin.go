@@ -0,0 +1,82 @@
+package member
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	pinMinLength  = 4
+	pinMaxLength  = 8
+	pinIterations = 100_000
+	pinKeyLength  = 32
+	pinScheme     = "pbkdf2-sha256"
+)
+
+var (
+	ErrPINFormat = errors.New("pin must be 4 to 8 digits")
+	ErrPINNotSet = errors.New("member has no pin")
+)
+
+func ValidatePIN(pin string) error {
+	if len(pin) < pinMinLength || len(pin) > pinMaxLength {
+		return ErrPINFormat
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return ErrPINFormat
+		}
+	}
+	return nil
+}
+
+// HashPIN derives a PBKDF2-SHA256 key from the PIN and salt and encodes it
+// as "pbkdf2-sha256$iterations$salt$key" so the parameters travel with it.
+func HashPIN(pin string, salt []byte) (string, error) {
+	if err := ValidatePIN(pin); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, pin, salt, pinIterations, pinKeyLength)
+	if err != nil {
+		return "", fmt.Errorf("derive pin key: %w", err)
+	}
+
+	enc := base64.RawStdEncoding
+	return strings.Join([]string{pinScheme, strconv.Itoa(pinIterations), enc.EncodeToString(salt), enc.EncodeToString(key)}, "$"), nil
+}
+
+func (m Member) HasPIN() bool {
+	return m.PINHash != ""
+}
+
+func (m Member) CheckPIN(pin string) bool {
+	parts := strings.Split(m.PINHash, "$")
+	if len(parts) != 4 || parts[0] != pinScheme {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+
+	enc := base64.RawStdEncoding
+	salt, errSalt := enc.DecodeString(parts[2])
+	want, errKey := enc.DecodeString(parts[3])
+	if errSalt != nil || errKey != nil {
+		return false
+	}
+
+	got, err := pbkdf2.Key(sha256.New, pin, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
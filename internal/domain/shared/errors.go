@@ -6,10 +6,48 @@ var (
 	ErrNotFound           = errors.New("not found")
 	ErrDuplicateID        = errors.New("id already exists")
 	ErrDuplicateBarcode   = errors.New("barcode already exists")
+	ErrDuplicateISBN      = errors.New("isbn already exists")
 	ErrCopyNotAvailable   = errors.New("copy is not available")
 	ErrMemberNotEligible  = errors.New("member is not eligible to borrow")
+	ErrMembershipExpired  = errors.New("membership has expired")
+	ErrDuplicateCard      = errors.New("card number already exists")
+	ErrDuplicateEmail     = errors.New("email address already exists")
 	ErrLoanLimitReached   = errors.New("member has reached active loan limit")
 	ErrLoanAlreadyClosed  = errors.New("loan is already returned")
 	ErrRenewalLimit       = errors.New("renewal limit reached")
 	ErrLoanAlreadyOverdue = errors.New("overdue loan cannot be renewed")
+	ErrOverrideNotAllowed = errors.New("only supervisors may override loan rules")
+	ErrDuplicateHold      = errors.New("member already has a hold on this title")
+	ErrHoldNotAllowed     = errors.New("title cannot be placed on hold")
+	ErrHoldClosed         = errors.New("hold is no longer waiting")
+	ErrInvalidCredentials = errors.New("invalid member id or pin")
+	ErrDeliveryNotFailed  = errors.New("only failed deliveries can be retried")
+	ErrStocktakeClosed    = errors.New("stocktake session is closed")
+	ErrDuplicateBranch    = errors.New("branch already exists")
+	ErrTransferState      = errors.New("transfer cannot move to that state")
+	ErrAlreadyAtBranch    = errors.New("copy is already at that branch")
+	ErrTransferOpen       = errors.New("copy already has an open transfer")
 )
+
+var ErrInvalidInput = errors.New("invalid input")
+
+// ValidationError marks a rejected field value while keeping the original
+// message, so callers can match ErrInvalidInput without losing detail.
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() []error {
+	return []error{ErrInvalidInput, e.Err}
+}
+
+func Invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+	return ValidationError{Err: err}
+}
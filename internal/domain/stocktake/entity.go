@@ -0,0 +1,79 @@
+package stocktake
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+type Scan struct {
+	Barcode string
+	Shelf   string
+	At      time.Time
+}
+
+// Session is one shelf check. Scans are kept in the order they were taken;
+// scanning the same barcode twice records both. A session with a Branch only
+// checks the copies currently at that branch.
+type Session struct {
+	ID        string
+	Name      string
+	Branch    string
+	StartedAt time.Time
+	ClosedAt  *time.Time
+	Status    Status
+	Scans     []Scan
+}
+
+func (s Session) Validate() error {
+	if strings.TrimSpace(s.ID) == "" {
+		return errors.New("stocktake id is required")
+	}
+
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("stocktake name is required")
+	}
+
+	if s.StartedAt.IsZero() {
+		return errors.New("stocktake start date is required")
+	}
+
+	switch s.Status {
+	case StatusOpen, StatusClosed:
+	default:
+		return errors.New("stocktake status is invalid")
+	}
+
+	for _, sc := range s.Scans {
+		if strings.TrimSpace(sc.Barcode) == "" {
+			return errors.New("scanned barcode is required")
+		}
+	}
+
+	return nil
+}
+
+func (s Session) IsOpen() bool {
+	return s.Status == StatusOpen
+}
+
+// Covers reports whether a copy at branch is on the shelves being checked.
+func (s Session) Covers(branch string) bool {
+	return s.Branch == "" || s.Branch == branch
+}
+
+// Seen returns the shelf each barcode was last scanned on.
+func (s Session) Seen() map[string]string {
+	out := make(map[string]string, len(s.Scans))
+	for _, sc := range s.Scans {
+		out[sc.Barcode] = sc.Shelf
+	}
+	return out
+}
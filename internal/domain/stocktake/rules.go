@@ -0,0 +1,33 @@
+package stocktake
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+func AddScan(s Session, barcode, shelf string, at time.Time) (Session, error) {
+	if !s.IsOpen() {
+		return Session{}, shared.ErrStocktakeClosed
+	}
+
+	barcode = strings.TrimSpace(barcode)
+	if barcode == "" {
+		return Session{}, shared.Invalid(errors.New("barcode is required"))
+	}
+
+	s.Scans = append(append([]Scan(nil), s.Scans...), Scan{Barcode: barcode, Shelf: strings.TrimSpace(shelf), At: at})
+	return s, nil
+}
+
+func Close(s Session, now time.Time) (Session, error) {
+	if !s.IsOpen() {
+		return Session{}, shared.ErrStocktakeClosed
+	}
+
+	s.Status = StatusClosed
+	s.ClosedAt = &now
+	return s, nil
+}
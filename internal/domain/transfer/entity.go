@@ -0,0 +1,60 @@
+package transfer
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusRequested Status = "requested"
+	StatusInTransit Status = "in_transit"
+	StatusReceived  Status = "received"
+	StatusCancelled Status = "cancelled"
+)
+
+// Transfer moves a copy from one branch to another. Returns at a branch
+// other than the copy's home start in transit with Return set.
+type Transfer struct {
+	ID          string
+	CopyID      string
+	From        string
+	To          string
+	Status      Status
+	Return      bool
+	RequestedAt time.Time
+	ShippedAt   *time.Time
+	ClosedAt    *time.Time
+}
+
+func (t Transfer) Validate() error {
+	if strings.TrimSpace(t.ID) == "" {
+		return errors.New("transfer id is required")
+	}
+
+	if strings.TrimSpace(t.CopyID) == "" {
+		return errors.New("copy id is required")
+	}
+
+	if strings.TrimSpace(t.To) == "" {
+		return errors.New("destination branch is required")
+	}
+
+	if t.RequestedAt.IsZero() {
+		return errors.New("transfer request date is required")
+	}
+
+	switch t.Status {
+	case StatusRequested, StatusInTransit, StatusReceived, StatusCancelled:
+	default:
+		return errors.New("transfer status is invalid")
+	}
+
+	return nil
+}
+
+func (t Transfer) IsOpen() bool {
+	return t.Status == StatusRequested || t.Status == StatusInTransit
+}
@@ -0,0 +1,52 @@
+package transfer
+
+import (
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// Request starts a transfer of an available copy to another branch. The
+// copy stays on the shelf until it is shipped.
+func Request(id string, c copy.Copy, to string, now time.Time) (Transfer, error) {
+	if !c.IsAvailable() {
+		return Transfer{}, shared.ErrCopyNotAvailable
+	}
+
+	if c.At() == to {
+		return Transfer{}, shared.ErrAlreadyAtBranch
+	}
+
+	return Transfer{ID: id, CopyID: c.ID, From: c.At(), To: to, Status: StatusRequested, RequestedAt: now}, nil
+}
+
+func Ship(t Transfer, now time.Time) (Transfer, error) {
+	if t.Status != StatusRequested {
+		return Transfer{}, shared.ErrTransferState
+	}
+
+	t.Status = StatusInTransit
+	t.ShippedAt = &now
+	return t, nil
+}
+
+func Receive(t Transfer, now time.Time) (Transfer, error) {
+	if t.Status != StatusInTransit {
+		return Transfer{}, shared.ErrTransferState
+	}
+
+	t.Status = StatusReceived
+	t.ClosedAt = &now
+	return t, nil
+}
+
+func Cancel(t Transfer, now time.Time) (Transfer, error) {
+	if t.Status != StatusRequested {
+		return Transfer{}, shared.ErrTransferState
+	}
+
+	t.Status = StatusCancelled
+	t.ClosedAt = &now
+	return t, nil
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+)
+
+const MinSecretLength = 16
+
+// Supported lists the event types that can be delivered to webhook
+// endpoints.
+var Supported = []event.Type{
+	event.TypeLoanIssued,
+	event.TypeLoanRenewed,
+	event.TypeLoanReturned,
+	event.TypeLoanOverridden,
+	event.TypeMemberStatusChanged,
+	event.TypeMemberMerged,
+}
+
+type Endpoint struct {
+	ID        string
+	URL       string
+	Secret    string
+	Events    []event.Type
+	CreatedAt time.Time
+}
+
+func (e Endpoint) Validate() error {
+	if strings.TrimSpace(e.ID) == "" {
+		return errors.New("webhook id is required")
+	}
+
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+
+	if len(e.Secret) < MinSecretLength {
+		return fmt.Errorf("webhook secret must be at least %d characters", MinSecretLength)
+	}
+
+	if len(e.Events) == 0 {
+		return errors.New("webhook needs at least one event")
+	}
+
+	for _, t := range e.Events {
+		if !slices.Contains(Supported, t) {
+			return fmt.Errorf("webhook event %q is not supported", t)
+		}
+	}
+
+	if e.CreatedAt.IsZero() {
+		return errors.New("webhook created date is required")
+	}
+
+	return nil
+}
+
+func (e Endpoint) Wants(t event.Type) bool {
+	return slices.Contains(e.Events, t)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+type Delivery struct {
+	ID            string
+	EndpointID    string
+	EventID       string
+	EventType     event.Type
+	Payload       json.RawMessage
+	Status        DeliveryStatus
+	Attempts      int
+	LastCode      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+func (d Delivery) Validate() error {
+	if strings.TrimSpace(d.ID) == "" {
+		return errors.New("delivery id is required")
+	}
+
+	if strings.TrimSpace(d.EndpointID) == "" {
+		return errors.New("delivery endpoint id is required")
+	}
+
+	if strings.TrimSpace(d.EventID) == "" {
+		return errors.New("delivery event id is required")
+	}
+
+	if len(d.Payload) == 0 {
+		return errors.New("delivery payload is required")
+	}
+
+	switch d.Status {
+	case DeliveryPending, DeliveryDelivered, DeliveryFailed:
+	default:
+		return errors.New("delivery status is invalid")
+	}
+
+	if d.CreatedAt.IsZero() {
+		return errors.New("delivery created date is required")
+	}
+
+	return nil
+}
+
+func (d Delivery) Due(now time.Time) bool {
+	return d.Status == DeliveryPending && !now.Before(d.NextAttemptAt)
+}
+
+// Outcome summarises the last attempt for the delivery log.
+func (d Delivery) Outcome() string {
+	switch {
+	case d.LastError != "":
+		return d.LastError
+	case d.Attempts == 0:
+		return "-"
+	default:
+		return strconv.Itoa(d.LastCode)
+	}
+}
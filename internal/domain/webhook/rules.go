@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// Backoff is the wait before each retry; a delivery is given up after the
+// first attempt plus one retry per step.
+var Backoff = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+}
+
+func MaxAttempts() int {
+	return len(Backoff) + 1
+}
+
+func RecordSuccess(d Delivery, code int, now time.Time) Delivery {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.LastCode = code
+	d.LastError = ""
+	d.DeliveredAt = &now
+	return d
+}
+
+func RecordFailure(d Delivery, code int, reason string, now time.Time) Delivery {
+	d.Attempts++
+	d.LastCode = code
+	d.LastError = reason
+
+	if d.Attempts >= MaxAttempts() {
+		d.Status = DeliveryFailed
+		return d
+	}
+
+	d.NextAttemptAt = now.Add(Backoff[d.Attempts-1])
+	return d
+}
+
+// Retry puts a failed delivery back in the queue with a fresh attempt budget.
+func Retry(d Delivery, now time.Time) (Delivery, error) {
+	if d.Status != DeliveryFailed {
+		return Delivery{}, shared.ErrDeliveryNotFailed
+	}
+
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	return d, nil
+}
+
+// Sign returns the X-LMS-Signature value for a payload: a hex HMAC-SHA256 of
+// "<unix timestamp>.<body>" keyed with the endpoint secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,130 @@
+// Package document renders loan receipts and overdue letters from text
+// templates, either as plain text or as a PDF set in Courier.
+package document
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/pdf"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Local().Format("2 Jan 2006") },
+	"datetime": func(t time.Time) string { return t.Local().Format("2 Jan 2006 15:04") },
+	"pad": func(s string, n int) string {
+		if w := utf8.RuneCountInString(s); w < n {
+			return s + strings.Repeat(" ", n-w)
+		}
+		return s
+	},
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// PDF page geometry: Courier advances 0.6 em, so 10 pt text fits 84
+// columns between 54 pt margins on a Letter page.
+const (
+	fontSize   = 10.0
+	lineHeight = 12.0
+	margin     = 54.0
+	columns    = 84
+)
+
+// Receipt writes a checkout or return receipt in format, "text" or "pdf".
+func Receipt(w io.Writer, format, library string, r dto.Receipt) error {
+	name := "checkout.tmpl"
+	if r.Kind == dto.ReceiptReturn {
+		name = "return.tmpl"
+	}
+
+	text, err := render(name, library, r.Member, r.Items, r.At)
+	if err != nil {
+		return err
+	}
+	return write(w, format, []string{text})
+}
+
+// OverdueNotices writes one letter per notice. In a PDF every letter starts
+// on a new page; in text they are separated by a form feed.
+func OverdueNotices(w io.Writer, format, library string, notices []dto.OverdueNotice) error {
+	letters := make([]string, 0, len(notices))
+	for _, n := range notices {
+		text, err := render("overdue.tmpl", library, n.Member, n.Items, n.At)
+		if err != nil {
+			return err
+		}
+		letters = append(letters, text)
+	}
+	return write(w, format, letters)
+}
+
+func render(name, library string, m member.Member, items []dto.DocumentItem, at time.Time) (string, error) {
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, name, struct {
+		Library string
+		Member  member.Member
+		Items   []dto.DocumentItem
+		At      time.Time
+	}{library, m, items, at})
+	return buf.String(), err
+}
+
+func write(w io.Writer, format string, docs []string) error {
+	switch strings.ToLower(format) {
+	case "text", "txt":
+		_, err := io.WriteString(w, strings.Join(docs, "\f\n"))
+		return err
+	case "pdf":
+		return writePDF(w, docs)
+	default:
+		return fmt.Errorf("unsupported document format %q", format)
+	}
+}
+
+func writePDF(w io.Writer, docs []string) error {
+	doc := pdf.New(pdf.LetterWidth, pdf.LetterHeight)
+	perPage := int((pdf.LetterHeight - 2*margin) / lineHeight)
+	for _, text := range docs {
+		lines := wrap(strings.Split(strings.TrimRight(text, "\n"), "\n"))
+		for start := 0; start < len(lines); start += perPage {
+			page := doc.AddPage()
+			for i, line := range lines[start:min(start+perPage, len(lines))] {
+				page.Text(margin, margin+fontSize+float64(i)*lineHeight, pdf.Courier, fontSize, line)
+			}
+		}
+	}
+	return doc.Write(w)
+}
+
+// wrap breaks lines longer than the page is wide, keeping the indent of the
+// original line on the continuation.
+func wrap(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent >= columns/2 {
+			indent = 0
+		}
+		for utf8.RuneCountInString(line) > columns {
+			runes := []rune(line)
+			cut := columns
+			if i := strings.LastIndex(string(runes[:columns]), " "); i > indent {
+				cut = utf8.RuneCountInString(string(runes[:columns])[:i])
+			}
+			out = append(out, strings.TrimRight(string(runes[:cut]), " "))
+			line = strings.Repeat(" ", indent) + strings.TrimLeft(string(runes[cut:]), " ")
+		}
+		out = append(out, line)
+	}
+	return out
+}
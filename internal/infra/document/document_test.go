@@ -0,0 +1,120 @@
+package document_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/document"
+)
+
+func TestReceipt(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local)
+	returned := at.Add(-time.Hour)
+	joe := member.Member{ID: "m-1", Name: "Joe"}
+
+	tests := []struct {
+		name    string
+		receipt dto.Receipt
+		want    []string
+	}{
+		{
+			name: "checkout",
+			receipt: dto.Receipt{Kind: dto.ReceiptCheckout, Member: joe, At: at, Items: []dto.DocumentItem{
+				{LoanID: "l-1", Barcode: "C001", Title: "Go", DueAt: at.AddDate(0, 0, 14)},
+				{LoanID: "l-2", Barcode: "C002", Title: "Rust", DueAt: at.AddDate(0, 0, 14)},
+			}},
+			want: []string{"Test Library", "CHECKOUT RECEIPT", "20 Mar 2026 12:00", "Member: Joe (m-1)", "C001           Go", "Due 3 Apr 2026", "Items borrowed: 2"},
+		},
+		{
+			name: "return",
+			receipt: dto.Receipt{Kind: dto.ReceiptReturn, Member: joe, At: at, Items: []dto.DocumentItem{
+				{LoanID: "l-1", Barcode: "C001", Title: "Go", ReturnedAt: &returned, DaysOverdue: 1},
+			}},
+			want: []string{"RETURN RECEIPT", "Returned 20 Mar 2026 (1 day late)", "Items returned: 1"},
+		},
+		{
+			name: "short loans",
+			receipt: dto.Receipt{Kind: dto.ReceiptCheckout, Member: joe, At: at, Items: []dto.DocumentItem{
+				{LoanID: "l-1", Barcode: "C001", Title: "Go", DueAt: at.Add(2 * time.Hour), Short: true},
+			}},
+			want: []string{"Due 20 Mar 2026 14:00"},
+		},
+		{
+			name: "short loan returned late",
+			receipt: dto.Receipt{Kind: dto.ReceiptReturn, Member: joe, At: at, Items: []dto.DocumentItem{
+				{LoanID: "l-1", Barcode: "C001", Title: "Go", ReturnedAt: &returned, DaysOverdue: 1, Short: true, HoursOverdue: 3},
+			}},
+			want: []string{"Returned 20 Mar 2026 (3 hours late)"},
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := document.Receipt(&buf, "text", "Test Library", tt.receipt); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Fatalf("%s: expected %q in:\n%s", tt.name, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestOverdueNotices(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local)
+	notices := []dto.OverdueNotice{
+		{Member: member.Member{ID: "m-1", Name: "Joe", Email: "joe@example.com"}, At: at, Items: []dto.DocumentItem{
+			{Barcode: "C001", Title: "A very long title " + strings.Repeat("that keeps going ", 8), DueAt: at.AddDate(0, 0, -5), DaysOverdue: 5},
+		}},
+		{Member: member.Member{ID: "m-2", Name: "Ann"}, At: at, Items: []dto.DocumentItem{
+			{Barcode: "C002", Title: "Go", DueAt: at.AddDate(0, 0, -1), DaysOverdue: 1},
+			{Barcode: "C003", Title: "Rust (2nd ed.)", DueAt: at.AddDate(0, 0, -1), DaysOverdue: 1},
+		}},
+	}
+
+	var text bytes.Buffer
+	if err := document.OverdueNotices(&text, "text", "Test Library", notices); err != nil {
+		t.Fatalf("text: %v", err)
+	}
+	letters := strings.Split(text.String(), "\f\n")
+	if len(letters) != 2 {
+		t.Fatalf("expected two letters, got %d", len(letters))
+	}
+	for _, want := range []string{"Dear Joe,", "joe@example.com", "following item is overdue", "5 days overdue"} {
+		if !strings.Contains(letters[0], want) {
+			t.Fatalf("expected %q in:\n%s", want, letters[0])
+		}
+	}
+	if !strings.Contains(letters[1], "following 2 items are overdue") || !strings.Contains(letters[1], "1 day overdue") {
+		t.Fatalf("unexpected second letter:\n%s", letters[1])
+	}
+
+	var pdf bytes.Buffer
+	if err := document.OverdueNotices(&pdf, "PDF", "Test Library", notices); err != nil {
+		t.Fatalf("pdf: %v", err)
+	}
+	out := pdf.String()
+	for _, want := range []string{"%PDF-1.4", "/Count 2", "/BaseFont /Courier", "(Dear Ann,) Tj", "(C003           Rust \\(2nd ed.\\)) Tj"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in pdf", want)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if i := strings.Index(line, " Td ("); i >= 0 && len(line)-i > 84+len(" Td () Tj ET")+4 {
+			t.Fatalf("expected long lines to wrap, got %q", line)
+		}
+	}
+
+	if err := document.OverdueNotices(&pdf, "html", "Test Library", notices); err == nil {
+		t.Fatalf("expected unsupported format error")
+	}
+}
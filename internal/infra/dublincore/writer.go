@@ -0,0 +1,86 @@
+package dublincore
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+const (
+	oaiDCNS = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	dcNS    = "http://purl.org/dc/elements/1.1/"
+)
+
+type collection struct {
+	XMLName xml.Name `xml:"collection"`
+	OAIDC   string   `xml:"xmlns:oai_dc,attr"`
+	DC      string   `xml:"xmlns:dc,attr"`
+	Records []record `xml:"oai_dc:dc"`
+}
+
+type record struct {
+	Identifiers []string `xml:"dc:identifier"`
+	Title       string   `xml:"dc:title"`
+	Creators    []string `xml:"dc:creator"`
+	Publisher   string   `xml:"dc:publisher,omitempty"`
+	Date        string   `xml:"dc:date,omitempty"`
+	Subject     string   `xml:"dc:subject,omitempty"`
+	Type        string   `xml:"dc:type"`
+	Description string   `xml:"dc:description,omitempty"`
+}
+
+func toRecord(h dto.Holding) record {
+	b := h.Book
+	rec := record{
+		Identifiers: []string{b.ID},
+		Title:       b.Title,
+		Creators:    b.Authors,
+		Publisher:   b.Publisher,
+		Subject:     b.Category,
+		Type:        "Text",
+		Description: holdingSummary(h.Copies),
+	}
+	if b.ISBN != "" {
+		rec.Identifiers = append(rec.Identifiers, "urn:isbn:"+b.ISBN)
+	}
+	if b.Year > 0 {
+		rec.Date = strconv.Itoa(b.Year)
+	}
+	return rec
+}
+
+func Write(w io.Writer, holdings []dto.Holding) error {
+	coll := collection{OAIDC: oaiDCNS, DC: dcNS}
+	for _, h := range holdings {
+		coll.Records = append(coll.Records, toRecord(h))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(coll); err != nil {
+		return fmt.Errorf("encode dublin core: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func holdingSummary(copies []copy.Copy) string {
+	if len(copies) == 0 {
+		return ""
+	}
+
+	available := 0
+	for _, c := range copies {
+		if c.Status == copy.StatusAvailable {
+			available++
+		}
+	}
+	return fmt.Sprintf("%d copies, %d available", len(copies), available)
+}
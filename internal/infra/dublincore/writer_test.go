@@ -0,0 +1,42 @@
+package dublincore_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/dublincore"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := dublincore.Write(&buf, []dto.Holding{{
+		Book: book.Book{ID: "b-1", Title: "Rock & Roll", Authors: []string{"Ann"}, ISBN: "9780306406157", Year: 2001},
+		Copies: []copy.Copy{
+			{ID: "c-1", Status: copy.StatusAvailable},
+			{ID: "c-2", Status: copy.StatusLoaned},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`xmlns:dc="http://purl.org/dc/elements/1.1/"`,
+		"<dc:title>Rock &amp; Roll</dc:title>",
+		"<dc:creator>Ann</dc:creator>",
+		"<dc:identifier>urn:isbn:9780306406157</dc:identifier>",
+		"<dc:date>2001</dc:date>",
+		"<dc:description>2 copies, 1 available</dc:description>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package label
+
+import (
+	"fmt"
+	"strings"
+)
+
+// code128Patterns holds the bar and space widths of every Code 128 symbol,
+// indexed by symbol value. Each pattern starts with a bar.
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Code128 encodes data as alternating bar and space widths in modules,
+// starting with a bar. Digit runs of four or more use code set C; anything
+// else printable ASCII uses code set B.
+func Code128(data string) ([]int, error) {
+	if data == "" {
+		return nil, fmt.Errorf("code 128: empty data")
+	}
+	for _, r := range data {
+		if r < 32 || r > 126 {
+			return nil, fmt.Errorf("code 128: unsupported character %q", r)
+		}
+	}
+
+	symbols := code128Symbols(data)
+
+	checksum := symbols[0]
+	for i, v := range symbols[1:] {
+		checksum += (i + 1) * v
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var widths []int
+	for _, v := range symbols {
+		for _, w := range code128Patterns[v] {
+			widths = append(widths, int(w-'0'))
+		}
+	}
+	return widths, nil
+}
+
+func code128Symbols(data string) []int {
+	var symbols []int
+	setC := false
+	for i := 0; i < len(data); {
+		run := digitRun(data[i:])
+		// Switching to C pays off for four or more digits; a run at the very
+		// start or end of the data only needs to be even.
+		useC := run >= 4 && (run%2 == 0 || i+run == len(data) || i == 0)
+		switch {
+		case useC && i == 0:
+			symbols = append(symbols, code128StartC)
+			setC = true
+		case useC && !setC:
+			symbols = append(symbols, code128CodeC)
+			setC = true
+		case i == 0:
+			symbols = append(symbols, code128StartB)
+		}
+
+		if setC {
+			pairs := run / 2
+			for k := 0; k < pairs; k++ {
+				symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+			}
+			if i < len(data) {
+				symbols = append(symbols, code128CodeB)
+				setC = false
+			}
+			continue
+		}
+
+		symbols = append(symbols, int(data[i])-32)
+		i++
+	}
+	return symbols
+}
+
+func digitRun(s string) int {
+	return len(s) - len(strings.TrimLeft(s, "0123456789"))
+}
@@ -0,0 +1,111 @@
+package label_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/label"
+)
+
+func TestCode128(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data    string
+		modules int
+		tail    string
+	}{
+		// Start B, seven characters, checksum 55 and stop.
+		{data: "PJJ123C", modules: 11*9 + 13, tail: "3113212331112"},
+		// Start B, C, switch to code C and three digit pairs.
+		{data: "C000123", modules: 11*7 + 13},
+		// An even digit string starts in code C.
+		{data: "00012345", modules: 11*6 + 13},
+	}
+
+	for _, tt := range tests {
+		widths, err := label.Code128(tt.data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.data, err)
+		}
+
+		sum := 0
+		var digits strings.Builder
+		for _, w := range widths {
+			sum += w
+			digits.WriteString(strconv.Itoa(w))
+		}
+		if sum != tt.modules {
+			t.Fatalf("%s: expected %d modules got %d", tt.data, tt.modules, sum)
+		}
+		if tt.tail != "" && !strings.HasSuffix(digits.String(), tt.tail) {
+			t.Fatalf("%s: expected pattern to end with %s got %s", tt.data, tt.tail, digits.String())
+		}
+	}
+
+	if _, err := label.Code128("café"); err == nil {
+		t.Fatalf("expected non-ASCII data to be rejected")
+	}
+}
+
+func TestWriteSheets(t *testing.T) {
+	t.Parallel()
+
+	layout, ok := label.LayoutByName("Avery-5160")
+	if !ok {
+		t.Fatalf("expected the avery-5160 layout")
+	}
+
+	labels := make([]dto.Label, layout.PerPage()+1)
+	for i := range labels {
+		labels[i] = dto.Label{Barcode: fmt.Sprintf("C%06d", i+1), Title: "Rock & Roll (Live)", CallNumber: "QA76.73 .G63"}
+	}
+
+	var svg bytes.Buffer
+	if err := label.Write(&svg, "svg", layout, label.KindBarcode, labels); err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	var doc struct {
+		Pages []struct {
+			Texts []string `xml:"text"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(svg.Bytes(), &doc); err != nil {
+		t.Fatalf("svg is not well-formed: %v", err)
+	}
+	if len(doc.Pages) != 2 || len(doc.Pages[1].Texts) != 3 {
+		t.Fatalf("expected a second sheet with one label, got %+v", doc.Pages)
+	}
+
+	var pdf bytes.Buffer
+	if err := label.Write(&pdf, "PDF", layout, label.KindSpine, labels[:2]); err != nil {
+		t.Fatalf("pdf: %v", err)
+	}
+	out := pdf.String()
+	for _, want := range []string{"%PDF-1.4", "/Count 1", "(QA) Tj", "(76.73) Tj", "(.G63) Tj", "%%EOF"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in pdf:\n%s", want, out)
+		}
+	}
+
+	// Every xref entry must point at the start of its object.
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(out)
+	xref, _ := strconv.Atoi(m[1])
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(out[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(e[1])
+		if !strings.HasPrefix(out[off:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Fatalf("xref entry %d points at %q", i+1, out[off:off+10])
+		}
+	}
+
+	if err := label.Write(&pdf, "png", layout, label.KindBarcode, labels); err == nil {
+		t.Fatalf("expected unsupported format error")
+	}
+}
@@ -0,0 +1,131 @@
+package label
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+// Kind selects what is printed on each label.
+type Kind string
+
+const (
+	// KindBarcode prints the title, a Code 128 barcode and the call number.
+	KindBarcode Kind = "barcode"
+	// KindSpine prints the call number broken into lines for a book spine.
+	KindSpine Kind = "spine"
+)
+
+const (
+	pointsPerInch = 72.0
+	pointsPerMM   = pointsPerInch / 25.4
+)
+
+// Layout describes a sheet of label stock. All sizes are in points and
+// measured from the top-left corner of the page.
+type Layout struct {
+	Name        string
+	Description string
+	PageWidth   float64
+	PageHeight  float64
+	Columns     int
+	Rows        int
+	Left        float64
+	Top         float64
+	Width       float64
+	Height      float64
+	GapX        float64
+	GapY        float64
+}
+
+var layouts = map[string]Layout{
+	"avery-5160": {
+		Name:        "avery-5160",
+		Description: "US Letter, 3 x 10 address labels, 2.625 x 1 in",
+		PageWidth:   8.5 * pointsPerInch,
+		PageHeight:  11 * pointsPerInch,
+		Columns:     3,
+		Rows:        10,
+		Left:        0.1875 * pointsPerInch,
+		Top:         0.5 * pointsPerInch,
+		Width:       2.625 * pointsPerInch,
+		Height:      1 * pointsPerInch,
+		GapX:        0.125 * pointsPerInch,
+	},
+	"avery-l7160": {
+		Name:        "avery-l7160",
+		Description: "A4, 3 x 7 labels, 63.5 x 38.1 mm",
+		PageWidth:   210 * pointsPerMM,
+		PageHeight:  297 * pointsPerMM,
+		Columns:     3,
+		Rows:        7,
+		Left:        7.2 * pointsPerMM,
+		Top:         15.15 * pointsPerMM,
+		Width:       63.5 * pointsPerMM,
+		Height:      38.1 * pointsPerMM,
+		GapX:        2.5 * pointsPerMM,
+	},
+	"spine-1x1.5": {
+		Name:        "spine-1x1.5",
+		Description: "US Letter, 7 x 6 spine labels, 1 x 1.5 in",
+		PageWidth:   8.5 * pointsPerInch,
+		PageHeight:  11 * pointsPerInch,
+		Columns:     7,
+		Rows:        6,
+		Left:        0.375 * pointsPerInch,
+		Top:         0.6875 * pointsPerInch,
+		Width:       1 * pointsPerInch,
+		Height:      1.5 * pointsPerInch,
+		GapX:        0.125 * pointsPerInch,
+		GapY:        0.125 * pointsPerInch,
+	},
+}
+
+// Write renders labels in format, "pdf" or "svg".
+func Write(w io.Writer, format string, l Layout, kind Kind, labels []dto.Label) error {
+	switch kind {
+	case KindBarcode, KindSpine:
+	default:
+		return fmt.Errorf("unsupported label kind %q", kind)
+	}
+
+	switch strings.ToLower(format) {
+	case "pdf":
+		return WritePDF(w, l, kind, labels)
+	case "svg":
+		return WriteSVG(w, l, kind, labels)
+	default:
+		return fmt.Errorf("unsupported label format %q", format)
+	}
+}
+
+// LayoutByName looks up a layout, ignoring case.
+func LayoutByName(name string) (Layout, bool) {
+	l, ok := layouts[strings.ToLower(strings.TrimSpace(name))]
+	return l, ok
+}
+
+// Layouts returns every layout sorted by name.
+func Layouts() []Layout {
+	out := make([]Layout, 0, len(layouts))
+	for _, l := range layouts {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// PerPage is the number of labels on one sheet.
+func (l Layout) PerPage() int {
+	return l.Columns * l.Rows
+}
+
+// origin returns the top-left corner of the i-th label on its page.
+func (l Layout) origin(i int) (float64, float64) {
+	i %= l.PerPage()
+	col, row := i%l.Columns, i/l.Columns
+	return l.Left + float64(col)*(l.Width+l.GapX), l.Top + float64(row)*(l.Height+l.GapY)
+}
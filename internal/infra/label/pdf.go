@@ -0,0 +1,36 @@
+package label
+
+import (
+	"io"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/pdf"
+)
+
+// WritePDF renders labels as a PDF with one page per sheet.
+func WritePDF(w io.Writer, l Layout, kind Kind, labels []dto.Label) error {
+	pages, err := compose(l, kind, labels)
+	if err != nil {
+		return err
+	}
+
+	doc := pdf.New(l.PageWidth, l.PageHeight)
+	for _, p := range pages {
+		out := doc.AddPage()
+		for _, r := range p.rects {
+			out.Rect(r.x, r.y, r.w, r.h)
+		}
+		for _, t := range p.texts {
+			font := pdf.Helvetica
+			if t.bold {
+				font = pdf.HelveticaBold
+			}
+			x := t.x
+			if t.centered {
+				x -= textWidth(t.s, t.size) / 2
+			}
+			out.Text(x, t.y, font, t.size, t.s)
+		}
+	}
+	return doc.Write(w)
+}
@@ -0,0 +1,151 @@
+package label
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+const (
+	padding     = 4.0
+	quietZone   = 10 // modules of white space either side of a barcode
+	maxModule   = 2.0
+	charWidthEm = 0.55 // average Helvetica advance, used to fit text
+)
+
+// page is a sheet reduced to filled rectangles and text runs, in points from
+// the top-left corner. Text y is the baseline.
+type page struct {
+	rects []rect
+	texts []text
+}
+
+type rect struct {
+	x, y, w, h float64
+}
+
+type text struct {
+	x, y, size float64
+	bold       bool
+	centered   bool
+	s          string
+}
+
+func compose(l Layout, kind Kind, labels []dto.Label) ([]page, error) {
+	var pages []page
+	for i, lb := range labels {
+		if i%l.PerPage() == 0 {
+			pages = append(pages, page{})
+		}
+		p := &pages[len(pages)-1]
+		x, y := l.origin(i)
+
+		var err error
+		switch kind {
+		case KindSpine:
+			p.spine(x, y, l.Width, l.Height, lb)
+		default:
+			err = p.barcode(x, y, l.Width, l.Height, lb)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+func (p *page) barcode(x, y, w, h float64, lb dto.Label) error {
+	size := 7.0
+	if h > 90 {
+		size = 8
+	}
+	inner := w - 2*padding
+	cx := x + w/2
+
+	if lb.Title != "" {
+		p.texts = append(p.texts, text{x: x + padding, y: y + padding + size, size: size, bold: true, s: fit(lb.Title, size, inner)})
+	}
+	p.texts = append(p.texts, text{x: cx, y: y + h - padding, size: size, centered: true, s: fit(lb.CallNumber, size, inner)})
+
+	if lb.Barcode == "" {
+		return nil
+	}
+	p.texts = append(p.texts, text{x: cx, y: y + h - padding - size - 1, size: size, centered: true, s: fit(lb.Barcode, size, inner)})
+
+	widths, err := Code128(lb.Barcode)
+	if err != nil {
+		return err
+	}
+	modules := 0
+	for _, n := range widths {
+		modules += n
+	}
+
+	module := min(inner/float64(modules+2*quietZone), maxModule)
+	top := y + padding + size + 3
+	bottom := y + h - padding - 2*(size+1) - 2
+	bx := cx - module*float64(modules)/2
+	for i, n := range widths {
+		if i%2 == 0 {
+			p.rects = append(p.rects, rect{x: bx, y: top, w: module * float64(n), h: bottom - top})
+		}
+		bx += module * float64(n)
+	}
+	return nil
+}
+
+func (p *page) spine(x, y, w, h float64, lb dto.Label) {
+	lines := spineLines(lb.CallNumber)
+	if len(lines) == 0 && lb.Barcode != "" {
+		lines = []string{lb.Barcode}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	size := min(11, (h-2*padding)/(1.2*float64(len(lines))))
+	for i, line := range lines {
+		p.texts = append(p.texts, text{
+			x:        x + w/2,
+			y:        y + padding + size + float64(i)*size*1.2,
+			size:     size,
+			bold:     true,
+			centered: true,
+			s:        fit(line, size, w-2*padding),
+		})
+	}
+}
+
+// spineLines breaks a call number into the lines usually printed on a
+// spine: one per part, with a leading Library of Congress class split from
+// its number ("QA76.73 .G63" becomes QA, 76.73, .G63).
+func spineLines(call string) []string {
+	fields := strings.Fields(call)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	first := fields[0]
+	if i := strings.IndexFunc(first, func(r rune) bool { return !unicode.IsLetter(r) }); i > 0 && unicode.IsDigit(rune(first[i])) {
+		return append([]string{first[:i], first[i:]}, fields[1:]...)
+	}
+	return fields
+}
+
+// fit shortens s so it is no wider than width at the given font size.
+func fit(s string, size, width float64) string {
+	limit := max(int(width/(size*charWidthEm)), 0)
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	if limit <= 3 {
+		return string(r[:limit])
+	}
+	return string(r[:limit-3]) + "..."
+}
+
+func textWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * charWidthEm
+}
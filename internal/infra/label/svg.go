@@ -0,0 +1,62 @@
+package label
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+// pageGap separates sheets stacked in one SVG document.
+const pageGap = 18.0
+
+// WriteSVG renders labels as one SVG document with the sheets stacked top
+// to bottom. Units are points so the sheet prints at its real size.
+func WriteSVG(w io.Writer, l Layout, kind Kind, labels []dto.Label) error {
+	pages, err := compose(l, kind, labels)
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		pages = []page{{}}
+	}
+
+	height := float64(len(pages))*l.PageHeight + float64(len(pages)-1)*pageGap
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%spt" height="%spt" viewBox="0 0 %s %s">`+"\n",
+		num(l.PageWidth), num(height), num(l.PageWidth), num(height))
+
+	for i, p := range pages {
+		fmt.Fprintf(bw, `<g transform="translate(0 %s)">`+"\n", num(float64(i)*(l.PageHeight+pageGap)))
+		fmt.Fprintf(bw, `<rect width="%s" height="%s" fill="#fff"/>`+"\n", num(l.PageWidth), num(l.PageHeight))
+		for _, r := range p.rects {
+			fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s"/>`+"\n", num(r.x), num(r.y), num(r.w), num(r.h))
+		}
+		for _, t := range p.texts {
+			attrs := ""
+			if t.centered {
+				attrs += ` text-anchor="middle"`
+			}
+			if t.bold {
+				attrs += ` font-weight="bold"`
+			}
+			fmt.Fprintf(bw, `<text x="%s" y="%s" font-family="Helvetica, Arial, sans-serif" font-size="%s"%s>`, num(t.x), num(t.y), num(t.size), attrs)
+			if err := xml.EscapeText(bw, []byte(t.s)); err != nil {
+				return err
+			}
+			fmt.Fprint(bw, "</text>\n")
+		}
+		fmt.Fprint(bw, "</g>\n")
+	}
+
+	fmt.Fprint(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,124 @@
+package marc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+)
+
+const (
+	leaderTemplate  = "00000nam a2200000 i 4500"
+	transactionDate = "20060102150405.0"
+	enteredDate     = "060102"
+)
+
+// FromHolding maps a title and its copies onto a MARC21 bibliographic
+// record. Each copy becomes an 852 location field and an 876 item field
+// carrying its barcode and circulation status. Archived titles are marked
+// as deleted in the leader so downstream catalogs can drop them.
+func FromHolding(h dto.Holding, institution string) Record {
+	b := h.Book
+	leader := []byte(leaderTemplate)
+	if b.Status == book.StatusArchived {
+		leader[5] = 'd'
+	}
+
+	rec := Record{Leader: string(leader)}
+	rec.Fields = append(rec.Fields, Field{Tag: "001", Value: b.ID})
+	if changed := h.ChangedAt(); !changed.IsZero() {
+		rec.Fields = append(rec.Fields, Field{Tag: "005", Value: changed.UTC().Format(transactionDate)})
+	}
+	rec.Fields = append(rec.Fields, Field{Tag: "008", Value: fixedData(h)})
+
+	if b.ISBN != "" {
+		rec.Fields = append(rec.Fields, dataField("020", " ", " ", "a", b.ISBN))
+	}
+
+	for i, author := range b.Authors {
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+		}
+		rec.Fields = append(rec.Fields, dataField(tag, "1", " ", "a", author))
+	}
+
+	titleInd1 := "0"
+	if len(b.Authors) > 0 {
+		titleInd1 = "1"
+	}
+	rec.Fields = append(rec.Fields, dataField("245", titleInd1, "0", "a", b.Title))
+
+	if b.Publisher != "" || b.Year > 0 {
+		f := Field{Tag: "264", Ind1: " ", Ind2: "1"}
+		if b.Publisher != "" {
+			f.Subfields = append(f.Subfields, Subfield{Code: "b", Value: b.Publisher})
+		}
+		if b.Year > 0 {
+			f.Subfields = append(f.Subfields, Subfield{Code: "c", Value: strconv.Itoa(b.Year)})
+		}
+		rec.Fields = append(rec.Fields, f)
+	}
+
+	if b.Category != "" {
+		rec.Fields = append(rec.Fields, dataField("650", " ", "4", "a", b.Category))
+	}
+
+	for _, c := range h.Copies {
+		location := Field{Tag: "852", Ind1: " ", Ind2: " "}
+		if institution != "" {
+			location.Subfields = append(location.Subfields, Subfield{Code: "a", Value: institution})
+		}
+		if c.Branch != "" {
+			location.Subfields = append(location.Subfields, Subfield{Code: "b", Value: c.Branch})
+		}
+		if c.Location != "" {
+			location.Subfields = append(location.Subfields, Subfield{Code: "c", Value: c.Location})
+		}
+		if c.CallNumber != "" {
+			location.Subfields = append(location.Subfields, Subfield{Code: "h", Value: c.CallNumber})
+		}
+		if c.Barcode != "" {
+			location.Subfields = append(location.Subfields, Subfield{Code: "p", Value: c.Barcode})
+		}
+		if len(location.Subfields) > 0 {
+			rec.Fields = append(rec.Fields, location)
+		}
+
+		item := Field{Tag: "876", Ind1: " ", Ind2: " ", Subfields: []Subfield{
+			{Code: "a", Value: c.ID},
+			{Code: "j", Value: string(c.Status)},
+		}}
+		if c.Barcode != "" {
+			item.Subfields = append(item.Subfields, Subfield{Code: "p", Value: c.Barcode})
+		}
+		if c.ConditionNote != "" {
+			item.Subfields = append(item.Subfields, Subfield{Code: "z", Value: c.ConditionNote})
+		}
+		rec.Fields = append(rec.Fields, item)
+	}
+
+	return rec
+}
+
+func fixedData(h dto.Holding) string {
+	entered := "      "
+	if !h.Book.UpdatedAt.IsZero() {
+		entered = h.Book.UpdatedAt.UTC().Format(enteredDate)
+	}
+
+	dateType, year := "n", "uuuu"
+	if h.Book.Year > 0 {
+		dateType, year = "s", fmt.Sprintf("%04d", h.Book.Year)
+	}
+
+	// 008: entered, date type, date 1, blank date 2, place, material
+	// specific positions, language, modified record, cataloging source.
+	return entered + dateType + year + "    " + "xx " + strings.Repeat(" ", 17) + "und" + " " + "d"
+}
+
+func dataField(tag, ind1, ind2, code, value string) Field {
+	return Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: []Subfield{{Code: code, Value: value}}}
+}
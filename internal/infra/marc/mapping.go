@@ -0,0 +1,84 @@
+package marc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// BookInput maps the bibliographic fields of a MARC21 record onto a book
+// create request: 245 title, 100/700 authors, 020 ISBN, 264/260 publisher
+// and year, and the first 650 subject as category.
+func BookInput(rec Record) dto.CreateBookInput {
+	in := dto.CreateBookInput{}
+
+	if f, ok := rec.Field("245"); ok {
+		title := trimPunct(f.Subfield("a"))
+		if sub := trimPunct(f.Subfield("b")); sub != "" {
+			title += ": " + sub
+		}
+		in.Title = title
+	}
+
+	for _, tag := range []string{"100", "110", "700", "710"} {
+		for _, f := range rec.FieldsByTag(tag) {
+			if name := trimPunct(f.Subfield("a")); name != "" {
+				in.Authors = append(in.Authors, name)
+			}
+		}
+	}
+
+	for _, f := range rec.FieldsByTag("020") {
+		if isbn := firstToken(f.Subfield("a")); isbn != "" {
+			in.ISBN = isbn
+			break
+		}
+	}
+
+	for _, tag := range []string{"264", "260"} {
+		for _, f := range rec.FieldsByTag(tag) {
+			if in.Publisher == "" {
+				in.Publisher = trimPunct(f.Subfield("b"))
+			}
+			if in.Year == 0 {
+				in.Year = parseYear(f.Subfield("c"))
+			}
+		}
+	}
+	if in.Year == 0 {
+		if fixed := rec.ControlValue("008"); len(fixed) >= 11 {
+			in.Year = parseYear(fixed[7:11])
+		}
+	}
+
+	if f, ok := rec.Field("650"); ok {
+		in.Category = trimPunct(f.Subfield("a"))
+	}
+
+	return in
+}
+
+func trimPunct(s string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,.="))
+}
+
+func firstToken(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+func parseYear(s string) int {
+	m := yearPattern.FindString(s)
+	if m == "" {
+		return 0
+	}
+	n, _ := strconv.Atoi(m)
+	return n
+}
@@ -0,0 +1,210 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatAuto    Format = "auto"
+	FormatMARC21  Format = "marc21"
+	FormatMARCXML Format = "marcxml"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatMARC21, FormatMARCXML:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported marc format %q", s)
+	}
+}
+
+func Read(r io.Reader, format Format) ([]Record, error) {
+	br := bufio.NewReader(r)
+	if format == FormatAuto || format == "" {
+		format = detectFormat(br)
+	}
+
+	if format == FormatMARCXML {
+		return ReadXML(br)
+	}
+	return ReadBinary(br)
+}
+
+func detectFormat(br *bufio.Reader) Format {
+	head, _ := br.Peek(512)
+	trimmed := bytes.TrimLeft(head, " \t\r\n\xef\xbb\xbf")
+	if len(trimmed) > 0 && trimmed[0] == '<' {
+		return FormatMARCXML
+	}
+	return FormatMARC21
+}
+
+func ReadBinary(r io.Reader) ([]Record, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read marc21: %w", err)
+	}
+
+	var out []Record
+	for len(bytes.TrimSpace(raw)) > 0 {
+		raw = bytes.TrimLeft(raw, "\r\n ")
+		if len(raw) < leaderLength {
+			return nil, fmt.Errorf("%w: truncated leader in record %d", ErrMalformed, len(out)+1)
+		}
+
+		n, ok := number(raw[:5])
+		if !ok || n <= leaderLength || n > len(raw) {
+			return nil, fmt.Errorf("%w: bad record length in record %d", ErrMalformed, len(out)+1)
+		}
+
+		rec, err := decodeBinary(raw[:n])
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(out)+1, err)
+		}
+		out = append(out, rec)
+		raw = raw[n:]
+	}
+
+	return out, nil
+}
+
+func decodeBinary(raw []byte) (Record, error) {
+	leader := string(raw[:leaderLength])
+	base, ok := number([]byte(leader[12:17]))
+	if !ok || base <= leaderLength || base > len(raw) {
+		return Record{}, fmt.Errorf("%w: bad base address", ErrMalformed)
+	}
+
+	dir := raw[leaderLength : base-1]
+	if len(dir)%directoryEntry != 0 {
+		return Record{}, fmt.Errorf("%w: bad directory length", ErrMalformed)
+	}
+
+	rec := Record{Leader: leader}
+	for i := 0; i < len(dir); i += directoryEntry {
+		entry := string(dir[i : i+directoryEntry])
+		length, okLen := number([]byte(entry[3:7]))
+		start, okStart := number([]byte(entry[7:12]))
+		if !okLen || !okStart || base+start+length > len(raw) {
+			return Record{}, fmt.Errorf("%w: bad directory entry %q", ErrMalformed, entry)
+		}
+
+		data := raw[base+start : base+start+length]
+		data = bytes.TrimRight(data, string([]byte{fieldTerminator}))
+		rec.Fields = append(rec.Fields, decodeField(entry[:3], data))
+	}
+
+	return rec, nil
+}
+
+// number reads a fixed-width MARC number. Only digits are allowed, so a
+// sign or a space cannot turn a length or offset negative.
+func number(b []byte) (int, bool) {
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, len(b) > 0
+}
+
+func decodeField(tag string, data []byte) Field {
+	f := Field{Tag: tag}
+	if f.IsControl() {
+		f.Value = string(data)
+		return f
+	}
+
+	if len(data) >= 2 {
+		f.Ind1, f.Ind2 = string(data[0]), string(data[1])
+		data = data[2:]
+	}
+
+	for _, part := range bytes.Split(data, []byte{subfieldDelim}) {
+		if len(part) == 0 {
+			continue
+		}
+		f.Subfields = append(f.Subfields, Subfield{Code: string(part[0]), Value: string(part[1:])})
+	}
+	return f
+}
+
+type xmlCollection struct {
+	Records []xmlRecord `xml:"record"`
+}
+
+type xmlRecord struct {
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+func ReadXML(r io.Reader) ([]Record, error) {
+	dec := xml.NewDecoder(r)
+
+	var out []Record
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: decode marcxml: %v", ErrMalformed, err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var xr xmlRecord
+		if err := dec.DecodeElement(&xr, &start); err != nil {
+			return nil, fmt.Errorf("%w: decode marcxml record %d: %v", ErrMalformed, len(out)+1, err)
+		}
+		out = append(out, fromXML(xr))
+	}
+}
+
+func fromXML(xr xmlRecord) Record {
+	rec := Record{Leader: xr.Leader}
+	for _, cf := range xr.ControlFields {
+		rec.Fields = append(rec.Fields, Field{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range xr.DataFields {
+		f := Field{Tag: df.Tag, Ind1: df.Ind1, Ind2: df.Ind2}
+		for _, sf := range df.Subfields {
+			f.Subfields = append(f.Subfields, Subfield{Code: sf.Code, Value: sf.Value})
+		}
+		rec.Fields = append(rec.Fields, f)
+	}
+	return rec
+}
@@ -0,0 +1,137 @@
+package marc_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/marc"
+)
+
+const sampleXML = `<?xml version="1.0" encoding="UTF-8"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <leader>00000nam a2200000 a 4500</leader>
+    <controlfield tag="001">rec-1</controlfield>
+    <controlfield tag="008">150901s2015    nyua          001 0 eng d</controlfield>
+    <datafield tag="020" ind1=" " ind2=" "><subfield code="a">9780134190440 (pbk.)</subfield></datafield>
+    <datafield tag="100" ind1="1" ind2=" "><subfield code="a">Donovan, Alan A. A.,</subfield></datafield>
+    <datafield tag="245" ind1="1" ind2="4">
+      <subfield code="a">The Go programming language /</subfield>
+      <subfield code="c">Alan A.A. Donovan, Brian W. Kernighan.</subfield>
+    </datafield>
+    <datafield tag="264" ind1=" " ind2="1">
+      <subfield code="a">New York :</subfield>
+      <subfield code="b">Addison-Wesley,</subfield>
+      <subfield code="c">[2016]</subfield>
+    </datafield>
+    <datafield tag="650" ind1=" " ind2="0"><subfield code="a">Go (Computer program language)</subfield></datafield>
+    <datafield tag="700" ind1="1" ind2=" "><subfield code="a">Kernighan, Brian W.,</subfield></datafield>
+  </record>
+</collection>`
+
+func TestReadXMLMapsBookInput(t *testing.T) {
+	t.Parallel()
+
+	records, err := marc.Read(strings.NewReader(sampleXML), marc.FormatAuto)
+	if err != nil {
+		t.Fatalf("read marcxml: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record got %d", len(records))
+	}
+
+	in := marc.BookInput(records[0])
+	if in.Title != "The Go programming language" {
+		t.Fatalf("unexpected title %q", in.Title)
+	}
+	if len(in.Authors) != 2 || in.Authors[0] != "Donovan, Alan A. A" || in.Authors[1] != "Kernighan, Brian W" {
+		t.Fatalf("unexpected authors %q", in.Authors)
+	}
+	if in.ISBN != "9780134190440" || in.Publisher != "Addison-Wesley" || in.Year != 2016 {
+		t.Fatalf("unexpected mapping %+v", in)
+	}
+	if in.Category != "Go (Computer program language)" {
+		t.Fatalf("unexpected category %q", in.Category)
+	}
+}
+
+func TestReadBinary(t *testing.T) {
+	t.Parallel()
+
+	raw := binaryRecord([][2]string{
+		{"001", "rec-2"},
+		{"008", "990101s1999    xx            000 0 eng d"},
+		{"020", "  \x1fa0306406152"},
+		{"245", "10\x1faCafé society :\x1fba history /"},
+		{"100", "1 \x1faÉmile, Zola."},
+	})
+	raw += binaryRecord([][2]string{{"245", "00\x1faSecond"}})
+
+	records, err := marc.Read(strings.NewReader(raw), marc.FormatAuto)
+	if err != nil {
+		t.Fatalf("read marc21: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records got %d", len(records))
+	}
+
+	in := marc.BookInput(records[0])
+	if in.Title != "Café society: a history" || in.Authors[0] != "Émile, Zola" || in.ISBN != "0306406152" || in.Year != 1999 {
+		t.Fatalf("unexpected mapping %+v", in)
+	}
+	if records[0].ControlValue("001") != "rec-2" {
+		t.Fatalf("expected control field to be decoded")
+	}
+}
+
+func TestReadBinaryRejectsTruncatedRecord(t *testing.T) {
+	t.Parallel()
+
+	raw := binaryRecord([][2]string{{"245", "00\x1faTitle"}})
+	_, err := marc.ReadBinary(strings.NewReader(raw[:len(raw)-5]))
+	if !errors.Is(err, marc.ErrMalformed) {
+		t.Fatalf("expected malformed error got %v", err)
+	}
+}
+
+func TestReadBinaryRejectsBadNumbers(t *testing.T) {
+	t.Parallel()
+
+	good := binaryRecord([][2]string{{"245", "00\x1faTitle"}, {"100", "1 \x1faAuthor"}})
+	tests := []struct {
+		name  string
+		at    int
+		value string
+	}{
+		{name: "signed record length", at: 0, value: "+0058"},
+		{name: "signed base address", at: 12, value: "-0049"},
+		{name: "negative field length", at: 27, value: "-001"},
+		{name: "signed field start", at: 31, value: "+0000"},
+		{name: "negative field start", at: 43, value: "-0009"},
+		{name: "spaces in field length", at: 27, value: " 10 "},
+	}
+
+	for _, tt := range tests {
+		raw := good[:tt.at] + tt.value + good[tt.at+len(tt.value):]
+		if _, err := marc.ReadBinary(strings.NewReader(raw)); !errors.Is(err, marc.ErrMalformed) {
+			t.Fatalf("%s: expected malformed error got %v", tt.name, err)
+		}
+	}
+}
+
+func binaryRecord(fields [][2]string) string {
+	var dir, data strings.Builder
+	for _, f := range fields {
+		body := f[1] + "\x1e"
+		fmt.Fprintf(&dir, "%s%04d%05d", f[0], len(body), data.Len())
+		data.WriteString(body)
+	}
+	dir.WriteString("\x1e")
+
+	base := 24 + dir.Len()
+	total := base + data.Len() + 1
+	leader := fmt.Sprintf("%05dnam a22%05d a 4500", total, base)
+	return leader + dir.String() + data.String() + "\x1d"
+}
@@ -0,0 +1,84 @@
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	fieldTerminator  = 0x1E
+	recordTerminator = 0x1D
+	subfieldDelim    = 0x1F
+	leaderLength     = 24
+	directoryEntry   = 12
+)
+
+var ErrMalformed = errors.New("malformed marc record")
+
+type Subfield struct {
+	Code  string
+	Value string
+}
+
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      string
+	Ind2      string
+	Subfields []Subfield
+}
+
+func (f Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+func (f Field) Subfield(code string) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+func (f Field) SubfieldValues(code string) []string {
+	var out []string
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			out = append(out, sf.Value)
+		}
+	}
+	return out
+}
+
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+func (r Record) Field(tag string) (Field, bool) {
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+func (r Record) FieldsByTag(tag string) []Field {
+	var out []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (r Record) ControlValue(tag string) string {
+	f, ok := r.Field(tag)
+	if !ok {
+		return ""
+	}
+	return f.Value
+}
@@ -0,0 +1,155 @@
+package marc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	maxFieldLength  = 9999
+	maxRecordLength = 99999
+	marcxmlNS       = "http://www.loc.gov/MARC21/slim"
+)
+
+func Write(w io.Writer, records []Record, format Format) error {
+	switch format {
+	case FormatMARCXML:
+		return WriteXML(w, records)
+	case FormatMARC21, FormatAuto, "":
+		return WriteBinary(w, records)
+	default:
+		return fmt.Errorf("unsupported marc format %q", format)
+	}
+}
+
+func WriteBinary(w io.Writer, records []Record) error {
+	for i, rec := range records {
+		raw, err := encodeBinary(rec)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeBinary(rec Record) ([]byte, error) {
+	var dir, data bytes.Buffer
+	for _, f := range rec.Fields {
+		encoded := encodeField(f)
+		if len(encoded) > maxFieldLength {
+			return nil, fmt.Errorf("%w: field %s exceeds %d bytes", ErrMalformed, f.Tag, maxFieldLength)
+		}
+		fmt.Fprintf(&dir, "%3.3s%04d%05d", padTag(f.Tag), len(encoded), data.Len())
+		data.Write(encoded)
+	}
+	dir.WriteByte(fieldTerminator)
+
+	base := leaderLength + dir.Len()
+	total := base + data.Len() + 1
+	if total > maxRecordLength {
+		return nil, fmt.Errorf("%w: record exceeds %d bytes", ErrMalformed, maxRecordLength)
+	}
+
+	leader := []byte(normalizeLeader(rec.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, dir.Bytes()...)
+	out = append(out, data.Bytes()...)
+	out = append(out, recordTerminator)
+	return out, nil
+}
+
+func encodeField(f Field) []byte {
+	var b bytes.Buffer
+	if f.IsControl() {
+		b.WriteString(f.Value)
+		b.WriteByte(fieldTerminator)
+		return b.Bytes()
+	}
+
+	b.WriteString(indicator(f.Ind1))
+	b.WriteString(indicator(f.Ind2))
+	for _, sf := range f.Subfields {
+		b.WriteByte(subfieldDelim)
+		b.WriteString(sf.Code)
+		b.WriteString(sf.Value)
+	}
+	b.WriteByte(fieldTerminator)
+	return b.Bytes()
+}
+
+// normalizeLeader pads or trims the leader to 24 bytes and fixes the
+// positions that describe the encoding: Unicode, two indicators, one-byte
+// subfield codes and the 4500 entry map.
+func normalizeLeader(leader string) string {
+	b := []byte(fmt.Sprintf("%-24.24s", leader))
+	for i := range b {
+		if b[i] == 0 {
+			b[i] = ' '
+		}
+	}
+	b[9], b[10], b[11] = 'a', '2', '2'
+	copy(b[20:24], "4500")
+	return string(b)
+}
+
+func indicator(s string) string {
+	if s == "" {
+		return " "
+	}
+	return s[:1]
+}
+
+func padTag(tag string) string {
+	return fmt.Sprintf("%03s", strings.TrimSpace(tag))
+}
+
+type xmlOutCollection struct {
+	XMLName xml.Name    `xml:"collection"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Records []xmlRecord `xml:"record"`
+}
+
+func WriteXML(w io.Writer, records []Record) error {
+	coll := xmlOutCollection{Xmlns: marcxmlNS}
+	for _, rec := range records {
+		coll.Records = append(coll.Records, toXML(rec))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(coll); err != nil {
+		return fmt.Errorf("encode marcxml: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func toXML(rec Record) xmlRecord {
+	xr := xmlRecord{Leader: normalizeLeader(rec.Leader)}
+	for _, f := range rec.Fields {
+		if f.IsControl() {
+			xr.ControlFields = append(xr.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+
+		df := xmlDataField{Tag: f.Tag, Ind1: indicator(f.Ind1), Ind2: indicator(f.Ind2)}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: sf.Code, Value: sf.Value})
+		}
+		xr.DataFields = append(xr.DataFields, df)
+	}
+	return xr
+}
@@ -0,0 +1,112 @@
+package marc_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/marc"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	changed := time.Date(2026, 5, 4, 3, 2, 1, 0, time.UTC)
+	holding := dto.Holding{
+		Book: book.Book{
+			ID:        "b-1",
+			Title:     "Café society",
+			Authors:   []string{"Ann Author", "Bob Second"},
+			ISBN:      "9780306406157",
+			Category:  "Sociology",
+			Publisher: "Pub House",
+			Year:      2019,
+			Status:    book.StatusActive,
+			UpdatedAt: changed.Add(-time.Hour),
+		},
+		Copies: []copy.Copy{
+			{ID: "c-1", BookID: "b-1", Barcode: "BC-1", Status: copy.StatusAvailable, UpdatedAt: changed},
+			{ID: "c-2", BookID: "b-1", Barcode: "BC-2", Status: copy.StatusLoaned, ConditionNote: "worn"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		format marc.Format
+	}{
+		{name: "marc21", format: marc.FormatMARC21},
+		{name: "marcxml", format: marc.FormatMARCXML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := marc.Write(&buf, []marc.Record{marc.FromHolding(holding, "XYZ")}, tt.format); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			records, err := marc.Read(&buf, marc.FormatAuto)
+			if err != nil {
+				t.Fatalf("read back: %v", err)
+			}
+			if len(records) != 1 {
+				t.Fatalf("expected 1 record got %d", len(records))
+			}
+			rec := records[0]
+
+			got := marc.BookInput(rec)
+			want := dto.CreateBookInput{
+				Title:     "Café society",
+				Authors:   []string{"Ann Author", "Bob Second"},
+				ISBN:      "9780306406157",
+				Category:  "Sociology",
+				Publisher: "Pub House",
+				Year:      2019,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected book input\n got %+v\nwant %+v", got, want)
+			}
+
+			if v := rec.ControlValue("005"); v != "20260504030201.0" {
+				t.Fatalf("expected 005 from latest copy change got %q", v)
+			}
+			if v := rec.ControlValue("008"); len(v) != 40 || v[7:11] != "2019" {
+				t.Fatalf("unexpected 008 %q", v)
+			}
+
+			items := rec.FieldsByTag("876")
+			if len(items) != 2 {
+				t.Fatalf("expected 2 item fields got %d", len(items))
+			}
+			if items[1].Subfield("p") != "BC-2" || items[1].Subfield("j") != "loaned" || items[1].Subfield("z") != "worn" {
+				t.Fatalf("unexpected item field %+v", items[1])
+			}
+			if loc := rec.FieldsByTag("852"); len(loc) != 2 || loc[0].Subfield("a") != "XYZ" {
+				t.Fatalf("unexpected location fields %+v", loc)
+			}
+		})
+	}
+}
+
+func TestExportMarksArchivedTitlesDeleted(t *testing.T) {
+	t.Parallel()
+
+	rec := marc.FromHolding(dto.Holding{Book: book.Book{ID: "b-1", Title: "Gone", Status: book.StatusArchived}}, "")
+
+	var buf bytes.Buffer
+	if err := marc.WriteBinary(&buf, []marc.Record{rec}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := buf.Bytes()[5]; got != 'd' {
+		t.Fatalf("expected record status d got %q", got)
+	}
+	if len(rec.FieldsByTag("852")) != 0 {
+		t.Fatalf("expected no location fields without copies")
+	}
+}
@@ -0,0 +1,142 @@
+// Package pdf writes simple uncompressed PDF documents made of filled
+// rectangles and text in the standard Type 1 fonts, so nothing needs to be
+// embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Page sizes in points.
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+)
+
+type Font string
+
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+	Courier       Font = "F3"
+)
+
+var fonts = []struct {
+	font Font
+	base string
+}{
+	{Helvetica, "Helvetica"},
+	{HelveticaBold, "Helvetica-Bold"},
+	{Courier, "Courier"},
+}
+
+// Document is a list of pages of the same size. Coordinates are in points
+// from the top-left corner of the page.
+type Document struct {
+	width, height float64
+	pages         []*Page
+}
+
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{height: d.height}
+	p.content.WriteString("0 g\n")
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Rect fills a rectangle whose top-left corner is at x, y.
+func (p *Page) Rect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// Text draws s with its baseline starting at x, y.
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td %s Tj ET\n", font, num(size), num(x), num(p.height-y), literal(s))
+}
+
+// Write serialises the document. A document without pages gets one blank
+// page, since a PDF must have at least one.
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Object 1 is the catalog, 2 the page tree, then one per font, then a
+	// page and its content stream for every page.
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	var resources bytes.Buffer
+	resources.WriteString("<< /Font <<")
+	for _, f := range fonts {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.base))
+		fmt.Fprintf(&resources, " /%s %d 0 R", f.font, len(objects))
+	}
+	resources.WriteString(" >> >>")
+
+	var kids bytes.Buffer
+	for _, p := range d.pages {
+		pageObj := len(objects) + 1
+		fmt.Fprintf(&kids, " %d 0 R", pageObj)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+				num(d.width), num(d.height), resources.String(), pageObj+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", p.content.Len(), p.content.Bytes()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s ] /Count %d >>", kids.String(), len(d.pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// literal writes s as a string in WinAnsi, which matches Latin-1 for the
+// characters the library prints; anything outside it becomes "?".
+func literal(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128 || (r >= 160 && r <= 255):
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
@@ -2,6 +2,7 @@ package jsonstore
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
@@ -39,6 +40,20 @@ func (r *BookRepository) GetByID(_ context.Context, id string) (book.Book, error
 	return b, nil
 }
 
+func (r *BookRepository) GetByISBN(_ context.Context, isbn string) (book.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	want := canonicalISBN(isbn)
+	for _, b := range r.store.data.Books {
+		if b.ISBN != "" && canonicalISBN(b.ISBN) == want {
+			return b, nil
+		}
+	}
+
+	return book.Book{}, shared.ErrNotFound
+}
+
 func (r *BookRepository) List(_ context.Context) ([]book.Book, error) {
 	r.store.mu.RLock()
 	defer r.store.mu.RUnlock()
@@ -50,3 +65,10 @@ func (r *BookRepository) List(_ context.Context) ([]book.Book, error) {
 
 	return out, nil
 }
+
+func canonicalISBN(isbn string) string {
+	if n, err := book.NormalizeISBN(isbn); err == nil {
+		return n
+	}
+	return strings.TrimSpace(isbn)
+}
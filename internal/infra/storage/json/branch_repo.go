@@ -0,0 +1,52 @@
+package jsonstore
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/branch"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type BranchRepository struct {
+	store *Store
+}
+
+func NewBranchRepository(store *Store) *BranchRepository {
+	return &BranchRepository{store: store}
+}
+
+func (r *BranchRepository) Save(_ context.Context, b branch.Branch) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Branches[b.Code] = b
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *BranchRepository) GetByCode(_ context.Context, code string) (branch.Branch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	b, ok := r.store.data.Branches[code]
+	if !ok {
+		return branch.Branch{}, shared.ErrNotFound
+	}
+
+	return b, nil
+}
+
+func (r *BranchRepository) List(_ context.Context) ([]branch.Branch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]branch.Branch, 0, len(r.store.data.Branches))
+	for _, b := range r.store.data.Branches {
+		out = append(out, b)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package jsonstore
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type HoldRepository struct {
+	store *Store
+}
+
+func NewHoldRepository(store *Store) *HoldRepository {
+	return &HoldRepository{store: store}
+}
+
+func (r *HoldRepository) Save(_ context.Context, h hold.Hold) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Holds[h.ID] = h
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *HoldRepository) GetByID(_ context.Context, id string) (hold.Hold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	h, ok := r.store.data.Holds[id]
+	if !ok {
+		return hold.Hold{}, shared.ErrNotFound
+	}
+
+	return h, nil
+}
+
+func (r *HoldRepository) List(_ context.Context) ([]hold.Hold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]hold.Hold, 0, len(r.store.data.Holds))
+	for _, h := range r.store.data.Holds {
+		out = append(out, h)
+	}
+
+	return out, nil
+}
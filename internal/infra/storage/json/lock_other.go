@@ -0,0 +1,18 @@
+//go:build !unix && !windows
+
+package jsonstore
+
+import (
+	"fmt"
+	"os"
+)
+
+// lockFile only creates path here: this platform has no file locks, so a
+// second process opening the store is not detected.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open storage lock: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package jsonstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile opens path and takes an exclusive lock on it without waiting.
+// The lock goes away when the file is closed or the process exits.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open storage lock: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrStoreInUse
+		}
+		return nil, fmt.Errorf("lock storage file: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package jsonstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const errSharingViolation syscall.Errno = 32
+
+// lockFile opens path without sharing it, so no other process can open it
+// until the handle is closed or the process exits.
+func lockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, fmt.Errorf("open storage lock: %w", err)
+	}
+
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if errors.Is(err, errSharingViolation) {
+			return nil, ErrStoreInUse
+		}
+		return nil, fmt.Errorf("open storage lock: %w", err)
+	}
+
+	return os.NewFile(uintptr(h), path), nil
+}
@@ -2,6 +2,7 @@ package jsonstore
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
@@ -39,6 +40,23 @@ func (r *MemberRepository) GetByID(_ context.Context, id string) (member.Member,
 	return m, nil
 }
 
+func (r *MemberRepository) GetByCardNumber(_ context.Context, card string) (member.Member, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	want := strings.TrimSpace(card)
+	if want == "" {
+		return member.Member{}, shared.ErrNotFound
+	}
+	for _, m := range r.store.data.Members {
+		if strings.EqualFold(m.CardNumber, want) {
+			return m, nil
+		}
+	}
+
+	return member.Member{}, shared.ErrNotFound
+}
+
 func (r *MemberRepository) List(_ context.Context) ([]member.Member, error) {
 	r.store.mu.RLock()
 	defer r.store.mu.RUnlock()
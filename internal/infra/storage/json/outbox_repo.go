@@ -0,0 +1,110 @@
+package jsonstore
+
+import (
+	"context"
+	"maps"
+	"sort"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+)
+
+// outboxKeep is how many of the latest events are kept for lms events once
+// every subscriber has handled them.
+const outboxKeep = 1000
+
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+func (r *OutboxRepository) Append(_ context.Context, envelopes []event.Envelope) ([]event.Envelope, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	out := make([]event.Envelope, 0, len(envelopes))
+	seq := r.store.data.OutboxSeq
+	for _, env := range envelopes {
+		seq++
+		env.Seq = seq
+		out = append(out, env)
+	}
+
+	next := r.store.data
+	next.Outbox = append(append([]event.Envelope(nil), r.store.data.Outbox...), out...)
+	next.OutboxSeq = seq
+	if err := r.store.writeSnapshot(next); err != nil {
+		return nil, err
+	}
+
+	r.store.data = next
+	return out, nil
+}
+
+func (r *OutboxRepository) After(_ context.Context, seq int64, limit int) ([]event.Envelope, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	outbox := r.store.data.Outbox
+	start := sort.Search(len(outbox), func(i int) bool { return outbox[i].Seq > seq })
+
+	end := len(outbox)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	return append([]event.Envelope(nil), outbox[start:end]...), nil
+}
+
+func (r *OutboxRepository) Head(_ context.Context) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.data.OutboxSeq, nil
+}
+
+func (r *OutboxRepository) Cursor(_ context.Context, subscriber string) (int64, bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seq, ok := r.store.data.Cursors[subscriber]
+	return seq, ok, nil
+}
+
+func (r *OutboxRepository) SaveCursor(_ context.Context, subscriber string, seq int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	next := r.store.data
+	next.Cursors = maps.Clone(r.store.data.Cursors)
+	next.Cursors[subscriber] = seq
+	next.Outbox = trimOutbox(next.Outbox, next.Cursors, outboxKeep)
+	if err := r.store.writeSnapshot(next); err != nil {
+		return err
+	}
+
+	r.store.data = next
+	return nil
+}
+
+// trimOutbox drops the envelopes every cursor has passed, apart from the
+// latest keep of them.
+func trimOutbox(outbox []event.Envelope, cursors map[string]int64, keep int) []event.Envelope {
+	if len(outbox) <= keep {
+		return outbox
+	}
+
+	passed := outbox[len(outbox)-1].Seq
+	for _, seq := range cursors {
+		passed = min(passed, seq)
+	}
+
+	cut := sort.Search(len(outbox), func(i int) bool { return outbox[i].Seq > passed })
+	cut = min(cut, len(outbox)-keep)
+	if cut <= 0 {
+		return outbox
+	}
+	return append([]event.Envelope(nil), outbox[cut:]...)
+}
@@ -0,0 +1,25 @@
+package jsonstore
+
+import "context"
+
+type SequenceRepository struct {
+	store *Store
+}
+
+func NewSequenceRepository(store *Store) *SequenceRepository {
+	return &SequenceRepository{store: store}
+}
+
+func (r *SequenceRepository) Next(_ context.Context, name string, floor int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	prev := r.store.data.Sequences[name]
+	n := max(prev+1, floor)
+	r.store.data.Sequences[name] = n
+	if err := r.store.writeSnapshot(r.store.data); err != nil {
+		r.store.data.Sequences[name] = prev
+		return 0, err
+	}
+	return n, nil
+}
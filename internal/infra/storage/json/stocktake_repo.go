@@ -0,0 +1,52 @@
+package jsonstore
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/stocktake"
+)
+
+type StocktakeRepository struct {
+	store *Store
+}
+
+func NewStocktakeRepository(store *Store) *StocktakeRepository {
+	return &StocktakeRepository{store: store}
+}
+
+func (r *StocktakeRepository) Save(_ context.Context, s stocktake.Session) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Stocktakes[s.ID] = s
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *StocktakeRepository) GetByID(_ context.Context, id string) (stocktake.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.data.Stocktakes[id]
+	if !ok {
+		return stocktake.Session{}, shared.ErrNotFound
+	}
+
+	return s, nil
+}
+
+func (r *StocktakeRepository) List(_ context.Context) ([]stocktake.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]stocktake.Session, 0, len(r.store.data.Stocktakes))
+	for _, s := range r.store.data.Stocktakes {
+		out = append(out, s)
+	}
+
+	return out, nil
+}
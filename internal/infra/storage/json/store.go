@@ -9,9 +9,15 @@ import (
 	"sync"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/branch"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/stocktake"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
 )
 
 const schemaVersion = 1
@@ -19,11 +25,16 @@ const schemaVersion = 1
 var (
 	ErrCorruptData      = errors.New("storage data is corrupt")
 	ErrUnsupportedStore = errors.New("unsupported storage schema version")
+	ErrStoreInUse       = errors.New("storage file is in use by another lms process")
 )
 
+// Store keeps the whole snapshot in memory and rewrites the file on every
+// save. Only one process may have a store open at a time: Open locks
+// <path>.lock and fails with ErrStoreInUse while another process holds it.
 type Store struct {
 	mu   sync.RWMutex
 	path string
+	lock *os.File
 	data snapshot
 }
 
@@ -33,6 +44,21 @@ type snapshot struct {
 	Copies  map[string]copy.Copy     `json:"copies"`
 	Members map[string]member.Member `json:"members"`
 	Loans   map[string]loan.Loan     `json:"loans"`
+	Holds   map[string]hold.Hold     `json:"holds"`
+
+	Outbox    []event.Envelope `json:"outbox"`
+	OutboxSeq int64            `json:"outbox_seq"`
+	Cursors   map[string]int64 `json:"cursors"`
+
+	Sequences map[string]int64 `json:"sequences"`
+
+	Webhooks   map[string]webhook.Endpoint `json:"webhooks"`
+	Deliveries map[string]webhook.Delivery `json:"webhook_deliveries"`
+
+	Stocktakes map[string]stocktake.Session `json:"stocktakes"`
+
+	Branches  map[string]branch.Branch     `json:"branches"`
+	Transfers map[string]transfer.Transfer `json:"transfers"`
 }
 
 func Open(path string) (*Store, error) {
@@ -40,24 +66,47 @@ func Open(path string) (*Store, error) {
 		return nil, fmt.Errorf("create storage directory: %w", err)
 	}
 
-	s := &Store{path: path, data: newSnapshot()}
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{path: path, lock: lock, data: newSnapshot()}
+	if err := s.load(); err != nil {
+		lock.Close()
+		return nil, err
+	}
 
-	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		if err := s.writeSnapshot(s.data); err != nil {
-			return nil, err
-		}
-		return s, nil
+	return s, nil
+}
+
+// Close releases the lock so another process can open the store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lock == nil {
+		return nil
+	}
+	err := s.lock.Close()
+	s.lock = nil
+	return err
+}
+
+func (s *Store) load() error {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return s.writeSnapshot(s.data)
 	} else if err != nil {
-		return nil, fmt.Errorf("stat storage file: %w", err)
+		return fmt.Errorf("stat storage file: %w", err)
 	}
 
 	loaded, err := s.readSnapshot()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	s.data = loaded
-	return s, nil
+	return nil
 }
 
 func newSnapshot() snapshot {
@@ -67,6 +116,17 @@ func newSnapshot() snapshot {
 		Copies:  map[string]copy.Copy{},
 		Members: map[string]member.Member{},
 		Loans:   map[string]loan.Loan{},
+		Holds:   map[string]hold.Hold{},
+		Cursors: map[string]int64{},
+
+		Sequences: map[string]int64{},
+
+		Webhooks:   map[string]webhook.Endpoint{},
+		Deliveries: map[string]webhook.Delivery{},
+		Stocktakes: map[string]stocktake.Session{},
+
+		Branches:  map[string]branch.Branch{},
+		Transfers: map[string]transfer.Transfer{},
 	}
 }
 
@@ -128,6 +188,30 @@ func normalizeSnapshot(s *snapshot) {
 	if s.Loans == nil {
 		s.Loans = map[string]loan.Loan{}
 	}
+	if s.Holds == nil {
+		s.Holds = map[string]hold.Hold{}
+	}
+	if s.Cursors == nil {
+		s.Cursors = map[string]int64{}
+	}
+	if s.Sequences == nil {
+		s.Sequences = map[string]int64{}
+	}
+	if s.Webhooks == nil {
+		s.Webhooks = map[string]webhook.Endpoint{}
+	}
+	if s.Deliveries == nil {
+		s.Deliveries = map[string]webhook.Delivery{}
+	}
+	if s.Stocktakes == nil {
+		s.Stocktakes = map[string]stocktake.Session{}
+	}
+	if s.Branches == nil {
+		s.Branches = map[string]branch.Branch{}
+	}
+	if s.Transfers == nil {
+		s.Transfers = map[string]transfer.Transfer{}
+	}
 }
 
 func validateSnapshot(s snapshot) error {
@@ -155,5 +239,49 @@ func validateSnapshot(s snapshot) error {
 		}
 	}
 
+	for _, h := range s.Holds {
+		if err := h.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid hold %q: %v", ErrCorruptData, h.ID, err)
+		}
+	}
+
+	for _, e := range s.Webhooks {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid webhook %q: %v", ErrCorruptData, e.ID, err)
+		}
+	}
+
+	for _, d := range s.Deliveries {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid webhook delivery %q: %v", ErrCorruptData, d.ID, err)
+		}
+	}
+
+	for _, st := range s.Stocktakes {
+		if err := st.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid stocktake %q: %v", ErrCorruptData, st.ID, err)
+		}
+	}
+
+	for _, b := range s.Branches {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid branch %q: %v", ErrCorruptData, b.Code, err)
+		}
+	}
+
+	for _, t := range s.Transfers {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid transfer %q: %v", ErrCorruptData, t.ID, err)
+		}
+	}
+
+	var last int64
+	for _, env := range s.Outbox {
+		if env.Seq <= last || env.Seq > s.OutboxSeq {
+			return fmt.Errorf("%w: outbox sequence out of order at %d", ErrCorruptData, env.Seq)
+		}
+		last = env.Seq
+	}
+
 	return nil
 }
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/event"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
@@ -74,10 +76,18 @@ func TestStoreReadWriteReadConsistency(t *testing.T) {
 		t.Fatalf("save loan: %v", err)
 	}
 
+	if _, err := jsonstore.Open(path); !errors.Is(err, jsonstore.ErrStoreInUse) {
+		t.Fatalf("expected a second open to fail with ErrStoreInUse, got %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close store: %v", err)
+	}
+
 	reopened, err := jsonstore.Open(path)
 	if err != nil {
 		t.Fatalf("reopen store: %v", err)
 	}
+	defer reopened.Close()
 
 	bookRepo2 := jsonstore.NewBookRepository(reopened)
 	copyRepo2 := jsonstore.NewCopyRepository(reopened)
@@ -126,3 +136,52 @@ func TestOpenFailsOnCorruptJSON(t *testing.T) {
 		t.Fatalf("expected ErrCorruptData got %v", err)
 	}
 }
+
+func TestOutboxTrimsEventsEverySubscriberHandled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := jsonstore.Open(filepath.Join(t.TempDir(), "storage.json"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	outbox := jsonstore.NewOutboxRepository(store)
+
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	envelopes := make([]event.Envelope, 0, 1500)
+	for i := range 1500 {
+		env, err := event.Wrap("evt-"+strconv.Itoa(i), event.BookArchived{BookID: "b-1", At: at})
+		if err != nil {
+			t.Fatalf("wrap: %v", err)
+		}
+		envelopes = append(envelopes, env)
+	}
+	if _, err := outbox.Append(ctx, envelopes); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		subscriber string
+		cursor     int64
+		first      int64
+	}{
+		{name: "nothing is handled yet", subscriber: "webhooks", cursor: 0, first: 1},
+		{name: "a subscriber behind keeps what it has not handled", subscriber: "audit", cursor: 1500, first: 1},
+		{name: "trimmed up to the slowest subscriber", subscriber: "webhooks", cursor: 300, first: 301},
+		{name: "the latest events are kept once every subscriber is done", subscriber: "webhooks", cursor: 1500, first: 501},
+	}
+	for _, tt := range tests {
+		if err := outbox.SaveCursor(ctx, tt.subscriber, tt.cursor); err != nil {
+			t.Fatalf("%s: save cursor: %v", tt.name, err)
+		}
+		left, err := outbox.After(ctx, 0, 0)
+		if err != nil || len(left) == 0 || left[0].Seq != tt.first || left[len(left)-1].Seq != 1500 {
+			t.Fatalf("%s: expected events %d..1500 got %d events (%v)", tt.name, tt.first, len(left), err)
+		}
+	}
+	if head, _ := outbox.Head(ctx); head != 1500 {
+		t.Fatalf("expected the head to stay at 1500 got %d", head)
+	}
+}
@@ -0,0 +1,52 @@
+package jsonstore
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/transfer"
+)
+
+type TransferRepository struct {
+	store *Store
+}
+
+func NewTransferRepository(store *Store) *TransferRepository {
+	return &TransferRepository{store: store}
+}
+
+func (r *TransferRepository) Save(_ context.Context, t transfer.Transfer) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Transfers[t.ID] = t
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *TransferRepository) GetByID(_ context.Context, id string) (transfer.Transfer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	t, ok := r.store.data.Transfers[id]
+	if !ok {
+		return transfer.Transfer{}, shared.ErrNotFound
+	}
+
+	return t, nil
+}
+
+func (r *TransferRepository) List(_ context.Context) ([]transfer.Transfer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]transfer.Transfer, 0, len(r.store.data.Transfers))
+	for _, t := range r.store.data.Transfers {
+		out = append(out, t)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package jsonstore
+
+import (
+	"context"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/webhook"
+)
+
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+func (r *WebhookRepository) SaveEndpoint(_ context.Context, e webhook.Endpoint) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Webhooks[e.ID] = e
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *WebhookRepository) GetEndpoint(_ context.Context, id string) (webhook.Endpoint, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	e, ok := r.store.data.Webhooks[id]
+	if !ok {
+		return webhook.Endpoint{}, shared.ErrNotFound
+	}
+
+	return e, nil
+}
+
+func (r *WebhookRepository) DeleteEndpoint(_ context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.Webhooks[id]; !ok {
+		return shared.ErrNotFound
+	}
+
+	delete(r.store.data.Webhooks, id)
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *WebhookRepository) ListEndpoints(_ context.Context) ([]webhook.Endpoint, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]webhook.Endpoint, 0, len(r.store.data.Webhooks))
+	for _, e := range r.store.data.Webhooks {
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+func (r *WebhookRepository) SaveDelivery(_ context.Context, d webhook.Delivery) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.data.Deliveries[d.ID] = d
+	return r.store.writeSnapshot(r.store.data)
+}
+
+func (r *WebhookRepository) GetDelivery(_ context.Context, id string) (webhook.Delivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	d, ok := r.store.data.Deliveries[id]
+	if !ok {
+		return webhook.Delivery{}, shared.ErrNotFound
+	}
+
+	return d, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(_ context.Context) ([]webhook.Delivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]webhook.Delivery, 0, len(r.store.data.Deliveries))
+	for _, d := range r.store.data.Deliveries {
+		out = append(out, d)
+	}
+
+	return out, nil
+}
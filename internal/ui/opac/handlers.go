@@ -0,0 +1,423 @@
+package opac
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/hold"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+type availability struct {
+	Total     int
+	Available int
+	OnLoan    int
+}
+
+type titleView struct {
+	Book         book.Book
+	Availability availability
+}
+
+type searchView struct {
+	Query   string
+	Results []titleView
+}
+
+type bookView struct {
+	titleView
+	Copies   []copy.Copy
+	Queue    int
+	CanHold  bool
+	HoldNote string
+}
+
+type loanView struct {
+	Loan     loan.Loan
+	Title    string
+	Overdue  bool
+	CanRenew bool
+	Reason   string
+}
+
+type holdView struct {
+	Hold     hold.Hold
+	Title    string
+	Position int
+}
+
+type accountView struct {
+	Member member.Member
+	Loans  []loanView
+	Holds  []holdView
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	view := searchView{Query: q}
+
+	if q != "" {
+		books, err := s.services.Books.Search(r.Context(), q, searchLimit)
+		if err != nil {
+			s.fail(w, r, err)
+			return
+		}
+
+		avail, err := s.availability(r.Context())
+		if err != nil {
+			s.fail(w, r, err)
+			return
+		}
+
+		for _, b := range books {
+			if b.Status != book.StatusActive {
+				continue
+			}
+			view.Results = append(view.Results, titleView{Book: b, Availability: avail[b.ID]})
+		}
+	}
+
+	s.render(w, r, "search", "Catalog search", view)
+}
+
+func (s *Server) book(w http.ResponseWriter, r *http.Request) {
+	b, err := s.services.Books.GetByID(r.Context(), r.PathValue("id"))
+	if err == nil && b.Status != book.StatusActive {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	copies, err := s.copiesOf(r.Context(), b.ID)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	queue, err := s.services.Holds.Queue(r.Context(), b.ID)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	view := bookView{
+		titleView: titleView{Book: b, Availability: summarize(copies)},
+		Copies:    copies,
+		Queue:     len(queue),
+	}
+
+	if memberID, ok := s.sessions.memberID(r, s.clock.Now()); ok {
+		view.CanHold = true
+		for _, h := range queue {
+			if h.MemberID == memberID {
+				view.CanHold = false
+				view.HoldNote = "You already have a hold on this title."
+			}
+		}
+	}
+
+	s.render(w, r, "book", b.Title, view)
+}
+
+func (s *Server) placeHold(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := s.requireMember(w, r)
+	if !ok {
+		return
+	}
+
+	bookID := r.PathValue("id")
+	_, err := s.services.Holds.Place(r.Context(), dto.PlaceHoldInput{BookID: bookID, MemberID: memberID})
+	if code, known := errorCode(err); known {
+		redirect(w, r, "/books/"+bookID, "error", code)
+		return
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	redirect(w, r, "/account", "notice", "hold_placed")
+}
+
+func (s *Server) loginForm(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.sessions.memberID(r, s.clock.Now()); ok {
+		redirect(w, r, "/account", "", "")
+		return
+	}
+	s.render(w, r, "login", "Sign in", nil)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	now := s.clock.Now()
+	memberID := strings.TrimSpace(r.PostFormValue("member_id"))
+	pin := r.PostFormValue("pin")
+
+	if s.throttle.blocked(memberID, now) {
+		redirect(w, r, "/login", "error", "locked")
+		return
+	}
+
+	m, err := s.services.Members.Authenticate(r.Context(), memberID, pin)
+	if errors.Is(err, shared.ErrInvalidCredentials) {
+		s.throttle.fail(memberID, now)
+		redirect(w, r, "/login", "error", "credentials")
+		return
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	s.throttle.succeed(m.ID)
+	s.sessions.issue(w, m.ID, now)
+	redirect(w, r, "/account", "", "")
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	if !s.sessions.validCSRF(r) {
+		redirect(w, r, "/", "", "")
+		return
+	}
+	s.sessions.clear(w)
+	redirect(w, r, "/", "notice", "logged_out")
+}
+
+func (s *Server) account(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := s.sessions.memberID(r, s.clock.Now())
+	if !ok {
+		redirect(w, r, "/login", "notice", "login_required")
+		return
+	}
+
+	m, err := s.services.Members.GetByID(r.Context(), memberID)
+	if errors.Is(err, shared.ErrNotFound) {
+		s.sessions.clear(w)
+		redirect(w, r, "/login", "notice", "session_expired")
+		return
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	view, err := s.accountView(r.Context(), m)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	s.render(w, r, "account", "My account", view)
+}
+
+func (s *Server) renew(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := s.requireMember(w, r)
+	if !ok {
+		return
+	}
+
+	l, err := s.services.Loans.GetByID(r.Context(), r.PathValue("id"))
+	if err == nil && l.MemberID != memberID {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	_, err = s.services.Loans.Renew(r.Context(), dto.RenewLoanInput{LoanID: l.ID})
+	if code, known := errorCode(err); known {
+		redirect(w, r, "/account", "error", code)
+		return
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	redirect(w, r, "/account", "notice", "renewed")
+}
+
+func (s *Server) cancelHold(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := s.requireMember(w, r)
+	if !ok {
+		return
+	}
+
+	h, err := s.services.Holds.GetByID(r.Context(), r.PathValue("id"))
+	if err == nil && h.MemberID != memberID {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	_, err = s.services.Holds.Cancel(r.Context(), h.ID)
+	if code, known := errorCode(err); known {
+		redirect(w, r, "/account", "error", code)
+		return
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	redirect(w, r, "/account", "notice", "hold_cancelled")
+}
+
+// requireMember guards state-changing posts: the caller must hold a live
+// session and echo its CSRF token.
+func (s *Server) requireMember(w http.ResponseWriter, r *http.Request) (string, bool) {
+	memberID, ok := s.sessions.memberID(r, s.clock.Now())
+	if !ok {
+		redirect(w, r, "/login", "notice", "login_required")
+		return "", false
+	}
+	if !s.sessions.validCSRF(r) {
+		redirect(w, r, "/account", "error", "forbidden")
+		return "", false
+	}
+	return memberID, true
+}
+
+func (s *Server) accountView(ctx context.Context, m member.Member) (accountView, error) {
+	view := accountView{Member: m}
+	titles := map[string]string{}
+
+	title := func(bookID string) string {
+		if t, ok := titles[bookID]; ok {
+			return t
+		}
+		t := bookID
+		if b, err := s.services.Books.GetByID(ctx, bookID); err == nil {
+			t = b.Title
+		}
+		titles[bookID] = t
+		return t
+	}
+
+	loans, err := s.services.Loans.List(ctx)
+	if err != nil {
+		return accountView{}, err
+	}
+
+	now := s.clock.Now()
+	for _, l := range loans {
+		if l.MemberID != m.ID || l.Status != loan.StatusActive {
+			continue
+		}
+
+		lv := loanView{Loan: l, Title: l.CopyID, Overdue: l.IsOverdue(now)}
+		if c, err := s.services.Copies.GetByID(ctx, l.CopyID); err == nil {
+			lv.Title = title(c.BookID)
+		}
+		if err := s.services.Loans.CanRenew(l); err != nil {
+			code, _ := errorCode(err)
+			lv.Reason = errorText(code)
+		} else {
+			lv.CanRenew = true
+		}
+		view.Loans = append(view.Loans, lv)
+	}
+	sortLoans(view.Loans)
+
+	holds, err := s.services.Holds.ListByMember(ctx, m.ID)
+	if err != nil {
+		return accountView{}, err
+	}
+	for _, h := range holds {
+		if !h.IsWaiting() {
+			continue
+		}
+
+		queue, err := s.services.Holds.Queue(ctx, h.BookID)
+		if err != nil {
+			return accountView{}, err
+		}
+
+		hv := holdView{Hold: h, Title: title(h.BookID)}
+		for i, q := range queue {
+			if q.ID == h.ID {
+				hv.Position = i + 1
+			}
+		}
+		view.Holds = append(view.Holds, hv)
+	}
+
+	return view, nil
+}
+
+func (s *Server) availability(ctx context.Context) (map[string]availability, error) {
+	copies, err := s.services.Copies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byBook := map[string][]copy.Copy{}
+	for _, c := range copies {
+		byBook[c.BookID] = append(byBook[c.BookID], c)
+	}
+
+	out := make(map[string]availability, len(byBook))
+	for id, cs := range byBook {
+		out[id] = summarize(cs)
+	}
+	return out, nil
+}
+
+func (s *Server) copiesOf(ctx context.Context, bookID string) ([]copy.Copy, error) {
+	copies, err := s.services.Copies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]copy.Copy, 0)
+	for _, c := range copies {
+		if c.BookID == bookID {
+			out = append(out, c)
+		}
+	}
+	sortCopies(out)
+	return out, nil
+}
+
+func summarize(copies []copy.Copy) availability {
+	var a availability
+	for _, c := range copies {
+		switch c.Status {
+		case copy.StatusAvailable:
+			a.Total++
+			a.Available++
+		case copy.StatusLoaned:
+			a.Total++
+			a.OnLoan++
+		}
+	}
+	return a
+}
+
+func sortLoans(loans []loanView) {
+	sort.Slice(loans, func(i, j int) bool { return loans[i].Loan.DueAt.Before(loans[j].Loan.DueAt) })
+}
+
+func sortCopies(copies []copy.Copy) {
+	sort.Slice(copies, func(i, j int) bool {
+		if copies[i].Branch != copies[j].Branch {
+			return copies[i].Branch < copies[j].Branch
+		}
+		if c := copy.CompareCallNumbers(copies[i].CallNumber, copies[j].CallNumber); c != 0 {
+			return c < 0
+		}
+		return copies[i].Barcode+copies[i].ID < copies[j].Barcode+copies[j].ID
+	})
+}
@@ -0,0 +1,210 @@
+package opac
+
+import (
+	"crypto/rand"
+	"embed"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/ports"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+const searchLimit = 50
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var pages = []string{"search", "book", "login", "account"}
+
+type Services struct {
+	Books   usecase.BookService
+	Copies  usecase.CopyService
+	Members usecase.MemberService
+	Loans   usecase.LoanService
+	Holds   usecase.HoldService
+}
+
+type Server struct {
+	services  Services
+	clock     ports.Clock
+	logger    *slog.Logger
+	sessions  sessions
+	throttle  *loginThrottle
+	templates map[string]*template.Template
+	mux       *http.ServeMux
+}
+
+// NewServer builds the OPAC handler. An empty secret generates a random
+// session key, which logs every patron out when the process restarts.
+func NewServer(services Services, clock ports.Clock, logger *slog.Logger, secret []byte) (*Server, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := map[string]*template.Template{}
+	for _, name := range pages {
+		t, err := template.New("layout.html").Funcs(templateFuncs).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+
+	s := &Server{
+		services:  services,
+		clock:     clock,
+		logger:    logger,
+		sessions:  sessions{key: secret},
+		throttle:  newLoginThrottle(),
+		templates: templates,
+		mux:       http.NewServeMux(),
+	}
+	s.routes()
+	return s, nil
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /{$}", s.search)
+	s.mux.HandleFunc("GET /books/{id}", s.book)
+	s.mux.HandleFunc("POST /books/{id}/hold", s.placeHold)
+	s.mux.HandleFunc("GET /login", s.loginForm)
+	s.mux.HandleFunc("POST /login", s.login)
+	s.mux.HandleFunc("POST /logout", s.logout)
+	s.mux.HandleFunc("GET /account", s.account)
+	s.mux.HandleFunc("POST /account/loans/{id}/renew", s.renew)
+	s.mux.HandleFunc("POST /account/holds/{id}/cancel", s.cancelHold)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; style-src 'unsafe-inline'")
+	s.mux.ServeHTTP(w, r)
+}
+
+type page struct {
+	Title    string
+	MemberID string
+	CSRF     string
+	Notice   string
+	Error    string
+	Data     any
+}
+
+func (s *Server) render(w http.ResponseWriter, r *http.Request, name, title string, data any) {
+	memberID, _ := s.sessions.memberID(r, s.clock.Now())
+	p := page{
+		Title:    title,
+		MemberID: memberID,
+		CSRF:     s.sessions.csrfToken(r),
+		Notice:   notices[r.URL.Query().Get("notice")],
+		Error:    r.URL.Query().Get("error"),
+		Data:     data,
+	}
+	if p.Error != "" {
+		p.Error = errorText(p.Error)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.templates[name].Execute(w, p); err != nil {
+		s.logger.Error("render opac page", "page", name, "error", err)
+	}
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, shared.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	s.logger.Error("opac request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	http.Error(w, "Something went wrong. Please ask library staff for help.", http.StatusInternalServerError)
+}
+
+func redirect(w http.ResponseWriter, r *http.Request, path, key, value string) {
+	if key != "" {
+		path += "?" + url.Values{key: {value}}.Encode()
+	}
+	http.Redirect(w, r, path, http.StatusSeeOther)
+}
+
+var notices = map[string]string{
+	"renewed":         "Loan renewed.",
+	"hold_placed":     "Hold placed. We will let you know when a copy is ready.",
+	"hold_cancelled":  "Hold cancelled.",
+	"logged_out":      "You have been signed out.",
+	"login_required":  "Please sign in to continue.",
+	"session_expired": "Your session has expired. Please sign in again.",
+}
+
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{shared.ErrInvalidCredentials, "credentials"},
+	{shared.ErrRenewalLimit, "renewal_limit"},
+	{shared.ErrLoanAlreadyOverdue, "overdue"},
+	{shared.ErrLoanAlreadyClosed, "closed"},
+	{shared.ErrDuplicateHold, "duplicate_hold"},
+	{shared.ErrHoldNotAllowed, "hold_not_allowed"},
+	{shared.ErrHoldClosed, "hold_closed"},
+	{shared.ErrMemberNotEligible, "not_eligible"},
+	{shared.ErrMembershipExpired, "expired"},
+}
+
+var errorTexts = map[string]string{
+	"credentials":      "Member ID or PIN is incorrect.",
+	"locked":           "Too many failed attempts. Please try again later.",
+	"renewal_limit":    "This loan has already been renewed the maximum number of times.",
+	"overdue":          "Overdue loans cannot be renewed. Please return the item.",
+	"closed":           "This loan has already been returned.",
+	"duplicate_hold":   "You already have a hold on this title.",
+	"hold_not_allowed": "This title cannot be placed on hold.",
+	"hold_closed":      "This hold is no longer active.",
+	"not_eligible":     "Your account cannot borrow at the moment. Please contact the library.",
+	"expired":          "Your membership has expired. Please renew it at the library.",
+	"forbidden":        "That request could not be verified. Please try again.",
+}
+
+func errorCode(err error) (string, bool) {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return e.code, true
+		}
+	}
+	return "", false
+}
+
+func errorText(code string) string {
+	if text, ok := errorTexts[code]; ok {
+		return text
+	}
+	return "Something went wrong."
+}
+
+var templateFuncs = template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2 Jan 2006") },
+	"join": strings.Join,
+	"copyStatus": func(s copy.Status) string {
+		switch s {
+		case copy.StatusAvailable:
+			return "Available"
+		case copy.StatusLoaned:
+			return "On loan"
+		case copy.StatusInTransit:
+			return "In transit"
+		default:
+			return "Unavailable"
+		}
+	},
+}
@@ -0,0 +1,223 @@
+package opac_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/id"
+	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
+	"github.com/mibienpanjoe/LMS-bit/internal/ui/opac"
+)
+
+var csrfPattern = regexp.MustCompile(`name="csrf" value="([0-9a-f]+)"`)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+type fixture struct {
+	server *httptest.Server
+	bookID string
+	loanID string
+}
+
+func TestMemberRenewsAndPlacesHold(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	client := newClient(t)
+
+	body := get(t, client, f.server.URL+"/?q=go+programming")
+	if !strings.Contains(body, "The Go Programming Language") || !strings.Contains(body, "All 1 on loan") {
+		t.Fatalf("expected title with availability in search results:\n%s", body)
+	}
+
+	body = post(t, client, f.server.URL+"/login", url.Values{"member_id": {"m-1"}, "pin": {"0000"}})
+	if !strings.Contains(body, "Member ID or PIN is incorrect.") {
+		t.Fatalf("expected credentials error:\n%s", body)
+	}
+
+	body = post(t, client, f.server.URL+"/login", url.Values{"member_id": {"m-1"}, "pin": {"4321"}})
+	if !strings.Contains(body, "Ann Reader") || !strings.Contains(body, ">Renew</button>") {
+		t.Fatalf("expected account page with renew button:\n%s", body)
+	}
+	csrf := csrfPattern.FindStringSubmatch(body)[1]
+
+	body = post(t, client, f.server.URL+"/account/loans/"+f.loanID+"/renew", url.Values{})
+	if !strings.Contains(body, "That request could not be verified.") {
+		t.Fatalf("expected renew without csrf to be rejected:\n%s", body)
+	}
+
+	body = post(t, client, f.server.URL+"/account/loans/"+f.loanID+"/renew", url.Values{"csrf": {csrf}})
+	if !strings.Contains(body, "Loan renewed.") || strings.Contains(body, ">Renew</button>") {
+		t.Fatalf("expected renewed loan without further renew button:\n%s", body)
+	}
+
+	body = post(t, client, f.server.URL+"/books/"+f.bookID+"/hold", url.Values{"csrf": {csrf}})
+	if !strings.Contains(body, "Hold placed.") || !strings.Contains(body, "<td>1</td>") {
+		t.Fatalf("expected hold in first queue position:\n%s", body)
+	}
+
+	body = post(t, client, f.server.URL+"/books/"+f.bookID+"/hold", url.Values{"csrf": {csrf}})
+	if !strings.Contains(body, "You already have a hold on this title.") {
+		t.Fatalf("expected duplicate hold message:\n%s", body)
+	}
+}
+
+func TestOtherMembersCannotRenewLoan(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	client := newClient(t)
+
+	body := post(t, client, f.server.URL+"/login", url.Values{"member_id": {"m-2"}, "pin": {"8765"}})
+	csrf := csrfPattern.FindStringSubmatch(body)[1]
+
+	resp, err := client.PostForm(f.server.URL+"/account/loans/"+f.loanID+"/renew", url.Values{"csrf": {csrf}})
+	if err != nil {
+		t.Fatalf("post renew: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for another member's loan got %d", resp.StatusCode)
+	}
+}
+
+func TestLoginLocksAfterRepeatedFailures(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	client := newClient(t)
+
+	for i := 0; i < 5; i++ {
+		post(t, client, f.server.URL+"/login", url.Values{"member_id": {"m-1"}, "pin": {"9999"}})
+	}
+
+	body := post(t, client, f.server.URL+"/login", url.Values{"member_id": {"m-1"}, "pin": {"4321"}})
+	if !strings.Contains(body, "Too many failed attempts.") {
+		t.Fatalf("expected lockout message:\n%s", body)
+	}
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	ctx := context.Background()
+	store, err := jsonstore.Open(filepath.Join(t.TempDir(), "storage.json"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	clock := fixedClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	bookRepo := jsonstore.NewBookRepository(store)
+	copyRepo := jsonstore.NewCopyRepository(store)
+	memberRepo := jsonstore.NewMemberRepository(store)
+	idGen := id.NewGenerator()
+
+	services := opac.Services{
+		Books:   usecase.NewBookService(bookRepo, idGen, clock, book.Policy{UniqueISBN: true}, nil),
+		Copies:  usecase.NewCopyService(copyRepo, nil, idGen, clock, copy.Policy{}, nil),
+		Members: usecase.NewMemberService(memberRepo, nil, idGen, clock, member.Policy{}, nil),
+		Loans: usecase.NewLoanService(
+			jsonstore.NewLoanRepository(store),
+			copyRepo,
+			memberRepo,
+			idGen,
+			clock,
+			loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
+			nil,
+		),
+		Holds: usecase.NewHoldService(jsonstore.NewHoldRepository(store), bookRepo, memberRepo, idGen, clock),
+	}
+
+	b, err := services.Books.Create(ctx, dto.CreateBookInput{Title: "The Go Programming Language", Authors: []string{"Alan Donovan"}})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	c, err := services.Copies.Create(ctx, dto.CreateCopyInput{BookID: b.ID, Barcode: "BC-1"})
+	if err != nil {
+		t.Fatalf("create copy: %v", err)
+	}
+	for _, m := range []struct{ id, name, pin string }{{"m-1", "Ann Reader", "4321"}, {"m-2", "Bo Other", "8765"}} {
+		if _, err := services.Members.Register(ctx, dto.RegisterMemberInput{ID: m.id, Name: m.name}); err != nil {
+			t.Fatalf("register member: %v", err)
+		}
+		if _, err := services.Members.SetPIN(ctx, m.id, m.pin); err != nil {
+			t.Fatalf("set pin: %v", err)
+		}
+	}
+	l, err := services.Loans.Issue(ctx, dto.IssueLoanInput{CopyID: c.ID, MemberID: "m-1"})
+	if err != nil {
+		t.Fatalf("issue loan: %v", err)
+	}
+
+	site, err := opac.NewServer(services, clock, slog.New(slog.NewTextHandler(io.Discard, nil)), []byte("test-secret"))
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	srv := httptest.NewServer(site)
+	t.Cleanup(srv.Close)
+	return fixture{server: srv, bookID: b.ID, loanID: l.ID}
+}
+
+func newClient(t *testing.T) *http.Client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookie jar: %v", err)
+	}
+	return &http.Client{Jar: jar}
+}
+
+func get(t *testing.T, client *http.Client, target string) string {
+	t.Helper()
+
+	resp, err := client.Get(target)
+	if err != nil {
+		t.Fatalf("get %s: %v", target, err)
+	}
+	return readBody(t, resp)
+}
+
+func post(t *testing.T, client *http.Client, target string, form url.Values) string {
+	t.Helper()
+
+	resp, err := client.PostForm(target, form)
+	if err != nil {
+		t.Fatalf("post %s: %v", target, err)
+	}
+	return readBody(t, resp)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return string(raw)
+}
@@ -0,0 +1,141 @@
+package opac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookie   = "opac_session"
+	sessionLifetime = 30 * time.Minute
+	maxFailedLogins = 5
+	lockoutPeriod   = 15 * time.Minute
+)
+
+// sessions issues stateless cookies of the form
+// base64(memberID|expiry).signature, signed with an HMAC key held in memory.
+type sessions struct {
+	key []byte
+}
+
+func (s sessions) issue(w http.ResponseWriter, memberID string, now time.Time) {
+	expires := now.Add(sessionLifetime)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(memberID + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    payload + "." + s.sign(payload),
+		Path:     "/",
+		MaxAge:   int(sessionLifetime.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s sessions) clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s sessions) memberID(r *http.Request, now time.Time) (string, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+
+	payload, sig, ok := strings.Cut(c.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return "", false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", false
+	}
+
+	id, exp, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return "", false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.After(time.Unix(unix, 0)) {
+		return "", false
+	}
+
+	return id, true
+}
+
+// csrfToken binds form posts to the current session cookie.
+func (s sessions) csrfToken(r *http.Request) string {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+	return s.sign("csrf|" + c.Value)
+}
+
+func (s sessions) validCSRF(r *http.Request) bool {
+	want := s.csrfToken(r)
+	return want != "" && hmac.Equal([]byte(r.PostFormValue("csrf")), []byte(want))
+}
+
+func (s sessions) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type loginThrottle struct {
+	mu       sync.Mutex
+	failures map[string]int
+	locked   map[string]time.Time
+}
+
+func newLoginThrottle() *loginThrottle {
+	return &loginThrottle{failures: map[string]int{}, locked: map[string]time.Time{}}
+}
+
+func (t *loginThrottle) blocked(memberID string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until, ok := t.locked[memberID]
+	if !ok {
+		return false
+	}
+	if now.After(until) {
+		delete(t.locked, memberID)
+		return false
+	}
+	return true
+}
+
+func (t *loginThrottle) fail(memberID string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures[memberID]++
+	if t.failures[memberID] >= maxFailedLogins {
+		t.locked[memberID] = now.Add(lockoutPeriod)
+		delete(t.failures, memberID)
+	}
+}
+
+func (t *loginThrottle) succeed(memberID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, memberID)
+}
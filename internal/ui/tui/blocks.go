@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startBlockForm asks why the selected member is blocked and, optionally,
+// until when. Toggling a blocked member with x lifts its blocks again.
+func (m *Model) startBlockForm() tea.Cmd {
+	id := m.selectedID()
+	if id == "" {
+		return m.setStatus("Select a member first", statusInfo)
+	}
+
+	mm, err := m.services.Members.GetByID(m.ctx, id)
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+
+	m.activeForm = newForm(formBlockMember, mm.ID, "Block "+mm.Name, []string{"Reason", "Note", "Until (YYYY-MM-DD, blank for no end)"}, nil)
+	m.validateActiveForm()
+	return nil
+}
+
+// parseBlockUntil reads an optional block end date as local midnight.
+func parseBlockUntil(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("until must be a date such as 2026-12-31")
+	}
+	return t, nil
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+func TestBlockFormBlocksAndToggleUnblocks(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	model.config.Operator = "amy"
+	if _, err := model.services.Members.Register(model.ctx, dto.RegisterMemberInput{ID: "m-1", Name: "Ann Lee"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			next, _ := model.Update(k)
+			model = next.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	model.route = routeMembers
+	model.refreshRouteData()
+	press(runes("K"))
+	if model.activeForm == nil || model.activeForm.kind != formBlockMember {
+		t.Fatalf("expected the block form to open")
+	}
+	if got := model.activeForm.errors[0]; got != "reason is required" {
+		t.Fatalf("expected the reason to be required, got %q", got)
+	}
+
+	model.activeForm.fields[0].SetValue("lost card")
+	model.activeForm.fields[2].SetValue("next week")
+	model.validateActiveForm()
+	if got := model.activeForm.errors[2]; !strings.HasPrefix(got, "until must be a date") {
+		t.Fatalf("expected a date error, got %q", got)
+	}
+
+	model.activeForm.fields[2].SetValue("")
+	model.activeForm.focus = len(model.activeForm.fields) - 1
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.activeForm != nil {
+		t.Fatalf("expected the form to close, errors %v", model.activeForm.errors)
+	}
+
+	m, _ := model.services.Members.GetByID(model.ctx, "m-1")
+	if m.Status != member.StatusBlocked || m.Blocks[0].Reason != "lost card" || m.Blocks[0].CreatedBy != "amy" {
+		t.Fatalf("unexpected member after blocking %+v", m)
+	}
+	if got := model.table.Rows()[0][7]; got != "blocked: lost card" {
+		t.Fatalf("expected the reason in the status column, got %q", got)
+	}
+
+	press(runes("x"), runes("y"))
+	if m, _ := model.services.Members.GetByID(model.ctx, "m-1"); m.Status != member.StatusActive || len(m.Blocks) != 1 || m.Blocks[0].LiftedBy != "amy" {
+		t.Fatalf("expected toggle to unblock, got %+v", m)
+	}
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	dashboardDays       = 30
+	dashboardRefresh    = 30 * time.Second
+	dashboardTopOverdue = 5
+	// dashboardPanelHeight is the number of lines the cards and trends take
+	// above the table.
+	dashboardPanelHeight = 7
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+type dashboardTickMsg struct{}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardRefresh, func(time.Time) tea.Msg {
+		return dashboardTickMsg{}
+	})
+}
+
+// refreshDashboard reloads the metrics when the dashboard is on screen and
+// the user is not in the middle of a form, confirmation or search.
+func (m Model) refreshDashboard() (tea.Model, tea.Cmd) {
+	if m.route == routeDashboard && m.activeForm == nil && !m.confirming && !m.searching {
+		cursor := m.table.Cursor()
+		m.refreshRouteData()
+		m.table.SetCursor(min(cursor, len(m.table.Rows())-1))
+	}
+	return m, dashboardTick()
+}
+
+func sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = v * (len(sparkLevels) - 1) / peak
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func (m Model) dashboardTable() ([]table.Column, []table.Row) {
+	cols := []table.Column{{Title: "Top Overdue Member", Width: 20}, {Title: "Name", Width: 22}, {Title: "Items", Width: 6}, {Title: "Oldest Due", Width: 12}, {Title: "Days", Width: 6}}
+
+	groups, _ := m.services.Loans.OverdueByMember(m.ctx, m.branch)
+	if len(groups) > dashboardTopOverdue {
+		groups = groups[:dashboardTopOverdue]
+	}
+
+	rows := make([]table.Row, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, table.Row{
+			g.Member.ID,
+			g.Member.Name,
+			strconv.Itoa(len(g.Items)),
+			g.OldestDue.Format("2006-01-02"),
+			strconv.Itoa(g.Items[0].DaysOverdue),
+		})
+	}
+	if len(rows) == 0 {
+		rows = []table.Row{{"-", "No overdue loans", "", "", ""}}
+	}
+
+	return cols, rows
+}
+
+func (m Model) renderDashboard() string {
+	d := m.dashboard
+	cards := []string{
+		m.renderCard("Available", fmt.Sprintf("%d/%d", d.AvailableCopies, d.Copies)),
+		m.renderCard("Active members", fmt.Sprintf("%d/%d", d.ActiveMembers, d.Members)),
+		m.renderCard("Loans today", strconv.Itoa(d.LoansToday)),
+		m.renderCard("Returns today", strconv.Itoa(d.ReturnsToday)),
+		m.renderCard("Overdue", strconv.Itoa(d.Overdue)),
+	}
+
+	trends := []string{
+		m.styles.CardLabel.Render(fmt.Sprintf("Loans   %dd ", dashboardDays)) + m.styles.Spark.Render(sparkline(d.Issued)) + fmt.Sprintf(" %d", sum(d.Issued)),
+		m.styles.CardLabel.Render(fmt.Sprintf("Returns %dd ", dashboardDays)) + m.styles.Spark.Render(sparkline(d.Returned)) + fmt.Sprintf(" %d", sum(d.Returned)),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, cards...),
+		m.styles.Trends.Render(strings.Join(trends, "\n")),
+	)
+}
+
+func (m Model) renderCard(label, value string) string {
+	return m.styles.Card.Render(m.styles.CardLabel.Render(label) + "\n" + m.styles.CardValue.Render(value))
+}
@@ -0,0 +1,33 @@
+package tui
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   []int
+		want string
+	}{
+		{in: []int{0, 0, 0}, want: "▁▁▁"},
+		{in: []int{0, 1, 2, 4}, want: "▁▂▄█"},
+		{in: []int{3, 3}, want: "██"},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.in); got != tt.want {
+			t.Fatalf("sparkline(%v) = %q want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDashboardTickReschedules(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	next, cmd := model.Update(dashboardTickMsg{})
+	if cmd == nil {
+		t.Fatalf("expected the dashboard tick to schedule the next refresh")
+	}
+	_ = next.(Model).View()
+}
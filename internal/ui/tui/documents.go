@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/document"
+)
+
+// printReceipt writes the receipt for the selected loan's visit: its
+// checkout, or its return once it is back.
+func (m *Model) printReceipt() tea.Cmd {
+	id := m.selectedID()
+	if id == "" {
+		return m.setStatus("Select a loan first", statusInfo)
+	}
+
+	r, err := m.services.Documents.ReceiptFor(m.ctx, id)
+	var path string
+	if err == nil {
+		path, err = m.writeDocument("documents", string(r.Kind)+"-"+r.Member.ID, func(w io.Writer, format string) error {
+			return document.Receipt(w, format, m.config.AppName, r)
+		})
+	}
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+	return m.setStatus(fmt.Sprintf("Receipt for %d items written to %s", len(r.Items), path), statusSuccess)
+}
+
+// printNotices writes one overdue letter for every member listed in the
+// overdue report.
+func (m *Model) printNotices() tea.Cmd {
+	var members []string
+	for _, row := range m.table.Rows() {
+		id := strings.TrimPrefix(strings.TrimPrefix(row[0], groupCollapsed), groupExpanded)
+		if id != row[0] {
+			members = append(members, id)
+		}
+	}
+	if len(members) == 0 {
+		return m.setStatus("No overdue loans", statusInfo)
+	}
+
+	notices, err := m.services.Documents.OverdueNotices(m.ctx, m.branch, members)
+	var path string
+	if err == nil {
+		path, err = m.writeDocument("documents", "overdue", func(w io.Writer, format string) error {
+			return document.OverdueNotices(w, format, m.config.AppName, notices)
+		})
+	}
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+	return m.setStatus(fmt.Sprintf("%d overdue notices written to %s", len(notices), path), statusSuccess)
+}
+
+// writeDocument renders into a time-stamped file in dir next to the data
+// file, as a PDF unless the configured document format is text.
+func (m Model) writeDocument(dir, name string, render func(io.Writer, string) error) (string, error) {
+	format, ext := "pdf", ".pdf"
+	if strings.EqualFold(m.config.DocumentFormat, "text") {
+		format, ext = "text", ".txt"
+	}
+	return m.writeOutput(dir, name, ext, func(w io.Writer) error {
+		return render(w, format)
+	})
+}
+
+// writeOutput writes a time-stamped file into dir, which sits next to the
+// data file and is created when missing.
+func (m Model) writeOutput(dir, name, ext string, write func(io.Writer) error) (path string, err error) {
+	dir = filepath.Join(filepath.Dir(m.config.StoragePath), dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path = filepath.Join(dir, name+"-"+time.Now().Format("20060102-150405")+ext)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return path, write(file)
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+// The duplicate review list lives under Members and is kept in score
+// order.
+const routeDuplicates route = "Members/duplicates"
+
+func (m Model) membersView() route {
+	if m.duplicates {
+		return routeDuplicates
+	}
+	return routeMembers
+}
+
+func (m Model) membersTabLabel() string {
+	if m.duplicates {
+		return string(routeMembers) + " (duplicates)"
+	}
+	return string(routeMembers)
+}
+
+// duplicatesTable lists likely duplicate pairs. The first column is the
+// member a merge keeps, the second the one folded into it; x swaps them.
+func (m Model) duplicatesTable() ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "Keep", Width: 12}, {Title: "Merge", Width: 12}, {Title: "Keep Name", Width: 20}, {Title: "Merge Name", Width: 20}, {Title: "Score", Width: 6}, {Title: "Matched On", Width: 30}}
+
+	dups, err := m.services.Duplicates.Find(m.ctx)
+	rows := make([]table.Row, 0, len(dups))
+	for _, d := range dups {
+		keep, merge := d.Keep, d.Merge
+		if m.swapped[pairKey(keep.ID, merge.ID)] {
+			keep, merge = merge, keep
+		}
+		rows = append(rows, table.Row{keep.ID, merge.ID, keep.Name, merge.Name, fmt.Sprintf("%.0f%%", d.Score*100), strings.Join(d.Reasons, ", ")})
+	}
+	rows = filterRows(rows, m.searchQuery, len(cols))
+
+	switch {
+	case len(rows) == 0 && len(dups) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "", "No likely duplicates", "", "", ""}}
+	}
+
+	return cols, rows, err
+}
+
+func (m *Model) toggleDuplicates() tea.Cmd {
+	m.duplicates = !m.duplicates
+	m.refreshRouteData()
+	if m.duplicates {
+		return m.setStatus("Duplicate review: enter merges the second member into the first, x swaps them", statusInfo)
+	}
+	return m.setStatus("Members", statusInfo)
+}
+
+func (m *Model) swapSelectedPair() tea.Cmd {
+	keep, merge := m.selectedPair()
+	if keep == "" {
+		return m.setStatus("Select a pair first", statusInfo)
+	}
+
+	key := pairKey(keep, merge)
+	m.swapped[key] = !m.swapped[key]
+	cursor := m.table.Cursor()
+	m.refreshRouteData()
+	m.table.SetCursor(cursor)
+	return m.setStatus("Keeping "+merge+", merging "+keep, statusInfo)
+}
+
+func (m *Model) startMergeConfirm() tea.Cmd {
+	if keep, _ := m.selectedPair(); keep == "" {
+		return m.setStatus("Select a pair first", statusInfo)
+	}
+
+	m.confirming = true
+	m.confirmAct = confirmMergeMember
+	return nil
+}
+
+func (m Model) mergeSelectedPair() (dto.MergeResult, error) {
+	keep, merge := m.selectedPair()
+	return m.services.Duplicates.Merge(m.ctx, dto.MergeMembersInput{FromID: merge, IntoID: keep})
+}
+
+func (m Model) selectedPair() (string, string) {
+	row := m.table.SelectedRow()
+	if len(row) < 2 || row[0] == "-" {
+		return "", ""
+	}
+	return row[0], row[1]
+}
+
+func pairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+func TestDuplicateReviewMergesSelectedPair(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	for _, in := range []dto.RegisterMemberInput{
+		{ID: "m-1", Name: "Joe Smith", Email: "joe.smith@example.com"},
+		{ID: "m-2", Name: "Smith, Joe", Email: "joe.smith@example.com"},
+		{ID: "m-3", Name: "Ann Lee"},
+	} {
+		if _, err := model.services.Members.Register(model.ctx, in); err != nil {
+			t.Fatalf("register %s: %v", in.ID, err)
+		}
+	}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			next, _ := model.Update(k)
+			model = next.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	model.route = routeMembers
+	press(runes("v"))
+	if got := model.membersTabLabel(); got != "Members (duplicates)" {
+		t.Fatalf("unexpected tab label %q", got)
+	}
+	assertFirstColumn(t, model, "m-1")
+
+	press(runes("x"))
+	if keep, merge := model.selectedPair(); keep != "m-2" || merge != "m-1" {
+		t.Fatalf("expected x to swap the pair, got keep=%s merge=%s", keep, merge)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !model.confirming || model.confirmAct != confirmMergeMember {
+		t.Fatalf("expected enter to ask for confirmation")
+	}
+	press(runes("y"))
+
+	merged, err := model.services.Members.GetByID(model.ctx, "m-1")
+	if err != nil {
+		t.Fatalf("get merged member: %v", err)
+	}
+	if merged.MergedInto != "m-2" || merged.Status != member.StatusInactive {
+		t.Fatalf("expected m-1 to be merged into m-2, got %+v", merged)
+	}
+	if rows := model.table.Rows(); len(rows) != 1 || rows[0][0] != "-" {
+		t.Fatalf("expected no duplicates left, got %v", rows)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.duplicates {
+		t.Fatalf("expected esc to return to the member list")
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// Fields of the issue form after the copy and member.
+const (
+	issueDueField      = 2
+	issueOverrideField = 3
+)
+
+// issueDueLabel names the loan periods the desk may pick.
+func (m Model) issueDueLabel() string {
+	names := make([]string, 0, len(m.services.Loans.Periods()))
+	for _, p := range m.services.Loans.Periods() {
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return "Due (blank, date or date time)"
+	}
+	return "Due (blank, date, date time or " + strings.Join(names, "/") + ")"
+}
+
+// checkIssueForm lists under the issue form every rule the loan would
+// break, so the desk can explain a refusal before submitting, and when the
+// loan would fall due.
+func (m *Model) checkIssueForm() {
+	f := m.activeForm
+	f.notes = nil
+
+	due, dueErr := m.services.Loans.DueAt(loan.ParseTerms(f.fields[issueDueField].Value(), time.Local))
+	if dueErr != nil {
+		f.errors[issueDueField] = dueErr.Error()
+	}
+
+	copyID, memberID := strings.TrimSpace(f.fields[0].Value()), strings.TrimSpace(f.fields[1].Value())
+	if copyID == "" || memberID == "" {
+		return
+	}
+
+	e, err := m.services.Loans.CheckEligibility(m.ctx, copyID, memberID)
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		if _, err := m.services.Copies.GetByID(m.ctx, copyID); err != nil {
+			f.errors[0] = "no copy with this ID"
+		} else {
+			f.errors[1] = "no member with this ID or card"
+		}
+	case err != nil:
+		f.notes = []string{err.Error()}
+	case e.Eligible():
+		f.notes = []string{fmt.Sprintf("%s may borrow (%d of %d loans)", e.Member.Name, e.ActiveLoans, e.MaxLoans)}
+		if dueErr == nil {
+			f.notes = append(f.notes, "due "+due.Local().Format("Mon 2 Jan 2006 15:04"))
+		}
+	default:
+		all := true
+		for _, v := range e.Violations {
+			f.notes = append(f.notes, v.Detail)
+			all = all && v.Rule.Overridable()
+		}
+		if all && len(f.fields) > issueOverrideField {
+			f.notes = append(f.notes, "enter an override reason to issue anyway")
+		}
+		f.notes = append(f.notes, dto.UncheckedNote)
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+func TestIssueFormListsFailedRulesBeforeSubmit(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	b, err := model.services.Books.Create(model.ctx, dto.CreateBookInput{Title: "Go", Authors: []string{"Alan Donovan"}})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if _, err := model.services.Copies.Create(model.ctx, dto.CreateCopyInput{ID: "c-1", BookID: b.ID}); err != nil {
+		t.Fatalf("create copy: %v", err)
+	}
+	if _, err := model.services.Members.Register(model.ctx, dto.RegisterMemberInput{ID: "m-1", Name: "Ann Lee"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	model.route = routeLoans
+	model.refreshRouteData()
+	next, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	model = next.(Model)
+	if model.activeForm == nil || model.activeForm.kind != formIssueLoan {
+		t.Fatalf("expected the issue form to open")
+	}
+
+	model.activeForm.fields[0].SetValue("c-1")
+	model.activeForm.fields[1].SetValue("m-9")
+	model.validateActiveForm()
+	if got := model.activeForm.errors[1]; got != "no member with this ID or card" {
+		t.Fatalf("expected an unknown member error, got %q", got)
+	}
+
+	model.activeForm.fields[1].SetValue("m-1")
+	model.validateActiveForm()
+	if len(model.activeForm.notes) != 2 || model.activeForm.notes[0] != "Ann Lee may borrow (0 of 3 loans)" || !strings.HasPrefix(model.activeForm.notes[1], "due ") {
+		t.Fatalf("expected the member to be eligible, got %v", model.activeForm.notes)
+	}
+
+	model.activeForm.fields[issueDueField].SetValue("fortnight")
+	model.validateActiveForm()
+	if got := model.activeForm.errors[issueDueField]; got != `unknown loan period "fortnight" (known: reserve)` {
+		t.Fatalf("expected an unknown period error, got %q", got)
+	}
+	model.activeForm.fields[issueDueField].SetValue("reserve")
+	model.validateActiveForm()
+	if got := model.activeForm.errors[issueDueField]; got != "" {
+		t.Fatalf("expected the reserve period to be accepted, got %q", got)
+	}
+
+	if _, err := model.services.Blocks.Block(model.ctx, dto.BlockMemberInput{MemberID: "m-1", Reason: "lost card", CreatedBy: "amy"}); err != nil {
+		t.Fatalf("block: %v", err)
+	}
+	model.validateActiveForm()
+	if len(model.activeForm.notes) != 2 || !strings.HasPrefix(model.activeForm.notes[0], "blocked: lost card (by amy") || model.activeForm.notes[1] != dto.UncheckedNote {
+		t.Fatalf("expected the block to be explained, got %v", model.activeForm.notes)
+	}
+}
@@ -13,6 +13,7 @@ type keyMap struct {
 	Loans      key.Binding
 	Reports    key.Binding
 	Settings   key.Binding
+	Webhooks   key.Binding
 	Search     key.Binding
 	Cancel     key.Binding
 	Add        key.Binding
@@ -23,7 +24,15 @@ type keyMap struct {
 	Renew      key.Binding
 	Return     key.Binding
 	Filter     key.Binding
+	Period     key.Binding
+	Expand     key.Binding
+	ShelfList  key.Binding
+	Branch     key.Binding
+	Print      key.Binding
+	SortColumn key.Binding
+	SortOrder  key.Binding
 	Archive    key.Binding
+	Block      key.Binding
 	Danger     key.Binding
 	Accept     key.Binding
 	Reject     key.Binding
@@ -71,6 +80,10 @@ func newKeyMap() keyMap {
 			key.WithKeys("6", "s"),
 			key.WithHelp("6", "settings"),
 		),
+		Webhooks: key.NewBinding(
+			key.WithKeys("7", "w"),
+			key.WithHelp("7", "webhooks"),
+		),
 		Search: key.NewBinding(
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
@@ -109,12 +122,44 @@ func newKeyMap() keyMap {
 		),
 		Filter: key.NewBinding(
 			key.WithKeys("f"),
-			key.WithHelp("f", "filter"),
+			key.WithHelp("f", "filter/report"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "expand/collapse"),
+		),
+		ShelfList: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "shelf list/duplicates"),
+		),
+		Branch: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "branch"),
+		),
+		Print: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "print"),
+		),
+		Period: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "report period"),
+		),
+		SortColumn: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort column"),
+		),
+		SortOrder: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "sort order"),
 		),
 		Archive: key.NewBinding(
 			key.WithKeys("x"),
 			key.WithHelp("x", "archive/toggle"),
 		),
+		Block: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "block member"),
+		),
 		Danger: key.NewBinding(
 			key.WithKeys("ctrl+d"),
 			key.WithHelp("ctrl+d", "confirm"),
@@ -136,9 +181,9 @@ func (k keyMap) ShortHelp() []key.Binding {
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.NextRoute, k.PrevRoute, k.Search, k.Cancel},
-		{k.Dashboard, k.Books, k.Members, k.Loans, k.Reports, k.Settings},
-		{k.Add, k.Edit, k.CreateCopy, k.UpdateCopy, k.Issue, k.Renew, k.Return, k.Filter, k.Archive},
+		{k.NextRoute, k.PrevRoute, k.Search, k.SortColumn, k.SortOrder, k.Cancel},
+		{k.Dashboard, k.Books, k.Members, k.Loans, k.Reports, k.Settings, k.Webhooks},
+		{k.Add, k.Edit, k.CreateCopy, k.UpdateCopy, k.Issue, k.Renew, k.Return, k.Filter, k.Period, k.Expand, k.ShelfList, k.Branch, k.Print, k.Archive, k.Block},
 		{k.Danger, k.Accept, k.Reject, k.ToggleHelp, k.Quit},
 	}
 }
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/label"
+)
+
+// labelSelection is the batch P prints: every copy of the selected title in
+// the titles view, otherwise the copies currently listed.
+func (m Model) labelSelection() (dto.LabelSelection, bool) {
+	if m.booksView() == routeBooks {
+		id := m.selectedID()
+		return dto.LabelSelection{BookID: id, Branch: m.branch}, id != ""
+	}
+
+	var ids []string
+	for _, row := range m.table.Rows() {
+		if len(row) > 0 && row[0] != "-" && row[0] != "" {
+			ids = append(ids, row[0])
+		}
+	}
+	return dto.LabelSelection{Copies: ids}, len(ids) > 0
+}
+
+// printLabels writes a PDF label sheet next to the data file.
+func (m *Model) printLabels() tea.Cmd {
+	sel, ok := m.labelSelection()
+	if !ok {
+		return m.setStatus("Nothing to print", statusInfo)
+	}
+
+	layout, ok := label.LayoutByName(m.config.LabelLayout)
+	if !ok {
+		return m.setStatus(statusErrorPrefix+fmt.Sprintf("unknown label layout %q", m.config.LabelLayout), statusInfo)
+	}
+
+	labels, err := usecase.NewLabelService(m.services.Books, m.services.Copies).Labels(m.ctx, sel)
+	if err == nil && len(labels) == 0 {
+		return m.setStatus("No copies to label", statusInfo)
+	}
+	var path string
+	if err == nil {
+		path, err = m.writeLabels(layout, labels)
+	}
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+
+	m.refreshRouteData()
+	return m.setStatus(fmt.Sprintf("%d labels written to %s", len(labels), path), statusSuccess)
+}
+
+func (m Model) writeLabels(layout label.Layout, labels []dto.Label) (string, error) {
+	return m.writeOutput("labels", "labels", ".pdf", func(w io.Writer) error {
+		return label.WritePDF(w, layout, label.KindBarcode, labels)
+	})
+}
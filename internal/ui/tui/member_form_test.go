@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/infra/id"
+	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
+	timeutil "github.com/mibienpanjoe/LMS-bit/internal/infra/time"
+)
+
+func TestValidateMemberFormErrors(t *testing.T) {
+	t.Parallel()
+
+	labels := []string{"Name", "Email", "Phone", "Type", "Card Number"}
+	tests := []struct {
+		name     string
+		kind     formKind
+		values   map[int]string
+		defaults map[int]string
+		want     map[int]string
+	}{
+		{
+			name:   "valid",
+			kind:   formMember,
+			values: map[int]string{0: "Ann", 1: "ann@example.com", 2: "06 12 34 56 78"},
+		},
+		{
+			name:   "bad email and phone",
+			kind:   formMember,
+			values: map[int]string{1: "ann@", 2: "12 ab"},
+			want: map[int]string{
+				0: "name is required",
+				1: member.ErrEmailInvalid.Error(),
+				2: member.ErrPhoneInvalid.Error(),
+			},
+		},
+		{
+			name:     "stored number left alone",
+			kind:     formEditMember,
+			values:   map[int]string{0: "Ann", 2: "12"},
+			defaults: map[int]string{0: "Ann", 2: "12"},
+		},
+		{
+			name:     "stored number edited",
+			kind:     formEditMember,
+			values:   map[int]string{0: "Ann", 2: "123"},
+			defaults: map[int]string{0: "Ann", 2: "12"},
+			want:     map[int]string{2: member.ErrPhoneLength.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		f := newForm(tt.kind, "m-1", "Member", labels, tt.defaults)
+		for i, v := range tt.values {
+			f.fields[i].SetValue(v)
+		}
+		errs := validateFormErrors(*f, "33")
+		for i, got := range errs {
+			if got != tt.want[i] {
+				t.Fatalf("%s: field %d: expected %q got %q", tt.name, i, tt.want[i], got)
+			}
+		}
+	}
+}
+
+func TestMemberFormShowsDuplicateEmailInline(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	store, err := jsonstore.Open(t.TempDir() + "/storage.json")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	model.services.Members = usecase.NewMemberService(jsonstore.NewMemberRepository(store), nil, id.NewGenerator(), timeutil.NewClock(), member.Policy{UniqueEmail: true}, nil)
+	if _, err := model.services.Members.Register(model.ctx, dto.RegisterMemberInput{Name: "Ann", Email: "ann@example.com"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	model.route = routeMembers
+	model.startMemberForm()
+	model.activeForm.fields[0].SetValue("Ann Lee")
+	model.activeForm.fields[1].SetValue("ANN@example.com")
+	model.activeForm.focus = len(model.activeForm.fields) - 1
+
+	next, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = next.(Model)
+	if model.activeForm == nil {
+		t.Fatalf("expected the form to stay open")
+	}
+	if got := model.activeForm.errors[1]; got != "email address already exists" {
+		t.Fatalf("expected the duplicate email on the email field, got %q", got)
+	}
+}
@@ -2,9 +2,9 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,12 +16,15 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/eventbus"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
 	"github.com/mibienpanjoe/LMS-bit/internal/config"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
 	copydom "github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
 )
 
 const (
@@ -32,10 +35,20 @@ const (
 )
 
 type Services struct {
-	Books   usecase.BookService
-	Copies  usecase.CopyService
-	Members usecase.MemberService
-	Loans   usecase.LoanService
+	Books      usecase.BookService
+	Copies     usecase.CopyService
+	Members    usecase.MemberService
+	Loans      usecase.LoanService
+	Holds      usecase.HoldService
+	Reports    usecase.ReportService
+	Webhooks   usecase.WebhookService
+	Stocktakes usecase.StocktakeService
+	Branches   usecase.BranchService
+	Transfers  usecase.TransferService
+	Documents  usecase.DocumentService
+	Duplicates usecase.DuplicateService
+	Blocks     usecase.BlockService
+	Events     *eventbus.Bus
 }
 
 type loanFilter string
@@ -56,7 +69,9 @@ const (
 	formUpdateCopy
 	formMember
 	formEditMember
+	formBlockMember
 	formIssueLoan
+	formOverrideRenewal
 )
 
 type formState struct {
@@ -64,8 +79,10 @@ type formState struct {
 	kind     formKind
 	title    string
 	fields   []textinput.Model
+	defaults map[int]string
 	focus    int
 	errors   []string
+	notes    []string
 }
 
 type confirmAction int
@@ -75,6 +92,7 @@ const (
 	confirmArchiveBook
 	confirmReactivateBook
 	confirmToggleMember
+	confirmMergeMember
 )
 
 type Model struct {
@@ -96,11 +114,22 @@ type Model struct {
 	searchInput textinput.Model
 	searching   bool
 	searchQuery string
+	searchErr   error
 
 	showHelp bool
 	status   statusMessage
 
 	loanFilter loanFilter
+	sorts      map[route]sortState
+	report     reportView
+	period     int
+	expanded   map[string]bool
+	dashboard  dto.Dashboard
+	bookDetail string
+	shelfList  bool
+	branch     string
+	duplicates bool
+	swapped    map[string]bool
 
 	activeForm *formState
 	confirming bool
@@ -120,8 +149,8 @@ func NewModel(cfg config.Config, logger *slog.Logger, services Services) Model {
 	t.SetStyles(tableStyles())
 
 	search := textinput.New()
-	search.Placeholder = "type to filter"
-	search.CharLimit = 64
+	search.Placeholder = "type to filter, e.g. author:donovan year:>2010"
+	search.CharLimit = 128
 	search.Prompt = ""
 
 	m := Model{
@@ -137,6 +166,12 @@ func NewModel(cfg config.Config, logger *slog.Logger, services Services) Model {
 		searchInput: search,
 		status:      statusMessage{text: "Ready", kind: statusInfo},
 		loanFilter:  loanFilterAll,
+		sorts:       map[route]sortState{},
+		report:      reportOverdue,
+		period:      1,
+		expanded:    map[string]bool{},
+		swapped:     map[string]bool{},
+		branch:      strings.ToUpper(strings.TrimSpace(cfg.Branch)),
 	}
 	m.refreshRouteData()
 	return m
@@ -144,7 +179,7 @@ func NewModel(cfg config.Config, logger *slog.Logger, services Services) Model {
 
 func (m Model) Init() tea.Cmd {
 	m.logger.Info("starting tui", "app", m.config.AppName)
-	return nil
+	return dashboardTick()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -159,6 +194,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = statusMessage{text: "Ready", kind: statusInfo}
 		}
 		return m, nil
+	case dashboardTickMsg:
+		return m.refreshDashboard()
 	case tea.KeyMsg:
 		return m.updateKeyMsg(msg)
 	}
@@ -272,6 +309,8 @@ func (m Model) routeTargetForKey(msg tea.KeyMsg) (route, bool) {
 		return routeReports, true
 	case key.Matches(msg, m.keys.Settings):
 		return routeSettings, true
+	case key.Matches(msg, m.keys.Webhooks):
+		return routeWebhooks, true
 	default:
 		return "", false
 	}
@@ -291,16 +330,23 @@ func (m Model) handleActionKeys(msg tea.KeyMsg) (bool, Model, tea.Cmd) {
 
 	if key.Matches(msg, m.keys.CreateCopy) {
 		if m.route == routeBooks {
-			m.startCopyForm(m.selectedID())
+			bookID := m.bookDetail
+			if m.booksView() == routeBooks {
+				bookID = m.selectedID()
+			}
+			m.startCopyForm(bookID)
 		}
 		return true, m, nil
 	}
 
 	if key.Matches(msg, m.keys.Edit) {
-		switch m.route {
-		case routeBooks:
+		switch {
+		case m.route == routeBooks && m.booksView() != routeBooks:
+			m.startUpdateCopyForm(m.selectedID())
+			return true, m, nil
+		case m.route == routeBooks:
 			return true, m, m.startEditBookForm()
-		case routeMembers:
+		case m.route == routeMembers:
 			return true, m, m.startEditMemberForm()
 		default:
 			return true, m, nil
@@ -309,14 +355,21 @@ func (m Model) handleActionKeys(msg tea.KeyMsg) (bool, Model, tea.Cmd) {
 
 	if key.Matches(msg, m.keys.UpdateCopy) {
 		prefillCopyID := ""
-		if m.route == routeLoans {
+		switch {
+		case m.route == routeLoans:
 			prefillCopyID = m.selectedCopyID()
+		case m.route == routeBooks && m.booksView() != routeBooks:
+			prefillCopyID = m.selectedID()
 		}
 		m.startUpdateCopyForm(prefillCopyID)
 		return true, m, nil
 	}
 
 	if key.Matches(msg, m.keys.Archive) {
+		if m.route == routeMembers && m.duplicates {
+			cmd := m.swapSelectedPair()
+			return true, m, cmd
+		}
 		next, cmd := m.startArchiveOrToggleConfirm()
 		return true, next.(Model), cmd
 	}
@@ -327,6 +380,100 @@ func (m Model) handleActionKeys(msg tea.KeyMsg) (bool, Model, tea.Cmd) {
 			m.refreshRouteData()
 			return true, m, m.setStatus("Loan filter: "+string(m.loanFilter), statusInfo)
 		}
+		if m.route == routeReports {
+			m.cycleReportView()
+			m.refreshRouteData()
+			return true, m, m.setStatus("Report: "+m.reportLabel(), statusInfo)
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.Expand) {
+		if m.route == routeReports && m.report == reportOverdue {
+			m.toggleOverdueGroup()
+		}
+		if m.route == routeBooks && m.booksView() == routeBooks {
+			cmd := m.openBookDetail()
+			return true, m, cmd
+		}
+		if m.route == routeMembers && m.duplicates {
+			cmd := m.startMergeConfirm()
+			return true, m, cmd
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.Cancel) {
+		if m.route == routeBooks && m.bookDetail != "" {
+			m.bookDetail = ""
+			m.refreshRouteData()
+		}
+		if m.route == routeMembers && m.duplicates {
+			cmd := m.toggleDuplicates()
+			return true, m, cmd
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.ShelfList) {
+		if m.route == routeBooks {
+			m.shelfList = !m.shelfList || m.bookDetail != ""
+			m.bookDetail = ""
+			m.refreshRouteData()
+		}
+		if m.route == routeMembers {
+			cmd := m.toggleDuplicates()
+			return true, m, cmd
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.Branch) {
+		if branchScoped(m.route) {
+			m.cycleBranch()
+			m.refreshRouteData()
+			return true, m, m.setStatus("Branch: "+m.branchLabel(), statusInfo)
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.Print) {
+		var cmd tea.Cmd
+		switch {
+		case m.route == routeBooks:
+			cmd = m.printLabels()
+		case m.route == routeLoans:
+			cmd = m.printReceipt()
+		case m.route == routeReports && m.report == reportOverdue:
+			cmd = m.printNotices()
+		}
+		return true, m, cmd
+	}
+
+	if key.Matches(msg, m.keys.Period) {
+		if m.route == routeReports && m.hasPeriod() {
+			m.cycleReportPeriod()
+			m.refreshRouteData()
+			return true, m, m.setStatus("Report: "+m.reportLabel(), statusInfo)
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.SortColumn) {
+		if _, ok := m.cycleSortColumn(); ok {
+			m.refreshRouteData()
+			s, _ := m.sortFor(m.sortRoute())
+			return true, m, m.setStatus("Sorted by "+sortLabel(m.table.Columns(), s), statusInfo)
+		}
+		return true, m, nil
+	}
+
+	if key.Matches(msg, m.keys.SortOrder) {
+		if _, ok := m.toggleSortOrder(); ok {
+			m.refreshRouteData()
+			s, _ := m.sortFor(m.sortRoute())
+			return true, m, m.setStatus("Sorted by "+sortLabel(m.table.Columns(), s), statusInfo)
+		}
 		return true, m, nil
 	}
 
@@ -337,11 +484,23 @@ func (m Model) handleActionKeys(msg tea.KeyMsg) (bool, Model, tea.Cmd) {
 		return true, m, nil
 	}
 
+	if key.Matches(msg, m.keys.Block) {
+		if m.route == routeMembers && !m.duplicates {
+			cmd := m.startBlockForm()
+			return true, m, cmd
+		}
+		return true, m, nil
+	}
+
 	if key.Matches(msg, m.keys.Renew) {
 		if m.route == routeLoans {
 			next, cmd := m.renewSelectedLoan()
 			return true, next.(Model), cmd
 		}
+		if m.route == routeMembers {
+			cmd := m.renewSelectedMembership()
+			return true, m, cmd
+		}
 		return true, m, nil
 	}
 
@@ -363,7 +522,11 @@ func (m Model) View() string {
 		)
 	}
 
-	parts := []string{m.renderHeader(), m.styles.Body.Render(m.table.View())}
+	parts := []string{m.renderHeader()}
+	if m.route == routeDashboard {
+		parts = append(parts, m.renderDashboard())
+	}
+	parts = append(parts, m.styles.Body.Render(m.table.View()))
 	if m.activeForm != nil {
 		parts = append(parts, m.renderForm())
 	}
@@ -396,17 +559,24 @@ func (m *Model) startCopyForm(bookID string) {
 	if bookID != "" {
 		defaults[0] = bookID
 	}
-	m.activeForm = newForm(formCopy, "", "Add Copy", []string{"Book ID", "Barcode", "Condition Note"}, defaults)
+	if m.branch != "" {
+		defaults[3] = m.branch
+	}
+	m.activeForm = newForm(formCopy, "", "Add Copy", []string{"Book ID", "Barcode", "Condition Note", "Branch", "Location", "Call Number"}, defaults)
 	m.validateActiveForm()
 }
 
 func (m *Model) startMemberForm() {
-	m.activeForm = newForm(formMember, "", "Register Member", []string{"Name", "Email", "Phone"}, nil)
+	m.activeForm = newForm(formMember, "", "Register Member", []string{"Name", "Email", "Phone", "Type", "Card Number (blank for next)"}, nil)
 	m.validateActiveForm()
 }
 
 func (m *Model) startIssueForm() {
-	m.activeForm = newForm(formIssueLoan, "", "Issue Loan", []string{"Copy ID", "Member ID"}, nil)
+	labels := []string{"Copy ID", "Member ID or Card", m.issueDueLabel()}
+	if m.mayOverride() {
+		labels = append(labels, "Override reason (supervisor)")
+	}
+	m.activeForm = newForm(formIssueLoan, "", "Issue Loan", labels, nil)
 	m.validateActiveForm()
 }
 
@@ -452,9 +622,11 @@ func (m *Model) startEditMemberForm() tea.Cmd {
 		0: mm.Name,
 		1: mm.Email,
 		2: mm.Phone,
+		3: mm.Type,
+		4: mm.CardNumber,
 	}
 
-	m.activeForm = newForm(formEditMember, mm.ID, "Edit Member", []string{"Name", "Email", "Phone"}, defaults)
+	m.activeForm = newForm(formEditMember, mm.ID, "Edit Member", []string{"Name", "Email", "Phone", "Type", "Card Number"}, defaults)
 	m.validateActiveForm()
 	return nil
 }
@@ -463,8 +635,16 @@ func (m *Model) startUpdateCopyForm(prefillCopyID string) {
 	defaults := map[int]string{}
 	if prefillCopyID != "" {
 		defaults[0] = prefillCopyID
+		if c, err := m.services.Copies.GetByID(m.ctx, prefillCopyID); err == nil {
+			defaults[1] = c.Barcode
+			defaults[2] = string(c.Status)
+			defaults[3] = c.ConditionNote
+			defaults[4] = c.Branch
+			defaults[5] = c.Location
+			defaults[6] = c.CallNumber
+		}
 	}
-	m.activeForm = newForm(formUpdateCopy, "", "Update Copy", []string{"Copy ID", "Barcode", "Status", "Condition Note"}, defaults)
+	m.activeForm = newForm(formUpdateCopy, "", "Update Copy", []string{"Copy ID", "Barcode", "Status", "Condition Note", "Branch", "Location", "Call Number"}, defaults)
 	m.validateActiveForm()
 }
 
@@ -483,7 +663,7 @@ func newForm(kind formKind, targetID, title string, labels []string, defaults ma
 	fields[0].Focus()
 
 	errs := make([]string, len(labels))
-	return &formState{kind: kind, targetID: targetID, title: title, fields: fields, focus: 0, errors: errs}
+	return &formState{kind: kind, targetID: targetID, title: title, fields: fields, defaults: defaults, focus: 0, errors: errs}
 }
 
 func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -594,18 +774,47 @@ func (m Model) submitForm() (tea.Model, tea.Cmd) {
 			Year:      year,
 		})
 	case formCopy:
-		_, err = m.services.Copies.Create(m.ctx, dto.CreateCopyInput{BookID: get(0), Barcode: get(1), ConditionNote: get(2)})
+		_, err = m.services.Copies.Create(m.ctx, dto.CreateCopyInput{
+			BookID:        get(0),
+			Barcode:       get(1),
+			ConditionNote: get(2),
+			Branch:        get(3),
+			Location:      get(4),
+			CallNumber:    get(5),
+		})
 	case formUpdateCopy:
-		_, err = m.services.Copies.Update(m.ctx, dto.UpdateCopyInput{ID: get(0), Barcode: get(1), Status: get(2), ConditionNote: get(3)})
+		_, err = m.services.Copies.Update(m.ctx, dto.UpdateCopyInput{
+			ID:            get(0),
+			Barcode:       get(1),
+			Status:        get(2),
+			ConditionNote: get(3),
+			Branch:        get(4),
+			Location:      get(5),
+			CallNumber:    get(6),
+		})
 	case formMember:
-		_, err = m.services.Members.Register(m.ctx, dto.RegisterMemberInput{Name: get(0), Email: get(1), Phone: get(2)})
+		_, err = m.services.Members.Register(m.ctx, dto.RegisterMemberInput{Name: get(0), Email: get(1), Phone: get(2), Type: get(3), CardNumber: get(4)})
 	case formEditMember:
-		_, err = m.services.Members.Update(m.ctx, dto.UpdateMemberInput{ID: f.targetID, Name: get(0), Email: get(1), Phone: get(2)})
+		_, err = m.services.Members.Update(m.ctx, dto.UpdateMemberInput{ID: f.targetID, Name: get(0), Email: get(1), Phone: get(2), Type: get(3), CardNumber: get(4)})
+	case formBlockMember:
+		until, _ := parseBlockUntil(get(2))
+		_, err = m.services.Blocks.Block(m.ctx, dto.BlockMemberInput{MemberID: f.targetID, Reason: get(0), Note: get(1), CreatedBy: m.config.Operator, ExpiresAt: until})
 	case formIssueLoan:
-		_, err = m.services.Loans.Issue(m.ctx, dto.IssueLoanInput{CopyID: get(0), MemberID: get(1)})
+		terms := loan.ParseTerms(get(issueDueField), time.Local)
+		input := dto.IssueLoanInput{CopyID: get(0), MemberID: get(1), Period: terms.Period, DueAt: terms.DueAt}
+		if len(f.fields) > issueOverrideField && get(issueOverrideField) != "" {
+			input.Override = dto.LoanOverride{By: m.config.Operator, Reason: get(issueOverrideField)}
+		}
+		_, err = m.services.Loans.Issue(m.ctx, input)
+	case formOverrideRenewal:
+		_, err = m.services.Loans.Renew(m.ctx, dto.RenewLoanInput{LoanID: f.targetID, Override: dto.LoanOverride{By: m.config.Operator, Reason: get(0)}})
 	}
 
 	if err != nil {
+		if i := memberConflictField(f.kind, err); i >= 0 {
+			f.errors[i] = err.Error()
+			return m, m.setStatus("Please fix validation errors", statusInfo)
+		}
 		return m, m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
 	}
 
@@ -620,8 +829,8 @@ func (m Model) startArchiveOrToggleConfirm() (tea.Model, tea.Cmd) {
 		return m, m.setStatus("Select a row first", statusInfo)
 	}
 
-	switch m.route {
-	case routeBooks:
+	switch {
+	case m.route == routeBooks && m.booksView() == routeBooks:
 		b, err := m.services.Books.GetByID(m.ctx, id)
 		if err != nil {
 			return m, m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
@@ -632,7 +841,7 @@ func (m Model) startArchiveOrToggleConfirm() (tea.Model, tea.Cmd) {
 			m.confirmAct = confirmReactivateBook
 		}
 		return m, nil
-	case routeMembers:
+	case m.route == routeMembers:
 		m.confirming = true
 		m.confirmAct = confirmToggleMember
 		return m, nil
@@ -676,6 +885,9 @@ func (m Model) performConfirmAction(id string) error {
 		return err
 	case confirmToggleMember:
 		return m.toggleMemberStatus(id)
+	case confirmMergeMember:
+		_, err := m.mergeSelectedPair()
+		return err
 	default:
 		return nil
 	}
@@ -689,6 +901,8 @@ func (m Model) confirmSuccessMessage() string {
 		return "Book reactivated"
 	case confirmToggleMember:
 		return "Member status updated"
+	case confirmMergeMember:
+		return "Members merged"
 	default:
 		return "Updated successfully"
 	}
@@ -705,6 +919,11 @@ func (m Model) toggleMemberStatus(id string) error {
 			continue
 		}
 
+		if it.Status == member.StatusBlocked {
+			_, err = m.services.Blocks.Unblock(m.ctx, id, m.config.Operator)
+			return err
+		}
+
 		next := member.StatusInactive
 		if it.Status != member.StatusActive {
 			next = member.StatusActive
@@ -723,6 +942,10 @@ func (m Model) renewSelectedLoan() (tea.Model, tea.Cmd) {
 	}
 
 	if _, err := m.services.Loans.Renew(m.ctx, dto.RenewLoanInput{LoanID: id}); err != nil {
+		if overridable(err) && m.mayOverride() {
+			m.startOverrideRenewForm(id, err)
+			return m, nil
+		}
 		return m, m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
 	}
 
@@ -730,17 +953,38 @@ func (m Model) renewSelectedLoan() (tea.Model, tea.Cmd) {
 	return m, m.setStatus("Loan renewed", statusSuccess)
 }
 
+// renewSelectedMembership extends the selected member's membership by one
+// term.
+func (m *Model) renewSelectedMembership() tea.Cmd {
+	id := m.selectedID()
+	if id == "" {
+		return m.setStatus("Select a member first", statusInfo)
+	}
+
+	mm, err := m.services.Members.RenewMembership(m.ctx, id)
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+
+	m.refreshRouteData()
+	return m.setStatus("Membership renewed until "+mm.ExpiresAt.Format("2006-01-02"), statusSuccess)
+}
+
 func (m Model) returnSelectedLoan() (tea.Model, tea.Cmd) {
 	id := m.selectedID()
 	if id == "" {
 		return m, m.setStatus("Select a loan first", statusInfo)
 	}
 
-	if _, err := m.services.Loans.Return(m.ctx, dto.ReturnLoanInput{LoanID: id}); err != nil {
+	_, t, err := m.services.Transfers.Return(m.ctx, id, m.branch)
+	if err != nil {
 		return m, m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
 	}
 
 	m.refreshRouteData()
+	if t.ID != "" {
+		return m, m.setStatus("Loan returned; copy in transit to "+t.To, statusSuccess)
+	}
 	return m, m.setStatus("Loan returned", statusSuccess)
 }
 
@@ -763,22 +1007,57 @@ func (m *Model) refreshRouteData() {
 		rows []table.Row
 	)
 
+	q, err := query.Parse(m.searchQuery)
+	m.searchErr = err
+
 	switch m.route {
 	case routeBooks:
-		cols, rows = m.booksTable()
+		switch m.booksView() {
+		case routeShelf:
+			cols, rows, err = m.shelfTable(q)
+		case routeBookCopies:
+			cols, rows, err = m.bookCopiesTable(q)
+		default:
+			cols, rows, err = m.booksTable(q)
+		}
 	case routeMembers:
-		cols, rows = m.membersTable()
+		if m.duplicates {
+			cols, rows, err = m.duplicatesTable()
+		} else {
+			cols, rows, err = m.membersTable(q)
+		}
 	case routeLoans:
-		cols, rows = m.loansTable()
+		cols, rows, err = m.loansTable(q)
 	case routeReports:
-		cols, rows = m.reportsTable()
+		switch m.report {
+		case reportOverdue:
+			cols, rows, err = m.overdueTable(q)
+		case reportExpiring:
+			cols, rows, err = m.expiringTable()
+		default:
+			cols, rows, err = m.circulationReportTable()
+		}
 	case routeSettings:
 		cols, rows = m.settingsTable()
+		rows = filterRows(rows, m.searchQuery, len(cols))
+	case routeWebhooks:
+		cols, rows = m.webhooksTable()
+		rows = filterRows(rows, m.searchQuery, len(cols))
 	default:
+		m.dashboard, _ = m.services.Reports.Dashboard(m.ctx, dashboardDays, m.branch)
 		cols, rows = m.dashboardTable()
+		rows = filterRows(rows, m.searchQuery, len(cols))
+	}
+	if m.searchErr == nil {
+		m.searchErr = err
+	}
+
+	if s, ok := m.sortFor(m.sortRoute()); ok && !m.rankedByRelevance(q) {
+		sortTable(cols, rows, s)
+		cols = applySortIndicator(cols, s)
 	}
 
-	filtered := filterRows(rows, m.searchQuery, len(cols))
+	filtered := normalizeRows(rows, len(cols))
 	m.table.SetRows(nil)
 	m.table.SetColumns(cols)
 	m.table.SetRows(filtered)
@@ -786,113 +1065,137 @@ func (m *Model) refreshRouteData() {
 	m.resizeTable()
 }
 
-func (m Model) dashboardTable() ([]table.Column, []table.Row) {
+func (m Model) booksTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "ID", Width: 12}, {Title: "Title", Width: 20}, {Title: "Author", Width: 14}, {Title: "ISBN", Width: 14}, {Title: "Category", Width: 12}, {Title: "Copies", Width: 8}, {Title: "Status", Width: 10}}
+
 	books, _ := m.services.Books.List(m.ctx)
 	copies, _ := m.services.Copies.List(m.ctx)
-	members, _ := m.services.Members.List(m.ctx)
-	loans, _ := m.services.Loans.List(m.ctx)
-	overdue, _ := m.services.Loans.ListOverdue(m.ctx)
-
-	activeLoans := 0
-	for _, l := range loans {
-		if l.Status == loan.StatusActive {
-			activeLoans++
+	copyCount := map[string]int{}
+	for _, c := range copies {
+		if m.inBranch(c) {
+			copyCount[c.BookID]++
 		}
 	}
-
-	rows := []table.Row{
-		{"Books", "ok", fmt.Sprintf("%d titles", len(books))},
-		{"Copies", "ok", fmt.Sprintf("%d copies", len(copies))},
-		{"Members", "ok", fmt.Sprintf("%d registered", len(members))},
-		{"Active Loans", "ok", fmt.Sprintf("%d open", activeLoans)},
-		{"Overdue", "watch", fmt.Sprintf("%d overdue", len(overdue))},
+	if m.branch != "" {
+		held := books[:0:0]
+		for _, b := range books {
+			if copyCount[b.ID] > 0 {
+				held = append(held, b)
+			}
+		}
+		books = held
 	}
 
-	return []table.Column{{Title: "Area", Width: 20}, {Title: "Status", Width: 12}, {Title: "Details", Width: 40}}, rows
-}
-
-func (m Model) booksTable() ([]table.Column, []table.Row) {
-	books, _ := m.services.Books.List(m.ctx)
-	copies, _ := m.services.Copies.List(m.ctx)
-	copyCount := map[string]int{}
-	for _, c := range copies {
-		copyCount[c.BookID]++
+	items := make([]bookItem, 0, len(books))
+	if free := q.FreeText(); free != "" {
+		ranked, err := m.services.Books.Search(m.ctx, free, 0)
+		if err != nil {
+			return cols, []table.Row{noMatchesRow(len(cols))}, err
+		}
+		for _, b := range ranked {
+			if m.branch != "" && copyCount[b.ID] == 0 {
+				continue
+			}
+			items = append(items, bookItem{book: b, copies: copyCount[b.ID]})
+		}
+		q = q.WithoutFreeText()
+	} else {
+		for _, b := range books {
+			items = append(items, bookItem{book: b, copies: copyCount[b.ID]})
+		}
 	}
 
-	sort.Slice(books, func(i, j int) bool { return strings.ToLower(books[i].Title) < strings.ToLower(books[j].Title) })
+	matched, err := filterItems(items, q, bookFields, bookText)
 
-	rows := make([]table.Row, 0, len(books))
-	for _, b := range books {
+	rows := make([]table.Row, 0, len(matched))
+	for _, it := range matched {
+		b := it.book
 		author := ""
 		if len(b.Authors) > 0 {
 			author = b.Authors[0]
 		}
-		rows = append(rows, table.Row{b.ID, b.Title, author, b.ISBN, b.Category, fmt.Sprintf("%d", copyCount[b.ID]), string(b.Status)})
+		rows = append(rows, table.Row{b.ID, b.Title, author, b.ISBN, b.Category, fmt.Sprintf("%d", it.copies), string(b.Status)})
 	}
 
-	if len(rows) == 0 {
+	switch {
+	case len(rows) == 0 && len(books) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
 		rows = []table.Row{{"-", "No books yet", "Press a to add", "", "", "", ""}}
 	}
 
-	return []table.Column{{Title: "ID", Width: 12}, {Title: "Title", Width: 20}, {Title: "Author", Width: 14}, {Title: "ISBN", Width: 14}, {Title: "Category", Width: 12}, {Title: "Copies", Width: 8}, {Title: "Status", Width: 10}}, rows
+	return cols, rows, err
 }
 
-func (m Model) membersTable() ([]table.Column, []table.Row) {
-	members, _ := m.services.Members.List(m.ctx)
-	sort.Slice(members, func(i, j int) bool { return strings.ToLower(members[i].Name) < strings.ToLower(members[j].Name) })
+func (m Model) membersTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "ID", Width: 12}, {Title: "Card", Width: 10}, {Title: "Name", Width: 20}, {Title: "Email", Width: 22}, {Title: "Phone", Width: 14}, {Title: "Type", Width: 10}, {Title: "Expires", Width: 12}, {Title: "Status", Width: 10}}
 
-	rows := make([]table.Row, 0, len(members))
-	for _, mm := range members {
-		rows = append(rows, table.Row{mm.ID, mm.Name, mm.Email, mm.Phone, string(mm.Status)})
+	members, _ := m.services.Members.List(m.ctx)
+	matched, err := filterItems(members, q, memberFields, memberText)
+	now := time.Now()
+
+	rows := make([]table.Row, 0, len(matched))
+	for _, mm := range matched {
+		expires := "-"
+		if !mm.ExpiresAt.IsZero() {
+			expires = mm.ExpiresAt.Format("2006-01-02")
+		}
+		status := string(mm.Status)
+		switch {
+		case mm.MergedInto != "":
+			status = "merged:" + mm.MergedInto
+		case mm.Status == member.StatusBlocked:
+			status = "blocked: " + mm.BlockReason(now)
+		}
+		rows = append(rows, table.Row{mm.ID, mm.CardNumber, mm.Name, mm.Email, mm.Phone, mm.Type, expires, status})
 	}
 
-	if len(rows) == 0 {
-		rows = []table.Row{{"-", "No members yet", "Press a to register", "", ""}}
+	switch {
+	case len(rows) == 0 && len(members) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "", "No members yet", "Press a to register", "", "", "", ""}}
 	}
 
-	return []table.Column{{Title: "ID", Width: 12}, {Title: "Name", Width: 20}, {Title: "Email", Width: 24}, {Title: "Phone", Width: 16}, {Title: "Status", Width: 10}}, rows
+	return cols, rows, err
 }
 
-func (m Model) loansTable() ([]table.Column, []table.Row) {
+func (m Model) loansTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "LoanID", Width: 12}, {Title: "CopyID", Width: 12}, {Title: "MemberID", Width: 12}, {Title: "Due", Width: 14}, {Title: "State", Width: 12}}
+
 	loans, _ := m.services.Loans.List(m.ctx)
 	now := time.Now().UTC()
-	sort.Slice(loans, func(i, j int) bool { return loans[i].DueAt.Before(loans[j].DueAt) })
-
-	rows := make([]table.Row, 0, len(loans))
-	for _, l := range loans {
-		state := string(l.Status)
-		if l.IsOverdue(now) {
-			state = "overdue"
-		}
 
-		if !loanMatchesFilter(l, state, m.loanFilter) {
-			continue
+	items := make([]loanItem, 0, len(loans))
+	for _, it := range m.loanItems(loans, now) {
+		if (m.branch == "" || it.branch == m.branch) && loanMatchesFilter(it.loan, it.state, m.loanFilter) {
+			items = append(items, it)
 		}
-
-		rows = append(rows, table.Row{l.ID, l.CopyID, l.MemberID, l.DueAt.Format("2006-01-02"), state})
 	}
 
-	if len(rows) == 0 {
-		rows = []table.Row{{"-", "No loans", "Press i to issue", "", string(m.loanFilter)}}
-	}
-
-	return []table.Column{{Title: "LoanID", Width: 12}, {Title: "CopyID", Width: 12}, {Title: "MemberID", Width: 12}, {Title: "Due", Width: 14}, {Title: "State", Width: 12}}, rows
-}
+	matched, err := filterItems(items, q, loanFields, loanText)
 
-func (m Model) reportsTable() ([]table.Column, []table.Row) {
-	overdue, _ := m.services.Loans.ListOverdue(m.ctx)
-	sort.Slice(overdue, func(i, j int) bool { return overdue[i].DueAt.Before(overdue[j].DueAt) })
-
-	rows := make([]table.Row, 0, len(overdue))
-	for _, l := range overdue {
-		rows = append(rows, table.Row{l.ID, l.MemberID, l.CopyID, l.DueAt.Format("2006-01-02")})
+	rows := make([]table.Row, 0, len(matched))
+	for _, it := range matched {
+		l := it.loan
+		due, state := l.DueAt.Format("2006-01-02"), it.state
+		if l.IsShort() {
+			due = l.DueAt.Local().Format("2006-01-02 15:04")
+			if it.overdue {
+				state = fmt.Sprintf("overdue %dh", l.HoursOverdue(now))
+			}
+		}
+		rows = append(rows, table.Row{l.ID, l.CopyID, l.MemberID, due, state})
 	}
 
-	if len(rows) == 0 {
-		rows = []table.Row{{"-", "No overdue loans", "", ""}}
+	switch {
+	case len(rows) == 0 && len(items) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "No loans", "Press i to issue", "", string(m.loanFilter)}}
 	}
 
-	return []table.Column{{Title: "LoanID", Width: 12}, {Title: "MemberID", Width: 12}, {Title: "CopyID", Width: 12}, {Title: "Due Date", Width: 14}}, rows
+	return cols, rows, err
 }
 
 func (m Model) settingsTable() ([]table.Column, []table.Row) {
@@ -901,10 +1204,71 @@ func (m Model) settingsTable() ([]table.Column, []table.Row) {
 		{"loan.days", fmt.Sprintf("%d", m.config.LoanDays), settingsSourceEnvDefault},
 		{"loan.max_per_member", fmt.Sprintf("%d", m.config.MaxLoansPerUser), settingsSourceEnvDefault},
 		{"loan.max_renewals", fmt.Sprintf("%d", m.config.MaxLoanRenewals), settingsSourceEnvDefault},
+		{"loan.periods", m.config.LoanPeriods, settingsSourceEnvDefault},
+		{"loan.min_length", m.config.MinLoanLength, settingsSourceEnvDefault},
+		{"loan.max_length", m.config.MaxLoanLength, settingsSourceEnvDefault},
+		{"isbn.unique", strconv.FormatBool(m.config.UniqueISBN), settingsSourceEnvDefault},
+		{"http.addr", m.config.HTTPAddr, settingsSourceEnvDefault},
+		{"opac.addr", m.config.OPACAddr, settingsSourceEnvDefault},
+		{"branch", m.config.Branch, settingsSourceEnvDefault},
+		{"barcode.auto", strconv.FormatBool(m.config.AutoBarcode), settingsSourceEnvDefault},
+		{"barcode.prefix", m.config.BarcodePrefix, settingsSourceEnvDefault},
+		{"barcode.digits", fmt.Sprintf("%d", m.config.BarcodeDigits), settingsSourceEnvDefault},
+		{"label.layout", m.config.LabelLayout, settingsSourceEnvDefault},
+		{"membership.days", fmt.Sprintf("%d", m.config.MembershipDays), settingsSourceEnvDefault},
+		{"membership.notice_days", fmt.Sprintf("%d", m.config.ExpiringDays), settingsSourceEnvDefault},
+		{"card.prefix", m.config.CardPrefix, settingsSourceEnvDefault},
+		{"card.digits", fmt.Sprintf("%d", m.config.CardDigits), settingsSourceEnvDefault},
+		{"card.unique", strconv.FormatBool(m.config.UniqueCard), settingsSourceEnvDefault},
+		{"member.email_unique", strconv.FormatBool(m.config.UniqueEmail), settingsSourceEnvDefault},
+		{"phone.country_code", m.config.PhoneCountry, settingsSourceEnvDefault},
+		{"operator", m.config.Operator, settingsSourceEnvDefault},
+		{"supervisors", strings.Join(m.config.Supervisors, ","), settingsSourceEnvDefault},
+		{"block.overdue_items", fmt.Sprintf("%d", m.config.BlockOverdue), settingsSourceEnvDefault},
+		{"block.overdue_days", fmt.Sprintf("%d", m.config.BlockAfterDays), settingsSourceEnvDefault},
+		{"document.format", m.config.DocumentFormat, settingsSourceEnvDefault},
 	}
 	return []table.Column{{Title: "Key", Width: 28}, {Title: "Value", Width: 34}, {Title: "Source", Width: 18}}, rows
 }
 
+func (m Model) webhooksTable() ([]table.Column, []table.Row) {
+	cols := []table.Column{{Title: "Delivery", Width: 12}, {Title: "Created", Width: 16}, {Title: "URL", Width: 24}, {Title: "Event", Width: 20}, {Title: "Status", Width: 10}, {Title: "Tries", Width: 6}, {Title: "Last", Width: 24}}
+
+	endpoints, _ := m.services.Webhooks.List(m.ctx)
+	urls := make(map[string]string, len(endpoints))
+	for _, e := range endpoints {
+		urls[e.ID] = e.URL
+	}
+
+	deliveries, _ := m.services.Webhooks.Deliveries(m.ctx, 200)
+	rows := make([]table.Row, 0, len(deliveries))
+	for _, d := range deliveries {
+		url, ok := urls[d.EndpointID]
+		if !ok {
+			url = d.EndpointID + " (removed)"
+		}
+		rows = append(rows, table.Row{
+			d.ID,
+			d.CreatedAt.Local().Format("2006-01-02 15:04"),
+			url,
+			string(d.EventType),
+			string(d.Status),
+			strconv.Itoa(d.Attempts),
+			d.Outcome(),
+		})
+	}
+
+	if len(rows) == 0 {
+		text := "No deliveries yet"
+		if len(endpoints) == 0 {
+			text = "No webhooks registered"
+		}
+		rows = []table.Row{{"-", text, "lms webhooks add <url>", "", "", "", ""}}
+	}
+
+	return cols, rows
+}
+
 func loanMatchesFilter(l loan.Loan, state string, filter loanFilter) bool {
 	switch filter {
 	case loanFilterActive:
@@ -932,18 +1296,7 @@ func filterRows(rows []table.Row, query string, colCount int) []table.Row {
 	}
 
 	if len(filtered) == 0 {
-		empty := make(table.Row, colCount)
-		if colCount > 0 {
-			empty[0] = "-"
-		}
-		if colCount > 1 {
-			empty[1] = "No matches"
-		}
-		if colCount > 2 {
-			empty[2] = "Try another search term"
-		}
-
-		return []table.Row{empty}
+		return []table.Row{noMatchesRow(colCount)}
 	}
 
 	return normalizeRows(filtered, colCount)
@@ -986,6 +1339,9 @@ func (m *Model) resizeTable() {
 	if m.activeForm != nil || m.confirming {
 		tableHeight -= 6
 	}
+	if m.route == routeDashboard {
+		tableHeight -= dashboardPanelHeight
+	}
 	if tableHeight < 5 {
 		tableHeight = 5
 	}
@@ -995,6 +1351,9 @@ func (m *Model) resizeTable() {
 func (m Model) renderHeader() string {
 	title := m.styles.Header.Render(fmt.Sprintf(" %s ", m.config.AppName))
 	subtitle := m.styles.HeaderMuted.Render("by MJ")
+	if m.branch != "" {
+		subtitle += m.styles.HeaderMuted.Render(" | branch " + m.branch)
+	}
 	nav := m.renderRouteTabs()
 	searchLine := m.renderSearchLine()
 
@@ -1005,9 +1364,18 @@ func (m Model) renderRouteTabs() string {
 	items := make([]string, 0, len(allRoutes))
 	for _, r := range allRoutes {
 		label := string(r)
+		if r == routeBooks {
+			label = m.booksTabLabel()
+		}
+		if r == routeMembers {
+			label = m.membersTabLabel()
+		}
 		if r == routeLoans {
 			label = label + " (" + string(m.loanFilter) + ")"
 		}
+		if r == routeReports {
+			label = label + " (" + string(m.report) + ")"
+		}
 		if r == m.route {
 			items = append(items, m.styles.ActiveTab.Render(label))
 			continue
@@ -1023,7 +1391,11 @@ func (m Model) renderSearchLine() string {
 		state = "active"
 	}
 	prefix := m.styles.SearchLabel.Render("Search [/] (" + state + "):")
-	return prefix + " " + m.searchInput.View()
+	line := prefix + " " + m.searchInput.View()
+	if m.searchErr != nil {
+		line += " " + m.styles.SearchError.Render("! "+m.searchErr.Error())
+	}
+	return line
 }
 
 func (m Model) renderFooter() string {
@@ -1053,6 +1425,9 @@ func (m Model) renderForm() string {
 			lines = append(lines, "  ! "+m.activeForm.errors[i])
 		}
 	}
+	for _, note := range m.activeForm.notes {
+		lines = append(lines, "  - "+note)
+	}
 	lines = append(lines, "tab/shift+tab to move, enter to continue/submit, esc to cancel")
 
 	box := m.styles.ConfirmBox.Render(strings.Join(lines, "\n"))
@@ -1075,7 +1450,12 @@ func (m Model) renderConfirm() string {
 	}
 	if m.confirmAct == confirmToggleMember {
 		title = "Toggle Member Status"
-		body = "This will toggle selected member active/inactive status."
+		body = "This will toggle selected member active/inactive status. A blocked member is unblocked."
+	}
+	if m.confirmAct == confirmMergeMember {
+		keep, merge := m.selectedPair()
+		title = "Merge Members"
+		body = "This will move the loans and holds of " + merge + " to " + keep + " and deactivate " + merge + "."
 	}
 
 	msg := strings.Join([]string{
@@ -1122,10 +1502,15 @@ func (m *Model) validateActiveForm() {
 	if m.activeForm == nil {
 		return
 	}
-	m.activeForm.errors = validateFormErrors(*m.activeForm)
+	m.activeForm.errors = validateFormErrors(*m.activeForm, m.config.PhoneCountry)
+	if m.activeForm.kind == formIssueLoan {
+		m.checkIssueForm()
+	}
 }
 
-func validateFormErrors(f formState) []string {
+// validateFormErrors checks each field of f on its own. phoneCountry is the
+// calling code given to phone numbers typed without one.
+func validateFormErrors(f formState, phoneCountry string) []string {
 	errs := make([]string, len(f.fields))
 	get := func(i int) string {
 		if i < 0 || i >= len(f.fields) {
@@ -1144,6 +1529,11 @@ func validateFormErrors(f formState) []string {
 	case formBook, formEditBook:
 		req(0, "title is required")
 		req(1, "author is required")
+		if isbn := get(2); isbn != "" {
+			if _, err := book.NormalizeISBN(isbn); err != nil {
+				errs[2] = err.Error()
+			}
+		}
 		year := get(5)
 		if year != "" {
 			if _, err := strconv.Atoi(year); err != nil {
@@ -1158,21 +1548,56 @@ func validateFormErrors(f formState) []string {
 		if get(2) != "" {
 			status := copydom.Status(strings.ToLower(get(2)))
 			switch status {
-			case copydom.StatusAvailable, copydom.StatusLoaned, copydom.StatusDamaged, copydom.StatusLost:
+			case copydom.StatusAvailable, copydom.StatusLoaned, copydom.StatusDamaged, copydom.StatusLost, copydom.StatusInTransit:
 			default:
-				errs[2] = "status must be available/loaned/damaged/lost"
+				errs[2] = "status must be available/loaned/damaged/lost/in_transit"
 			}
 		}
 	case formMember, formEditMember:
 		req(0, "name is required")
+		if email := get(1); email != "" {
+			if _, err := member.NormalizeEmail(email); err != nil {
+				errs[1] = err.Error()
+			}
+		}
+		// An existing number is only checked once it is edited, as the
+		// service keeps numbers stored before normalization.
+		if phone := get(2); phone != "" && (f.kind == formMember || phone != f.defaults[2]) {
+			if _, err := member.NormalizePhone(phone, phoneCountry); err != nil {
+				errs[2] = err.Error()
+			}
+		}
+	case formBlockMember:
+		req(0, "reason is required")
+		if _, err := parseBlockUntil(get(2)); err != nil {
+			errs[2] = err.Error()
+		}
 	case formIssueLoan:
 		req(0, "copy id is required")
 		req(1, "member id is required")
+	case formOverrideRenewal:
+		req(0, "override reason is required")
 	}
 
 	return errs
 }
 
+// memberConflictField is the member form field a uniqueness error belongs
+// to, or -1.
+func memberConflictField(kind formKind, err error) int {
+	if kind != formMember && kind != formEditMember {
+		return -1
+	}
+	switch {
+	case errors.Is(err, shared.ErrDuplicateEmail):
+		return 1
+	case errors.Is(err, shared.ErrDuplicateCard):
+		return 4
+	default:
+		return -1
+	}
+}
+
 func hasFormErrors(f *formState) bool {
 	if f == nil {
 		return false
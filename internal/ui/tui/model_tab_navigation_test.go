@@ -3,11 +3,15 @@ package tui
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
 	"github.com/mibienpanjoe/LMS-bit/internal/config"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	"github.com/mibienpanjoe/LMS-bit/internal/infra/id"
 	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
 	timeutil "github.com/mibienpanjoe/LMS-bit/internal/infra/time"
@@ -17,6 +21,18 @@ import (
 func TestTabNavigationAcrossRoutesDoesNotPanic(t *testing.T) {
 	t.Parallel()
 
+	model := newTestModel(t)
+
+	for i := 0; i < 20; i++ {
+		next, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = next.(Model)
+		_ = model.View()
+	}
+}
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+
 	store, err := jsonstore.Open(filepath.Join(t.TempDir(), "storage.json"))
 	if err != nil {
 		t.Fatalf("open store: %v", err)
@@ -29,19 +45,38 @@ func TestTabNavigationAcrossRoutesDoesNotPanic(t *testing.T) {
 
 	idGen := id.NewGenerator()
 	clock := timeutil.NewClock()
+	branchRepo := jsonstore.NewBranchRepository(store)
+	loans := usecase.NewLoanService(
+		loanRepo,
+		copyRepo,
+		memberRepo,
+		idGen,
+		clock,
+		loan.Policy{
+			LoanDays:          14,
+			MaxLoansPerMember: 3,
+			MaxRenewals:       1,
+			Supervisors:       []string{"sam"},
+			Periods:           []loan.Period{{Name: "reserve", Length: 2 * time.Hour}},
+			MinLength:         time.Hour,
+		},
+		nil,
+	)
+
+	holdRepo := jsonstore.NewHoldRepository(store)
 
 	services := Services{
-		Books:   usecase.NewBookService(bookRepo, idGen),
-		Copies:  usecase.NewCopyService(copyRepo, idGen),
-		Members: usecase.NewMemberService(memberRepo, idGen, clock),
-		Loans: usecase.NewLoanService(
-			loanRepo,
-			copyRepo,
-			memberRepo,
-			idGen,
-			clock,
-			loan.Policy{LoanDays: 14, MaxLoansPerMember: 3, MaxRenewals: 1},
-		),
+		Books:      usecase.NewBookService(bookRepo, idGen, clock, book.Policy{UniqueISBN: true}, nil),
+		Copies:     usecase.NewCopyService(copyRepo, nil, idGen, clock, copy.Policy{}, nil),
+		Members:    usecase.NewMemberService(memberRepo, nil, idGen, clock, member.Policy{}, nil),
+		Loans:      loans,
+		Reports:    usecase.NewReportService(loanRepo, copyRepo, bookRepo, memberRepo, clock),
+		Webhooks:   usecase.NewWebhookService(jsonstore.NewWebhookRepository(store), nil, idGen, clock),
+		Branches:   usecase.NewBranchService(branchRepo, clock),
+		Transfers:  usecase.NewTransferService(jsonstore.NewTransferRepository(store), branchRepo, copyRepo, loans, idGen, clock, nil),
+		Holds:      usecase.NewHoldService(holdRepo, bookRepo, memberRepo, idGen, clock),
+		Duplicates: usecase.NewDuplicateService(memberRepo, loanRepo, holdRepo, clock, nil),
+		Blocks:     usecase.NewBlockService(memberRepo, loanRepo, clock, member.BlockPolicy{}, nil),
 	}
 
 	cfg := config.Config{
@@ -53,11 +88,5 @@ func TestTabNavigationAcrossRoutesDoesNotPanic(t *testing.T) {
 		MaxLoanRenewals: 1,
 	}
 
-	model := NewModel(cfg, logging.New("error"), services)
-
-	for i := 0; i < 20; i++ {
-		next, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
-		model = next.(Model)
-		_ = model.View()
-	}
+	return NewModel(cfg, logging.New("error"), services)
 }
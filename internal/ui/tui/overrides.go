@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"errors"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/shared"
+)
+
+// mayOverride reports whether the operator is a supervisor, who may issue
+// or renew despite the loan limit, renewal limit and overdue rules.
+func (m Model) mayOverride() bool {
+	return m.services.Loans.MayOverride(m.config.Operator)
+}
+
+// overridable reports whether a supervisor's override lifts err.
+func overridable(err error) bool {
+	return errors.Is(err, shared.ErrLoanLimitReached) ||
+		errors.Is(err, shared.ErrRenewalLimit) ||
+		errors.Is(err, shared.ErrLoanAlreadyOverdue)
+}
+
+// startOverrideRenewForm asks a supervisor why the refused renewal of the
+// loan should go ahead anyway.
+func (m *Model) startOverrideRenewForm(loanID string, refused error) {
+	m.activeForm = newForm(formOverrideRenewal, loanID, "Override Renewal", []string{"Override reason"}, nil)
+	m.validateActiveForm()
+	m.activeForm.notes = []string{"refused: " + refused.Error(), "overriding as " + m.config.Operator}
+}
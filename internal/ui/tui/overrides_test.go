@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+func TestRefusedRenewalOffersSupervisorOverride(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	b, err := model.services.Books.Create(model.ctx, dto.CreateBookInput{Title: "Go", Authors: []string{"Alan Donovan"}})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if _, err := model.services.Copies.Create(model.ctx, dto.CreateCopyInput{ID: "c-1", BookID: b.ID}); err != nil {
+		t.Fatalf("create copy: %v", err)
+	}
+	if _, err := model.services.Members.Register(model.ctx, dto.RegisterMemberInput{ID: "m-1", Name: "Ann Lee"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	l, err := model.services.Loans.Issue(model.ctx, dto.IssueLoanInput{CopyID: "c-1", MemberID: "m-1"})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := model.services.Loans.Renew(model.ctx, dto.RenewLoanInput{LoanID: l.ID}); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			next, _ := model.Update(k)
+			model = next.(Model)
+		}
+	}
+	renew := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+
+	model.route = routeLoans
+	model.refreshRouteData()
+	press(renew)
+	if model.activeForm != nil {
+		t.Fatalf("expected no override form for staff who are not supervisors")
+	}
+
+	model.config.Operator = "sam"
+	press(renew)
+	if model.activeForm == nil || model.activeForm.kind != formOverrideRenewal {
+		t.Fatalf("expected the override form to open")
+	}
+	if got := model.activeForm.errors[0]; got != "override reason is required" {
+		t.Fatalf("expected the reason to be required, got %q", got)
+	}
+
+	model.activeForm.fields[0].SetValue("exam week")
+	model.validateActiveForm()
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.activeForm != nil {
+		t.Fatalf("expected the form to close, errors %v", model.activeForm.errors)
+	}
+
+	renewed, _ := model.services.Loans.GetByID(model.ctx, l.ID)
+	if renewed.RenewalCount != 2 || len(renewed.Overrides) != 1 || renewed.Overrides[0].By != "sam" || renewed.Overrides[0].Reason != "exam week" {
+		t.Fatalf("expected an overridden renewal, got %+v", renewed)
+	}
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+)
+
+const (
+	reportOverdue  reportView = "overdue"
+	reportExpiring reportView = "expiring-members"
+	reportTopLimit            = 20
+)
+
+type reportView string
+
+var reportViews = func() []reportView {
+	views := []reportView{reportOverdue, reportExpiring}
+	for _, k := range dto.ReportKinds {
+		views = append(views, reportView(k))
+	}
+	return views
+}()
+
+type reportPeriod struct {
+	label string
+	days  int
+}
+
+var reportPeriods = []reportPeriod{
+	{label: "30d", days: 30},
+	{label: "90d", days: 90},
+	{label: "12m", days: 365},
+	{label: "all"},
+}
+
+func (p reportPeriod) rangeAt(now time.Time) dto.ReportRange {
+	if p.days == 0 {
+		return dto.ReportRange{}
+	}
+	return dto.ReportRange{From: now.AddDate(0, 0, -p.days)}
+}
+
+func (m *Model) cycleReportView() {
+	for i, v := range reportViews {
+		if v == m.report {
+			m.report = reportViews[(i+1)%len(reportViews)]
+			return
+		}
+	}
+	m.report = reportOverdue
+}
+
+func (m *Model) cycleReportPeriod() {
+	m.period = (m.period + 1) % len(reportPeriods)
+}
+
+// hasPeriod reports whether the current report is limited by the period;
+// the overdue and membership reports always show the present.
+func (m Model) hasPeriod() bool {
+	return m.report != reportOverdue && m.report != reportExpiring
+}
+
+func (m Model) reportLabel() string {
+	if !m.hasPeriod() {
+		return string(m.report)
+	}
+	return string(m.report) + " " + reportPeriods[m.period].label
+}
+
+// expiringTable lists memberships ending within the notice window.
+func (m Model) expiringTable() ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "Member", Width: 12}, {Title: "Card", Width: 10}, {Title: "Name", Width: 22}, {Title: "Email", Width: 22}, {Title: "Expires", Width: 12}, {Title: "Days Left", Width: 10}, {Title: "Loans", Width: 6}}
+
+	items, err := m.services.Reports.ExpiringMembers(m.ctx, m.config.ExpiringDays)
+	rows := make([]table.Row, 0, len(items))
+	for _, it := range items {
+		rows = append(rows, table.Row{
+			it.Member.ID, it.Member.CardNumber, it.Member.Name, it.Member.Email,
+			it.Member.ExpiresAt.Format("2006-01-02"), strconv.Itoa(it.DaysLeft), strconv.Itoa(it.ActiveLoans),
+		})
+	}
+	if len(rows) == 0 {
+		rows = []table.Row{{"-", "", "No memberships expire in the next " + strconv.Itoa(m.config.ExpiringDays) + " days"}}
+	}
+
+	return cols, filterRows(rows, m.searchQuery, len(cols)), err
+}
+
+func (m Model) circulationReportTable() ([]table.Column, []table.Row, error) {
+	rng := reportPeriods[m.period].rangeAt(time.Now().UTC())
+	rng.Branch = m.branch
+	report, err := m.services.Reports.Table(m.ctx, dto.ReportKind(m.report), rng, reportTopLimit)
+	if err != nil {
+		return []table.Column{{Title: "Report", Width: 40}}, []table.Row{{err.Error()}}, err
+	}
+
+	widths := make([]int, len(report.Columns))
+	for i, c := range report.Columns {
+		widths[i] = max(len(c)+2, 8)
+	}
+	for _, r := range report.Rows {
+		for i, v := range r {
+			widths[i] = min(max(widths[i], len(v)+2), 32)
+		}
+	}
+
+	cols := make([]table.Column, len(report.Columns))
+	for i, c := range report.Columns {
+		cols[i] = table.Column{Title: c, Width: widths[i]}
+	}
+
+	rows := make([]table.Row, 0, len(report.Rows))
+	for _, r := range report.Rows {
+		rows = append(rows, table.Row(r))
+	}
+	if len(rows) == 0 {
+		rows = []table.Row{{"-", "No loans in this period"}}
+	}
+
+	return cols, filterRows(rows, m.searchQuery, len(cols)), nil
+}
+
+const (
+	groupCollapsed = "▸ "
+	groupExpanded  = "▾ "
+	groupChild     = "  "
+)
+
+// overdueTable lists one row per member with overdue loans; expanded groups
+// are followed by a row per loan. While searching, every group with a
+// matching loan is shown expanded.
+func (m Model) overdueTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "Member/Loan", Width: 16}, {Title: "Name/Title", Width: 22}, {Title: "Items", Width: 6}, {Title: "Oldest Due", Width: 12}, {Title: "Days", Width: 6}, {Title: "1-7", Width: 5}, {Title: "8-30", Width: 5}, {Title: "31+", Width: 5}}
+
+	groups, _ := m.services.Loans.OverdueByMember(m.ctx, m.branch)
+
+	var loans []loan.Loan
+	for _, g := range groups {
+		for _, it := range g.Items {
+			loans = append(loans, it.Loan)
+		}
+	}
+	items := m.loanItems(loans, time.Now().UTC())
+	matched, err := filterItems(items, q, loanFields, loanText)
+
+	visible := make(map[string]loanItem, len(matched))
+	for _, it := range matched {
+		visible[it.loan.ID] = it
+	}
+
+	rows := make([]table.Row, 0, len(groups))
+	for _, g := range groups {
+		children := make([]table.Row, 0, len(g.Items))
+		for _, it := range g.Items {
+			li, ok := visible[it.Loan.ID]
+			if !ok {
+				continue
+			}
+			label := li.title
+			if it.Barcode != "" {
+				label += " (" + it.Barcode + ")"
+			}
+			children = append(children, table.Row{groupChild + it.Loan.ID, groupChild + label, "", it.Loan.DueAt.Format("2006-01-02"), strconv.Itoa(it.DaysOverdue), "", "", ""})
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		open := m.expanded[g.Member.ID] || m.searchQuery != ""
+		marker := groupCollapsed
+		if open {
+			marker = groupExpanded
+		}
+		days := 0
+		if len(g.Items) > 0 {
+			days = g.Items[0].DaysOverdue
+		}
+		rows = append(rows, table.Row{
+			marker + g.Member.ID,
+			g.Member.Name,
+			strconv.Itoa(len(g.Items)),
+			g.OldestDue.Format("2006-01-02"),
+			strconv.Itoa(days),
+			strconv.Itoa(g.Aging.Days1To7),
+			strconv.Itoa(g.Aging.Days8To30),
+			strconv.Itoa(g.Aging.Days31AndOver),
+		})
+		if open {
+			rows = append(rows, children...)
+		}
+	}
+
+	switch {
+	case len(rows) == 0 && len(items) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "No overdue loans", "", "", "", "", "", ""}}
+	}
+
+	return cols, rows, err
+}
+
+// toggleOverdueGroup expands or collapses the member group under the cursor,
+// or the group a selected loan row belongs to, and keeps the cursor on it.
+func (m *Model) toggleOverdueGroup() {
+	rows := m.table.Rows()
+	idx := m.table.Cursor()
+	for idx > 0 && idx < len(rows) && strings.HasPrefix(rows[idx][0], groupChild) {
+		idx--
+	}
+	if idx < 0 || idx >= len(rows) {
+		return
+	}
+
+	memberID := strings.TrimPrefix(strings.TrimPrefix(rows[idx][0], groupCollapsed), groupExpanded)
+	if memberID == rows[idx][0] {
+		return
+	}
+	m.expanded[memberID] = !m.expanded[memberID]
+
+	m.refreshRouteData()
+	m.table.SetCursor(idx)
+}
@@ -9,6 +9,7 @@ const (
 	routeLoans     route = "Loans"
 	routeReports   route = "Reports"
 	routeSettings  route = "Settings"
+	routeWebhooks  route = "Webhooks"
 )
 
 var allRoutes = []route{
@@ -18,6 +19,7 @@ var allRoutes = []route{
 	routeLoans,
 	routeReports,
 	routeSettings,
+	routeWebhooks,
 }
 
 func nextRoute(current route) route {
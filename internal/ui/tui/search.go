@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
+)
+
+type bookItem struct {
+	book   book.Book
+	copies int
+}
+
+type loanItem struct {
+	loan       loan.Loan
+	state      string
+	overdue    bool
+	memberName string
+	barcode    string
+	title      string
+	branch     string
+}
+
+var bookFields = query.Fields[bookItem]{
+	"id":        query.TextField(func(b bookItem) []string { return []string{b.book.ID} }),
+	"title":     query.TextField(func(b bookItem) []string { return []string{b.book.Title} }),
+	"author":    query.TextField(func(b bookItem) []string { return b.book.Authors }),
+	"isbn":      query.TextField(func(b bookItem) []string { return []string{b.book.ISBN} }),
+	"category":  query.TextField(func(b bookItem) []string { return []string{b.book.Category} }),
+	"publisher": query.TextField(func(b bookItem) []string { return []string{b.book.Publisher} }),
+	"status":    query.TextField(func(b bookItem) []string { return []string{string(b.book.Status)} }),
+	"year":      query.NumberField(func(b bookItem) float64 { return float64(b.book.Year) }),
+	"copies":    query.NumberField(func(b bookItem) float64 { return float64(b.copies) }),
+}
+
+func bookText(b bookItem) []string {
+	out := []string{b.book.ID, b.book.Title, b.book.ISBN, b.book.Category, b.book.Publisher, string(b.book.Status)}
+	return append(out, b.book.Authors...)
+}
+
+var memberFields = query.Fields[member.Member]{
+	"id":      query.TextField(func(m member.Member) []string { return []string{m.ID} }),
+	"card":    query.TextField(func(m member.Member) []string { return []string{m.CardNumber} }),
+	"name":    query.TextField(func(m member.Member) []string { return []string{m.Name} }),
+	"email":   query.TextField(func(m member.Member) []string { return []string{m.Email} }),
+	"phone":   query.TextField(func(m member.Member) []string { return []string{m.Phone} }),
+	"type":    query.TextField(func(m member.Member) []string { return []string{m.Type} }),
+	"status":  query.TextField(func(m member.Member) []string { return []string{string(m.Status)} }),
+	"joined":  query.DateField(func(m member.Member) time.Time { return m.JoinedAt }),
+	"expires": query.DateField(func(m member.Member) time.Time { return m.ExpiresAt }),
+}
+
+func memberText(m member.Member) []string {
+	return []string{m.ID, m.CardNumber, m.Name, m.Email, m.Phone, m.Type, string(m.Status)}
+}
+
+var loanFields = query.Fields[loanItem]{
+	"id":       query.TextField(func(l loanItem) []string { return []string{l.loan.ID} }),
+	"copy":     query.TextField(func(l loanItem) []string { return []string{l.loan.CopyID, l.barcode} }),
+	"member":   query.TextField(func(l loanItem) []string { return []string{l.loan.MemberID, l.memberName} }),
+	"title":    query.TextField(func(l loanItem) []string { return []string{l.title} }),
+	"branch":   query.TextField(func(l loanItem) []string { return []string{l.branch} }),
+	"status":   query.TextField(func(l loanItem) []string { return []string{l.state} }),
+	"overdue":  query.BoolField(func(l loanItem) bool { return l.overdue }),
+	"due":      query.DateField(func(l loanItem) time.Time { return l.loan.DueAt }),
+	"issued":   query.DateField(func(l loanItem) time.Time { return l.loan.IssuedAt }),
+	"renewals": query.NumberField(func(l loanItem) float64 { return float64(l.loan.RenewalCount) }),
+}
+
+func loanText(l loanItem) []string {
+	return []string{l.loan.ID, l.loan.CopyID, l.loan.MemberID, l.memberName, l.barcode, l.title, l.state}
+}
+
+func filterItems[T any](items []T, q query.Query, fields query.Fields[T], text func(T) []string) ([]T, error) {
+	if q.IsEmpty() {
+		return items, nil
+	}
+
+	pred, err := query.Compile(q, fields, text)
+	if err != nil {
+		return items, err
+	}
+
+	return query.Filter(items, pred), nil
+}
+
+func (m Model) loanItems(loans []loan.Loan, now time.Time) []loanItem {
+	members, _ := m.services.Members.List(m.ctx)
+	copies, _ := m.services.Copies.List(m.ctx)
+	books, _ := m.services.Books.List(m.ctx)
+
+	names := make(map[string]string, len(members))
+	for _, mm := range members {
+		names[mm.ID] = mm.Name
+	}
+	titles := make(map[string]string, len(books))
+	for _, b := range books {
+		titles[b.ID] = b.Title
+	}
+	barcodes := make(map[string]string, len(copies))
+	copyTitles := make(map[string]string, len(copies))
+	homes := make(map[string]string, len(copies))
+	for _, c := range copies {
+		barcodes[c.ID] = c.Barcode
+		copyTitles[c.ID] = titles[c.BookID]
+		homes[c.ID] = c.Branch
+	}
+
+	out := make([]loanItem, 0, len(loans))
+	for _, l := range loans {
+		it := loanItem{
+			loan:       l,
+			state:      string(l.Status),
+			overdue:    l.IsOverdue(now),
+			memberName: names[l.MemberID],
+			barcode:    barcodes[l.CopyID],
+			title:      copyTitles[l.CopyID],
+			branch:     homes[l.CopyID],
+		}
+		if it.overdue {
+			it.state = "overdue"
+		}
+		out = append(out, it)
+	}
+
+	return out
+}
+
+func noMatchesRow(colCount int) table.Row {
+	empty := make(table.Row, colCount)
+	if colCount > 0 {
+		empty[0] = "-"
+	}
+	if colCount > 1 {
+		empty[1] = "No matches"
+	}
+	if colCount > 2 {
+		empty[2] = "Try another search term"
+	}
+	return empty
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+)
+
+func TestStructuredSearchFiltersBooks(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	for _, in := range []dto.CreateBookInput{
+		{Title: "The Go Programming Language", Authors: []string{"Alan Donovan"}, Year: 2015},
+		{Title: "The C Programming Language", Authors: []string{"Brian Kernighan"}, Year: 1978},
+	} {
+		if _, err := model.services.Books.Create(model.ctx, in); err != nil {
+			t.Fatalf("create book: %v", err)
+		}
+	}
+
+	model.route = routeBooks
+	model.searchQuery = "author:donovan year:>2010"
+	model.refreshRouteData()
+
+	rows := model.table.Rows()
+	if model.searchErr != nil || len(rows) != 1 || rows[0][1] != "The Go Programming Language" {
+		t.Fatalf("unexpected result err=%v rows=%v", model.searchErr, rows)
+	}
+
+	model.searchQuery = "shelf:a1"
+	model.refreshRouteData()
+	if !errors.Is(model.searchErr, query.ErrUnknownField) {
+		t.Fatalf("expected unknown field error got %v", model.searchErr)
+	}
+	if len(model.table.Rows()) != 2 {
+		t.Fatalf("expected unfiltered rows on error got %d", len(model.table.Rows()))
+	}
+}
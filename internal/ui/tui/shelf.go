@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+	copydom "github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+const (
+	callNumberTitle = "Call Number"
+	allBranches     = "all branches"
+)
+
+// The shelf list and a title's copies live under Books but keep their own
+// sort state.
+const (
+	routeShelf      route = "Books/shelf"
+	routeBookCopies route = "Books/copies"
+)
+
+type copyItem struct {
+	copy  copydom.Copy
+	title string
+}
+
+var copyFields = query.Fields[copyItem]{
+	"id":       query.TextField(func(c copyItem) []string { return []string{c.copy.ID} }),
+	"barcode":  query.TextField(func(c copyItem) []string { return []string{c.copy.Barcode} }),
+	"title":    query.TextField(func(c copyItem) []string { return []string{c.title} }),
+	"branch":   query.TextField(func(c copyItem) []string { return []string{c.copy.Branch, c.copy.At()} }),
+	"location": query.TextField(func(c copyItem) []string { return []string{c.copy.Location} }),
+	"call":     query.TextField(func(c copyItem) []string { return []string{c.copy.CallNumber} }),
+	"status":   query.TextField(func(c copyItem) []string { return []string{string(c.copy.Status)} }),
+}
+
+func copyText(c copyItem) []string {
+	return []string{c.copy.ID, c.copy.Barcode, c.title, c.copy.Branch, c.copy.Location, c.copy.CallNumber, string(c.copy.Status)}
+}
+
+// booksView is the route used for sorting: the titles list, the shelf list
+// or the copies of one title.
+func (m Model) booksView() route {
+	switch {
+	case m.bookDetail != "":
+		return routeBookCopies
+	case m.shelfList:
+		return routeShelf
+	default:
+		return routeBooks
+	}
+}
+
+func (m Model) sortRoute() route {
+	switch m.route {
+	case routeBooks:
+		return m.booksView()
+	case routeMembers:
+		return m.membersView()
+	default:
+		return m.route
+	}
+}
+
+func (m Model) booksTabLabel() string {
+	switch m.booksView() {
+	case routeShelf:
+		return string(routeBooks) + " (shelf)"
+	case routeBookCopies:
+		return string(routeBooks) + " (copies)"
+	default:
+		return string(routeBooks)
+	}
+}
+
+// branchScoped reports whether the branch scope applies to r.
+func branchScoped(r route) bool {
+	switch r {
+	case routeDashboard, routeBooks, routeLoans, routeReports:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Model) openBookDetail() tea.Cmd {
+	id := m.selectedID()
+	if id == "" {
+		return m.setStatus("Select a book first", statusInfo)
+	}
+
+	b, err := m.services.Books.GetByID(m.ctx, id)
+	if err != nil {
+		return m.setStatus(statusErrorPrefix+err.Error(), statusInfo)
+	}
+
+	m.bookDetail = b.ID
+	m.refreshRouteData()
+	return m.setStatus("Copies of "+b.Title+" (esc to go back)", statusInfo)
+}
+
+// inBranch keeps copies owned by or currently at the scoped branch.
+func (m Model) inBranch(c copydom.Copy) bool {
+	return m.branch == "" || c.Branch == m.branch || c.At() == m.branch
+}
+
+// cycleBranch moves the branch scope through every registered branch and
+// every branch that holds a copy, then back to all branches.
+func (m *Model) cycleBranch() {
+	seen := map[string]bool{}
+	var branches []string
+	add := func(code string) {
+		if code != "" && !seen[code] {
+			seen[code] = true
+			branches = append(branches, code)
+		}
+	}
+	registered, _ := m.services.Branches.List(m.ctx)
+	for _, b := range registered {
+		add(b.Code)
+	}
+	copies, _ := m.services.Copies.List(m.ctx)
+	for _, c := range copies {
+		add(c.Branch)
+		add(c.At())
+	}
+	sort.Strings(branches)
+
+	next := ""
+	for i, b := range branches {
+		if b == m.branch {
+			if i+1 < len(branches) {
+				next = branches[i+1]
+			}
+			m.branch = next
+			return
+		}
+	}
+	if m.branch == "" && len(branches) > 0 {
+		next = branches[0]
+	}
+	m.branch = next
+}
+
+func (m Model) branchLabel() string {
+	if m.branch == "" {
+		return allBranches
+	}
+	return m.branch
+}
+
+func (m Model) copyItems(bookID string) []copyItem {
+	copies, _ := m.services.Copies.List(m.ctx)
+	books, _ := m.services.Books.List(m.ctx)
+	titles := make(map[string]string, len(books))
+	for _, b := range books {
+		titles[b.ID] = b.Title
+	}
+
+	out := make([]copyItem, 0, len(copies))
+	for _, c := range copies {
+		if !m.inBranch(c) || (bookID != "" && c.BookID != bookID) {
+			continue
+		}
+		out = append(out, copyItem{copy: c, title: titles[c.BookID]})
+	}
+	return out
+}
+
+func (m Model) shelfTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "Copy ID", Width: 12}, {Title: callNumberTitle, Width: 16}, {Title: "Title", Width: 20}, {Title: "Barcode", Width: 12}, {Title: "Branch", Width: 10}, {Title: "Location", Width: 12}, {Title: "Status", Width: 10}}
+
+	items := m.copyItems("")
+	matched, err := filterItems(items, q, copyFields, copyText)
+
+	rows := make([]table.Row, 0, len(matched))
+	for _, it := range matched {
+		c := it.copy
+		rows = append(rows, table.Row{c.ID, c.CallNumber, it.title, c.Barcode, c.At(), c.Location, string(c.Status)})
+	}
+
+	switch {
+	case len(rows) == 0 && len(items) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "No copies in " + m.branchLabel(), "Press v for titles", "", "", "", ""}}
+	}
+
+	return cols, rows, err
+}
+
+func (m Model) bookCopiesTable(q query.Query) ([]table.Column, []table.Row, error) {
+	cols := []table.Column{{Title: "Copy ID", Width: 12}, {Title: callNumberTitle, Width: 16}, {Title: "Barcode", Width: 12}, {Title: "Branch", Width: 10}, {Title: "Location", Width: 12}, {Title: "Status", Width: 10}, {Title: "Condition", Width: 16}}
+
+	items := m.copyItems(m.bookDetail)
+	matched, err := filterItems(items, q, copyFields, copyText)
+
+	rows := make([]table.Row, 0, len(matched))
+	for _, it := range matched {
+		c := it.copy
+		rows = append(rows, table.Row{c.ID, c.CallNumber, c.Barcode, c.Branch, c.Location, string(c.Status), c.ConditionNote})
+	}
+
+	switch {
+	case len(rows) == 0 && len(items) > 0:
+		rows = []table.Row{noMatchesRow(len(cols))}
+	case len(rows) == 0:
+		rows = []table.Row{{"-", "No copies in " + m.branchLabel(), "Press c to add", "", "", "", ""}}
+	}
+
+	return cols, rows, err
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
+)
+
+func TestShelfListIsBranchScopedAndInCallNumberOrder(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	b, err := model.services.Books.Create(model.ctx, dto.CreateBookInput{Title: "Go", Authors: []string{"Alan Donovan"}})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	for _, in := range []dto.CreateCopyInput{
+		{ID: "c-1", BookID: b.ID, Branch: "Main", CallNumber: "QA76.9 .D3"},
+		{ID: "c-2", BookID: b.ID, Branch: "Main", CallNumber: "QA76.73 .G63"},
+		{ID: "c-3", BookID: b.ID, Branch: "East", CallNumber: "QA76.8 .A1"},
+	} {
+		if _, err := model.services.Copies.Create(model.ctx, in); err != nil {
+			t.Fatalf("create copy: %v", err)
+		}
+	}
+
+	model.route = routeBooks
+	next, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	model = next.(Model)
+	assertFirstColumn(t, model, "c-2", "c-3", "c-1")
+
+	next, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	model = next.(Model)
+	if model.branch != "EAST" {
+		t.Fatalf("expected the first branch to be EAST got %q", model.branch)
+	}
+	next, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	model = next.(Model)
+	assertFirstColumn(t, model, "c-2", "c-1")
+	if got := model.booksTabLabel(); got != "Books (shelf)" || model.branch != "MAIN" {
+		t.Fatalf("unexpected tab label %q", got)
+	}
+
+	next, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	model = next.(Model)
+	next, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = next.(Model)
+	if model.bookDetail != b.ID {
+		t.Fatalf("expected enter to open the copies of %s", b.ID)
+	}
+	assertFirstColumn(t, model, "c-2", "c-1")
+
+	next, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = next.(Model)
+	if model.bookDetail != "" || model.booksView() != routeBooks {
+		t.Fatalf("expected esc to return to the titles list")
+	}
+}
+
+func assertFirstColumn(t *testing.T, model Model, want ...string) {
+	t.Helper()
+
+	rows := model.table.Rows()
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows got %v", len(want), rows)
+	}
+	for i, id := range want {
+		if rows[i][0] != id {
+			t.Fatalf("row %d: expected %s got %v", i, id, rows)
+		}
+	}
+}
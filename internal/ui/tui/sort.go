@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/mibienpanjoe/LMS-bit/internal/app/query"
+	copydom "github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
+)
+
+const (
+	sortAscIndicator  = " ▲"
+	sortDescIndicator = " ▼"
+	sortDateLayout    = "2006-01-02"
+)
+
+type sortState struct {
+	column int
+	desc   bool
+}
+
+type columnKind int
+
+const (
+	columnText columnKind = iota
+	columnNumber
+	columnDate
+	columnCallNumber
+)
+
+func defaultSort(r route) (sortState, bool) {
+	switch r {
+	case routeBooks:
+		return sortState{column: 1}, true
+	case routeMembers:
+		return sortState{column: 1}, true
+	case routeLoans:
+		return sortState{column: 3}, true
+	case routeShelf, routeBookCopies:
+		return sortState{column: 1}, true
+	default:
+		return sortState{}, false
+	}
+}
+
+func (m Model) sortFor(r route) (sortState, bool) {
+	def, ok := defaultSort(r)
+	if !ok {
+		return sortState{}, false
+	}
+
+	if s, ok := m.sorts[r]; ok {
+		return s, true
+	}
+
+	return def, true
+}
+
+func (m Model) rankedByRelevance(q query.Query) bool {
+	if m.sortRoute() != routeBooks || q.FreeText() == "" {
+		return false
+	}
+
+	_, explicit := m.sorts[m.sortRoute()]
+	return !explicit
+}
+
+func (m *Model) cycleSortColumn() (sortState, bool) {
+	s, ok := m.sortFor(m.sortRoute())
+	if !ok {
+		return sortState{}, false
+	}
+
+	cols := len(m.table.Columns())
+	if cols == 0 {
+		return s, true
+	}
+
+	s.column = (s.column + 1) % cols
+	s.desc = false
+	m.sorts[m.sortRoute()] = s
+	return s, true
+}
+
+func (m *Model) toggleSortOrder() (sortState, bool) {
+	s, ok := m.sortFor(m.sortRoute())
+	if !ok {
+		return sortState{}, false
+	}
+
+	s.desc = !s.desc
+	m.sorts[m.sortRoute()] = s
+	return s, true
+}
+
+func sortRows(rows []table.Row, s sortState) {
+	sortRowsAs(rows, s, detectColumnKind(rows, s.column))
+}
+
+// sortTable is sortRows with call number columns, recognised by title, kept
+// in shelf order.
+func sortTable(cols []table.Column, rows []table.Row, s sortState) {
+	if s.column >= 0 && s.column < len(cols) && cols[s.column].Title == callNumberTitle {
+		sortRowsAs(rows, s, columnCallNumber)
+		return
+	}
+	sortRows(rows, s)
+}
+
+func sortRowsAs(rows []table.Row, s sortState, kind columnKind) {
+	if len(rows) < 2 {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		c := compareCells(cell(rows[i], s.column), cell(rows[j], s.column), kind)
+		if c == 0 {
+			c = strings.Compare(cell(rows[i], 0), cell(rows[j], 0))
+		}
+		if s.desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func applySortIndicator(cols []table.Column, s sortState) []table.Column {
+	if s.column < 0 || s.column >= len(cols) {
+		return cols
+	}
+
+	out := make([]table.Column, len(cols))
+	copy(out, cols)
+
+	indicator := sortAscIndicator
+	if s.desc {
+		indicator = sortDescIndicator
+	}
+	out[s.column].Title += indicator
+	return out
+}
+
+func detectColumnKind(rows []table.Row, col int) columnKind {
+	numbers, dates, seen := true, true, 0
+	for _, r := range rows {
+		v := strings.TrimSpace(cell(r, col))
+		if v == "" {
+			continue
+		}
+		seen++
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			numbers = false
+		}
+		if _, err := time.Parse(sortDateLayout, v); err != nil {
+			dates = false
+		}
+	}
+
+	switch {
+	case seen == 0:
+		return columnText
+	case numbers:
+		return columnNumber
+	case dates:
+		return columnDate
+	default:
+		return columnText
+	}
+}
+
+func compareCells(a, b string, kind columnKind) int {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == "" || b == "" {
+		return compareEmpty(a, b)
+	}
+
+	switch kind {
+	case columnNumber:
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		return cmp.Compare(x, y)
+	case columnDate:
+		x, _ := time.Parse(sortDateLayout, a)
+		y, _ := time.Parse(sortDateLayout, b)
+		return x.Compare(y)
+	case columnCallNumber:
+		return copydom.CompareCallNumbers(a, b)
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+func compareEmpty(a, b string) int {
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func cell(r table.Row, col int) string {
+	if col < 0 || col >= len(r) {
+		return ""
+	}
+	return r[col]
+}
+
+func sortLabel(cols []table.Column, s sortState) string {
+	name := ""
+	if s.column >= 0 && s.column < len(cols) {
+		name = strings.TrimSuffix(strings.TrimSuffix(cols[s.column].Title, sortAscIndicator), sortDescIndicator)
+	}
+
+	dir := "asc"
+	if s.desc {
+		dir = "desc"
+	}
+	return name + " (" + dir + ")"
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestSortRowsIsTypeAware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rows  []table.Row
+		state sortState
+		want  []string
+	}{
+		{
+			name:  "numbers compare numerically",
+			rows:  []table.Row{{"a", "10"}, {"b", "9"}, {"c", "100"}},
+			state: sortState{column: 1},
+			want:  []string{"b", "a", "c"},
+		},
+		{
+			name:  "text is case-insensitive",
+			rows:  []table.Row{{"a", "beta"}, {"b", "Alpha"}, {"c", "gamma"}},
+			state: sortState{column: 1},
+			want:  []string{"b", "a", "c"},
+		},
+		{
+			name:  "dates descending",
+			rows:  []table.Row{{"a", "2026-01-02"}, {"b", "2026-03-01"}, {"c", "2025-12-31"}},
+			state: sortState{column: 1, desc: true},
+			want:  []string{"b", "a", "c"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sortRows(tc.rows, tc.state)
+			for i, id := range tc.want {
+				if tc.rows[i][0] != id {
+					t.Fatalf("position %d: expected %s got %s", i, id, tc.rows[i][0])
+				}
+			}
+		})
+	}
+}
+
+func TestSortStateRememberedPerRoute(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel(t)
+	model.route = routeMembers
+	model.refreshRouteData()
+
+	if _, ok := model.cycleSortColumn(); !ok {
+		t.Fatalf("expected members route to be sortable")
+	}
+	model.route = routeBooks
+	model.refreshRouteData()
+	if _, ok := model.toggleSortOrder(); !ok {
+		t.Fatalf("expected books route to be sortable")
+	}
+
+	members, _ := model.sortFor(routeMembers)
+	if members.column != 2 || members.desc {
+		t.Fatalf("unexpected members sort state %+v", members)
+	}
+
+	books, _ := model.sortFor(routeBooks)
+	if books.column != 1 || !books.desc {
+		t.Fatalf("unexpected books sort state %+v", books)
+	}
+
+	if _, ok := model.sortFor(routeSettings); ok {
+		t.Fatalf("expected settings route to be unsortable")
+	}
+}
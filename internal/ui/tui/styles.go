@@ -9,12 +9,18 @@ type styles struct {
 	ActiveTab      lipgloss.Style
 	Body           lipgloss.Style
 	SearchLabel    lipgloss.Style
+	SearchError    lipgloss.Style
 	Footer         lipgloss.Style
 	StatusInfo     lipgloss.Style
 	StatusSuccess  lipgloss.Style
 	ConfirmBox     lipgloss.Style
 	ConfirmTitle   lipgloss.Style
 	TooSmallScreen lipgloss.Style
+	Card           lipgloss.Style
+	CardLabel      lipgloss.Style
+	CardValue      lipgloss.Style
+	Spark          lipgloss.Style
+	Trends         lipgloss.Style
 }
 
 func newStyles() styles {
@@ -40,6 +46,8 @@ func newStyles() styles {
 			Padding(1, 1),
 		SearchLabel: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("110")),
+		SearchError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("209")),
 		Footer: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("245")).
 			Padding(0, 1),
@@ -59,5 +67,19 @@ func newStyles() styles {
 		TooSmallScreen: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("216")).
 			Padding(1, 2),
+		Card: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(borderColor).
+			PaddingLeft(1).
+			Width(15),
+		CardLabel: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245")),
+		CardValue: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("230")),
+		Spark: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("110")),
+		Trends: lipgloss.NewStyle().
+			Padding(0, 1),
 	}
 }
@@ -9,8 +9,10 @@ import (
 
 	"github.com/mibienpanjoe/LMS-bit/internal/app/dto"
 	"github.com/mibienpanjoe/LMS-bit/internal/app/usecase"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/book"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/copy"
 	"github.com/mibienpanjoe/LMS-bit/internal/domain/loan"
+	"github.com/mibienpanjoe/LMS-bit/internal/domain/member"
 	jsonstore "github.com/mibienpanjoe/LMS-bit/internal/infra/storage/json"
 )
 
@@ -113,6 +115,9 @@ func TestOverdueAndPersistenceAfterReopen(t *testing.T) {
 		t.Fatalf("expected 1 overdue loan got %d", len(overdue))
 	}
 
+	if err := services.store.Close(); err != nil {
+		t.Fatalf("close store: %v", err)
+	}
 	reopened := newServices(t, storePath, clock, ids, loan.Policy{
 		LoanDays:          1,
 		MaxLoansPerMember: 3,
@@ -137,6 +142,7 @@ func TestOverdueAndPersistenceAfterReopen(t *testing.T) {
 }
 
 type services struct {
+	store   *jsonstore.Store
 	books   usecase.BookService
 	copies  usecase.CopyService
 	members usecase.MemberService
@@ -150,6 +156,7 @@ func newServices(t *testing.T, path string, clock *testClock, ids *seqID, policy
 	if err != nil {
 		t.Fatalf("open store: %v", err)
 	}
+	t.Cleanup(func() { store.Close() })
 
 	bookRepo := jsonstore.NewBookRepository(store)
 	copyRepo := jsonstore.NewCopyRepository(store)
@@ -157,10 +164,11 @@ func newServices(t *testing.T, path string, clock *testClock, ids *seqID, policy
 	loanRepo := jsonstore.NewLoanRepository(store)
 
 	return services{
-		books:   usecase.NewBookService(bookRepo, ids),
-		copies:  usecase.NewCopyService(copyRepo, ids),
-		members: usecase.NewMemberService(memberRepo, ids, clock),
-		loans:   usecase.NewLoanService(loanRepo, copyRepo, memberRepo, ids, clock, policy),
+		store:   store,
+		books:   usecase.NewBookService(bookRepo, ids, clock, book.Policy{UniqueISBN: true}, nil),
+		copies:  usecase.NewCopyService(copyRepo, nil, ids, clock, copy.Policy{}, nil),
+		members: usecase.NewMemberService(memberRepo, nil, ids, clock, member.Policy{}, nil),
+		loans:   usecase.NewLoanService(loanRepo, copyRepo, memberRepo, ids, clock, policy, nil),
 	}
 }
 